@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/config"
+	importerModel "github.com/ananaslegend/news-crud/internal/importer/model"
+	importerService "github.com/ananaslegend/news-crud/internal/importer/service"
+	userRepository "github.com/ananaslegend/news-crud/internal/user/repository"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runImport imports posts from an export file, the same as POST
+// /admin/imports but without the size limit. The report is printed as JSON.
+func runImport(ctx context.Context, cfg *config.AppConfig, logger *slog.Logger, args []string) error {
+	flags := newFlagSet("import", "[flags] <file>")
+	format := flags.String("format", "", "ndjson, csv or wxr, taken from the file extension by default")
+	source := flags.String("source", "", "system the posts come from, wordpress for wxr by default")
+	defaultAuthor := flags.Int("default-author", 0, "user id for posts whose author is not mapped")
+	actorID := flags.Int("actor-id", 0, "user id recorded in the audit log as the importer")
+	batchSize := flags.Int("batch-size", cfg.Import.BatchSize, "posts stored per transaction")
+	var authors stringList
+	flags.Var(&authors, "author", "maps an author of the export to a user as name=userID, may be repeated")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return badUsage(flags, "import needs one file")
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = formatOfFile(path)
+	}
+	if *source == "" {
+		*source = importerModel.DefaultSource(*format)
+	}
+
+	authorIDs, err := importerModel.ParseAuthors(authors)
+	if err != nil {
+		return badUsage(flags, "%v", err)
+	}
+
+	opts := importerModel.Options{
+		Format:          *format,
+		Source:          *source,
+		Authors:         authorIDs,
+		DefaultAuthorID: *defaultAuthor,
+		ActorID:         *actorID,
+		BatchSize:       *batchSize,
+	}
+	if err = opts.Validation(); err != nil {
+		return badUsage(flags, "%v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	importSrv := importerService.NewImportService(
+		logger,
+		newPostService(cfg, logger, db),
+		userRepository.NewUserRepository(db),
+	)
+
+	report, importErr := importSrv.Import(ctx, file, opts)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		return err
+	}
+
+	if importErr != nil {
+		return fmt.Errorf("import stopped, run it again to go on: %w", importErr)
+	}
+	return nil
+}
+
+func formatOfFile(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return importerModel.FormatNDJSON
+	case ".csv":
+		return importerModel.FormatCSV
+	case ".xml", ".wxr":
+		return importerModel.FormatWXR
+	default:
+		return ""
+	}
+}
@@ -1,21 +1,37 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/ananaslegend/news-crud/internal/config"
-	"github.com/ananaslegend/news-crud/internal/middleware"
-	permissionRepository "github.com/ananaslegend/news-crud/internal/permission/repository"
-	permissionService "github.com/ananaslegend/news-crud/internal/permission/service"
-	postHandler "github.com/ananaslegend/news-crud/internal/post/handler"
-	postRepository "github.com/ananaslegend/news-crud/internal/post/repository"
-	postService "github.com/ananaslegend/news-crud/internal/post/service"
 	"github.com/ananaslegend/news-crud/pkg/logs"
 	_ "github.com/lib/pq"
 	"log"
-	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
+const usage = `Usage: app [command] [args]
+
+Commands:
+  serve                  run the HTTP and gRPC servers, the default
+  migrate up             apply the pending migrations
+  migrate down [-steps]  revert the last migrations, one by default
+  migrate status         list the migrations and whether they are applied
+  seed                   create sample users and posts in a development database
+  import <file>          import posts from a JSON Lines, CSV or WordPress export
+  token mint             sign an access token for a user
+
+The configuration is read from the environment and .env as for serve.
+`
+
+// errUsage is returned for a bad command line, the usage was printed.
+var errUsage = errors.New("usage")
+
 func main() {
 	cfg, err := config.NewConfig()
 	if err != nil {
@@ -24,50 +40,70 @@ func main() {
 
 	logger := logs.SetUpLogger(*cfg)
 
-	db, err := sql.Open("postgres", cfg.DBConn)
-	if err != nil {
-		logger.Error("cant connect to database", logs.Err(err))
-		os.Exit(1)
+	args := os.Args[1:]
+	if len(args) == 0 || args[0] == "serve" {
+		serve(cfg, logger)
+		return
 	}
-	defer db.Close()
 
-	// todo graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	permissionRepo := permissionRepository.NewPermissionRepository(db)
-	permissionSrv := permissionService.NewPermissionService(permissionRepo)
+	switch args[0] {
+	case "migrate":
+		err = runMigrate(ctx, cfg, args[1:])
+	case "seed":
+		err = runSeed(ctx, cfg, logger, args[1:])
+	case "import":
+		err = runImport(ctx, cfg, logger, args[1:])
+	case "token":
+		err = runToken(cfg, args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		err = errUsage
+	}
 
-	postRepo := postRepository.NewPostRepository(db)
-	postSrv := postService.NewPostService(
-		logger,
-		postRepo,
-		postRepo,
-		postRepo,
-		postRepo,
-		postRepo,
-		permissionSrv,
-		permissionSrv,
-	)
-	postHdl := postHandler.NewPostHandler(
-		logger,
-		postSrv,
-		postSrv,
-		postSrv,
-		postSrv,
-		postSrv,
-	)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
 
-	mux := http.NewServeMux()
+func openDB(cfg *config.AppConfig) (*sql.DB, error) {
+	return sql.Open("postgres", cfg.DBConn)
+}
 
-	mux.HandleFunc("POST /posts", middleware.Auth(cfg.Secret, postHdl.CreatePost))
-	mux.HandleFunc("GET /posts", postHdl.GetPostByFilter)
-	mux.HandleFunc("GET /posts/{id}", postHdl.GetPostByID)
-	mux.HandleFunc("PUT /posts/{id}", middleware.Auth(cfg.Secret, postHdl.UpdatePostByID))
-	mux.HandleFunc("DELETE /posts/{id}", middleware.Auth(cfg.Secret, postHdl.DeletePost))
+// newFlagSet creates the flags of a command, usage describes its arguments.
+func newFlagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: app %s %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
 
-	s := http.Server{
-		Addr:    cfg.HttpPort,
-		Handler: mux, // todo recover middleware
+// parseFlags parses the flags of a command, a bad command line is reported
+// as errUsage as the flag package already printed what went wrong.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
 	}
+	return err
+}
 
-	s.ListenAndServe()
+// badUsage prints what is wrong with the arguments of a command and its
+// usage.
+func badUsage(flags *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(flags.Output(), format+"\n", args...)
+	flags.Usage()
+	return errUsage
 }
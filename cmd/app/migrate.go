@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/config"
+	"github.com/ananaslegend/news-crud/migrations"
+	"github.com/ananaslegend/news-crud/pkg/migrate"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// runMigrate applies, reverts or lists the migrations embedded in the binary.
+func runMigrate(ctx context.Context, cfg *config.AppConfig, args []string) error {
+	flags := newFlagSet("migrate", "up | down [-steps n] | status")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return badUsage(flags, "migrate needs a subcommand")
+	}
+
+	sub, args := flags.Arg(0), flags.Args()[1:]
+	switch sub {
+	case "up", "down", "status":
+	default:
+		return badUsage(flags, "unknown command migrate %s", sub)
+	}
+
+	subFlags := newFlagSet("migrate "+sub, "[flags]")
+	steps := 1
+	if sub == "down" {
+		subFlags.IntVar(&steps, "steps", 1, "number of migrations to revert")
+	}
+	if err := parseFlags(subFlags, args); err != nil {
+		return err
+	}
+	if subFlags.NArg() > 0 {
+		return badUsage(subFlags, "unexpected arguments %v", subFlags.Args())
+	}
+	if steps < 1 {
+		return badUsage(subFlags, "-steps should be at least 1")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "up":
+		done, err := migrator.Up(ctx)
+		printMigrations("applied", done)
+		if err == nil && len(done) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		done, err := migrator.Down(ctx, steps)
+		printMigrations("reverted", done)
+		if err == nil && len(done) == 0 {
+			fmt.Println("no applied migrations")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.Applied {
+				applied = s.AppliedAt.Format(time.DateTime)
+			}
+			if s.Modified {
+				applied += " (modified since)"
+			}
+			fmt.Fprintf(tw, "%03d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return tw.Flush()
+	}
+
+	return nil
+}
+
+func printMigrations(action string, done []migrate.Migration) {
+	for _, m := range done {
+		fmt.Printf("%s %03d_%s\n", action, m.Version, m.Name)
+	}
+}
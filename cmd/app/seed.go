@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/config"
+	seedModel "github.com/ananaslegend/news-crud/internal/seed/model"
+	seedService "github.com/ananaslegend/news-crud/internal/seed/service"
+	userRepository "github.com/ananaslegend/news-crud/internal/user/repository"
+	"log/slog"
+)
+
+// runSeed fills a development database with sample users and posts.
+func runSeed(ctx context.Context, cfg *config.AppConfig, logger *slog.Logger, args []string) error {
+	flags := newFlagSet("seed", "")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return badUsage(flags, "unexpected arguments %v", flags.Args())
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	postSrv := newPostService(cfg, logger, db)
+
+	seedSrv := seedService.NewSeedService(logger, userRepository.NewUserRepository(db), postSrv)
+
+	res, err := seedSrv.Seed(ctx, seedModel.Fixtures)
+	fmt.Printf("created %d users and %d posts\n", res.Users, res.Posts)
+	return err
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"context"
+	apiKeyHandler "github.com/ananaslegend/news-crud/internal/apikey/handler"
+	apiKeyModel "github.com/ananaslegend/news-crud/internal/apikey/model"
+	apiKeyRepository "github.com/ananaslegend/news-crud/internal/apikey/repository"
+	apiKeyService "github.com/ananaslegend/news-crud/internal/apikey/service"
+	auditHandler "github.com/ananaslegend/news-crud/internal/audit/handler"
+	auditRepository "github.com/ananaslegend/news-crud/internal/audit/repository"
+	auditService "github.com/ananaslegend/news-crud/internal/audit/service"
+	authHandler "github.com/ananaslegend/news-crud/internal/auth/handler"
+	authModel "github.com/ananaslegend/news-crud/internal/auth/model"
+	authRepository "github.com/ananaslegend/news-crud/internal/auth/repository"
+	authService "github.com/ananaslegend/news-crud/internal/auth/service"
+	commentHandler "github.com/ananaslegend/news-crud/internal/comment/handler"
+	commentRepository "github.com/ananaslegend/news-crud/internal/comment/repository"
+	commentService "github.com/ananaslegend/news-crud/internal/comment/service"
+	"github.com/ananaslegend/news-crud/internal/config"
+	"github.com/ananaslegend/news-crud/internal/docs"
+	docsHandler "github.com/ananaslegend/news-crud/internal/docs/handler"
+	exportHandler "github.com/ananaslegend/news-crud/internal/export/handler"
+	exportService "github.com/ananaslegend/news-crud/internal/export/service"
+	feedHandler "github.com/ananaslegend/news-crud/internal/feed/handler"
+	feedService "github.com/ananaslegend/news-crud/internal/feed/service"
+	graphHandler "github.com/ananaslegend/news-crud/internal/graph/handler"
+	graphModel "github.com/ananaslegend/news-crud/internal/graph/model"
+	graphService "github.com/ananaslegend/news-crud/internal/graph/service"
+	importerHandler "github.com/ananaslegend/news-crud/internal/importer/handler"
+	importerService "github.com/ananaslegend/news-crud/internal/importer/service"
+	mediaHandler "github.com/ananaslegend/news-crud/internal/media/handler"
+	mediaModel "github.com/ananaslegend/news-crud/internal/media/model"
+	mediaRepository "github.com/ananaslegend/news-crud/internal/media/repository"
+	mediaService "github.com/ananaslegend/news-crud/internal/media/service"
+	"github.com/ananaslegend/news-crud/internal/middleware"
+	permissionRepository "github.com/ananaslegend/news-crud/internal/permission/repository"
+	permissionService "github.com/ananaslegend/news-crud/internal/permission/service"
+	postHandler "github.com/ananaslegend/news-crud/internal/post/handler"
+	postRepository "github.com/ananaslegend/news-crud/internal/post/repository"
+	postRPC "github.com/ananaslegend/news-crud/internal/post/rpc"
+	postService "github.com/ananaslegend/news-crud/internal/post/service"
+	reactionHandler "github.com/ananaslegend/news-crud/internal/reaction/handler"
+	reactionModel "github.com/ananaslegend/news-crud/internal/reaction/model"
+	reactionRepository "github.com/ananaslegend/news-crud/internal/reaction/repository"
+	reactionService "github.com/ananaslegend/news-crud/internal/reaction/service"
+	sitemapHandler "github.com/ananaslegend/news-crud/internal/sitemap/handler"
+	sitemapService "github.com/ananaslegend/news-crud/internal/sitemap/service"
+	userRepository "github.com/ananaslegend/news-crud/internal/user/repository"
+	viewHandler "github.com/ananaslegend/news-crud/internal/view/handler"
+	viewModel "github.com/ananaslegend/news-crud/internal/view/model"
+	viewRepository "github.com/ananaslegend/news-crud/internal/view/repository"
+	viewService "github.com/ananaslegend/news-crud/internal/view/service"
+	"github.com/ananaslegend/news-crud/migrations"
+	postv1 "github.com/ananaslegend/news-crud/pkg/api/post/v1"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/migrate"
+	"github.com/ananaslegend/news-crud/pkg/oidc"
+	"github.com/ananaslegend/news-crud/pkg/openapi"
+	"github.com/ananaslegend/news-crud/pkg/ratelimit"
+	"github.com/ananaslegend/news-crud/pkg/storage"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// serve runs the HTTP and gRPC servers until SIGINT, SIGTERM or an error of
+// the HTTP server. The servers then finish the calls in flight and the
+// workers write what they buffered. It exits the process when a dependency
+// can not be set up.
+func serve(cfg *config.AppConfig, logger *slog.Logger) {
+	db, err := openDB(cfg)
+	if err != nil {
+		logger.Error("cant connect to database", logs.Err(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if cfg.MigrateOnStart {
+		migrator, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			logger.Error("cant load migrations", logs.Err(err))
+			os.Exit(1)
+		}
+
+		applied, err := migrator.Up(context.Background())
+		for _, m := range applied {
+			logger.Info("applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+		}
+		if err != nil {
+			logger.Error("cant migrate database", logs.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	apiKeyRepo := apiKeyRepository.NewAPIKeyRepository(db)
+	apiKeySrv := apiKeyService.NewAPIKeyService(
+		logger,
+		apiKeyRepo,
+		apiKeyRepo,
+		apiKeyRepo,
+		apiKeyRepo,
+		apiKeyRepo,
+	)
+	apiKeyHdl := apiKeyHandler.NewAPIKeyHandler(
+		logger,
+		apiKeySrv,
+		apiKeySrv,
+		apiKeySrv,
+	)
+
+	auditRepo := auditRepository.NewAuditRepository(db)
+	auditSrv := auditService.NewAuditService(logger, auditRepo, auditRepo)
+	auditHdl := auditHandler.NewAuditHandler(logger, auditSrv)
+
+	permissionRepo := permissionRepository.NewPermissionRepository(db)
+	permissionSrv := permissionService.NewPermissionService(permissionRepo)
+
+	viewRepo := viewRepository.NewViewRepository(db)
+	viewSrv := viewService.NewViewService(logger, viewModel.RecorderConfig(cfg.Views), viewRepo, viewRepo)
+	viewHdl := viewHandler.NewViewHandler(logger, viewSrv)
+
+	// workers stop after the servers, so views recorded by the last requests
+	// are still written
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		viewSrv.Run(workersCtx)
+	}()
+
+	postRepo := postRepository.NewPostRepository(db)
+	postSrv := postService.NewPostService(
+		logger,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		permissionSrv,
+		permissionSrv,
+		permissionSrv,
+		auditSrv,
+		viewSrv,
+	)
+	postHdl := postHandler.NewPostHandler(
+		logger,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+	)
+
+	userRepo := userRepository.NewUserRepository(db)
+
+	graphSrv, err := graphService.NewGraphService(
+		logger,
+		graphModel.Limits(cfg.GraphQL),
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		userRepo,
+		postRepo,
+	)
+	if err != nil {
+		logger.Error("cant set up graphql", logs.Err(err))
+		os.Exit(1)
+	}
+	graphHdl := graphHandler.NewGraphHandler(logger, graphSrv)
+
+	importSrv := importerService.NewImportService(logger, postSrv, userRepo)
+	importHdl := importerHandler.NewImportHandler(logger, cfg.Import.MaxSize, cfg.Import.BatchSize, importSrv)
+
+	exportSrv := exportService.NewExportService(logger, postSrv)
+	exportHdl := exportHandler.NewExportHandler(logger, exportSrv)
+
+	feedSrv := feedService.NewFeedService(
+		feedService.Config{
+			BaseURL:      cfg.PublicURL,
+			Title:        cfg.Feed.Title,
+			Description:  cfg.Feed.Description,
+			ItemLimit:    cfg.Feed.ItemLimit,
+			MaxItemLimit: cfg.Feed.MaxItemLimit,
+		},
+		postRepo,
+	)
+	feedHdl := feedHandler.NewFeedHandler(logger, feedSrv)
+
+	sitemapSrv := sitemapService.NewSitemapService(
+		sitemapService.Config{
+			BaseURL:         cfg.PublicURL,
+			PublicationName: cfg.Feed.Title,
+			Language:        cfg.NewsLanguage,
+		},
+		postRepo,
+		postRepo,
+		postRepo,
+	)
+	sitemapHdl := sitemapHandler.NewSitemapHandler(logger, sitemapSrv)
+
+	var mediaStorage storage.Storage
+	switch cfg.Media.Storage {
+	case "s3":
+		mediaStorage, err = storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.Media.S3Endpoint,
+			Region:    cfg.Media.S3Region,
+			Bucket:    cfg.Media.S3Bucket,
+			AccessKey: cfg.Media.S3AccessKey,
+			SecretKey: cfg.Media.S3SecretKey,
+		}, http.DefaultClient)
+	default:
+		mediaStorage, err = storage.NewLocal(cfg.Media.LocalDir)
+	}
+	if err != nil {
+		logger.Error("cant set up media storage", logs.Err(err))
+		os.Exit(1)
+	}
+
+	mediaRepo := mediaRepository.NewMediaRepository(db)
+
+	imageSrv, err := mediaService.NewImageService(logger, mediaModel.ProcessingConfig(cfg.Media.Images), mediaStorage, mediaRepo, mediaRepo, mediaRepo)
+	if err != nil {
+		logger.Error("cant set up image processing", logs.Err(err))
+		os.Exit(1)
+	}
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		imageSrv.Run(workersCtx)
+	}()
+
+	mediaSrv := mediaService.NewMediaService(
+		logger,
+		mediaService.Config{
+			MaxSize:      cfg.Media.MaxSize,
+			MaxPixels:    cfg.Media.Images.MaxPixels,
+			AllowedTypes: cfg.Media.AllowedTypes,
+		},
+		mediaStorage,
+		mediaRepo,
+		mediaRepo,
+		mediaRepo,
+		mediaRepo,
+		permissionSrv,
+		imageSrv,
+	)
+	mediaHdl := mediaHandler.NewMediaHandler(logger, cfg.Media.MaxSize, mediaSrv, mediaSrv, mediaSrv)
+
+	commentRepo := commentRepository.NewCommentRepository(db)
+	commentSrv := commentService.NewCommentService(
+		logger,
+		commentRepo,
+		commentRepo,
+		commentRepo,
+		commentRepo,
+		commentRepo,
+		commentRepo,
+		commentRepo,
+	)
+	commentHdl := commentHandler.NewCommentHandler(
+		logger,
+		commentSrv,
+		commentSrv,
+		commentSrv,
+		commentSrv,
+		commentSrv,
+		commentSrv,
+	)
+
+	reactionRepo := reactionRepository.NewReactionRepository(db)
+	reactionSrv := reactionService.NewReactionService(
+		reactionModel.NewSet(cfg.Reactions),
+		reactionRepo,
+		reactionRepo,
+	)
+	reactionHdl := reactionHandler.NewReactionHandler(
+		logger,
+		reactionSrv,
+		reactionSrv,
+		reactionSrv,
+	)
+
+	var rateLimitStore middleware.RateLimitStore = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.RedisAddr != "" {
+		redisClient := redis.NewClient(&redis.Options{Addr: cfg.RateLimit.RedisAddr})
+		defer redisClient.Close()
+
+		rateLimitStore = ratelimit.NewRedisStore(redisClient, "news-crud:rate-limit:")
+	}
+	limiter := middleware.NewRateLimiter(logger, rateLimitStore)
+	postsRead, postsWrite := cfg.RateLimit.PostsRead, cfg.RateLimit.PostsWrite
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /posts", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, postHdl.CreatePost))))
+	mux.HandleFunc("POST /posts:batch", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, postHdl.ApplyBatch))))
+	mux.HandleFunc("GET /posts", middleware.OptionalAuth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-read", postsRead,
+			middleware.Scope(apiKeyModel.ScopePostsRead, postHdl.GetPostByFilter))))
+	mux.HandleFunc("GET /posts/{id}", middleware.OptionalAuth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-read", postsRead,
+			middleware.Scope(apiKeyModel.ScopePostsRead, postHdl.GetPostByID))))
+	mux.HandleFunc("PUT /posts/{id}", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, postHdl.UpdatePostByID))))
+	mux.HandleFunc("DELETE /posts/{id}", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, postHdl.DeletePost))))
+	mux.HandleFunc("PUT /posts/{id}/authors", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, postHdl.UpdatePostAuthors))))
+
+	mux.HandleFunc("GET /graphql", middleware.OptionalAuth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-read", postsRead, graphHdl.Query)))
+	mux.HandleFunc("POST /graphql", middleware.OptionalAuth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-read", postsRead, graphHdl.Query)))
+
+	mux.HandleFunc("POST /media", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, mediaHdl.Upload))))
+	mux.HandleFunc("PUT /posts/{id}/media", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, mediaHdl.AttachMedia))))
+	mux.HandleFunc("GET /media/{key...}", limiter.Limit("posts-read", postsRead, mediaHdl.Serve))
+
+	// api keys are managed with a user token only, a key can not mint other keys
+	mux.HandleFunc("POST /api-keys", middleware.Auth(cfg.Secret, nil, apiKeyHdl.CreateAPIKey))
+	mux.HandleFunc("GET /api-keys", middleware.Auth(cfg.Secret, nil, apiKeyHdl.GetAPIKeys))
+	mux.HandleFunc("DELETE /api-keys/{id}", middleware.Auth(cfg.Secret, nil, apiKeyHdl.RevokeAPIKey))
+
+	mux.HandleFunc("GET /posts/trending", middleware.OptionalAuth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-read", postsRead,
+			middleware.Scope(apiKeyModel.ScopePostsRead, viewHdl.GetTrending))))
+
+	mux.HandleFunc("GET /feeds/rss.xml", limiter.Limit("posts-read", postsRead, feedHdl.GetRSS))
+	mux.HandleFunc("GET /feeds/atom.xml", limiter.Limit("posts-read", postsRead, feedHdl.GetAtom))
+	mux.HandleFunc("GET /feeds/authors/{id}/rss.xml", limiter.Limit("posts-read", postsRead, feedHdl.GetAuthorRSS))
+	mux.HandleFunc("GET /feeds/authors/{id}/atom.xml", limiter.Limit("posts-read", postsRead, feedHdl.GetAuthorAtom))
+	mux.HandleFunc("GET /feeds/tags/{tag}/rss.xml", limiter.Limit("posts-read", postsRead, feedHdl.GetTagRSS))
+	mux.HandleFunc("GET /feeds/tags/{tag}/atom.xml", limiter.Limit("posts-read", postsRead, feedHdl.GetTagAtom))
+
+	mux.HandleFunc("GET /sitemap.xml", sitemapHdl.GetIndex)
+	mux.HandleFunc("GET /sitemaps/{name}", sitemapHdl.GetSitemap)
+
+	mux.HandleFunc("GET /reactions", reactionHdl.GetReactions)
+	mux.HandleFunc("PUT /posts/{id}/reactions/{reaction}", middleware.Auth(cfg.Secret, nil,
+		limiter.Limit("posts-write", postsWrite, reactionHdl.AddReaction)))
+	mux.HandleFunc("DELETE /posts/{id}/reactions/{reaction}", middleware.Auth(cfg.Secret, nil,
+		limiter.Limit("posts-write", postsWrite, reactionHdl.RemoveReaction)))
+
+	moderators := []string{authModel.RoleEditor, authModel.RoleAdmin}
+
+	mux.HandleFunc("GET /posts/{id}/comments", middleware.OptionalAuth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-read", postsRead,
+			middleware.Scope(apiKeyModel.ScopePostsRead, commentHdl.GetCommentsByPostID))))
+	mux.HandleFunc("POST /posts/{id}/comments", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, commentHdl.CreateComment))))
+	mux.HandleFunc("PUT /comments/{id}", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, commentHdl.UpdateComment))))
+	mux.HandleFunc("DELETE /comments/{id}", middleware.Auth(cfg.Secret, apiKeySrv,
+		limiter.Limit("posts-write", postsWrite,
+			middleware.Scope(apiKeyModel.ScopePostsWrite, commentHdl.DeleteComment))))
+	// api keys carry no roles, moderation needs a user token
+	mux.HandleFunc("GET /comments/moderation", middleware.Auth(cfg.Secret, nil,
+		middleware.AnyRole(moderators, commentHdl.GetModerationQueue)))
+	mux.HandleFunc("POST /comments/{id}/approve", middleware.Auth(cfg.Secret, nil,
+		middleware.AnyRole(moderators, commentHdl.ApproveComment)))
+	mux.HandleFunc("POST /comments/{id}/reject", middleware.Auth(cfg.Secret, nil,
+		middleware.AnyRole(moderators, commentHdl.RejectComment)))
+
+	mux.HandleFunc("GET /admin/audit", middleware.Auth(cfg.Secret, nil,
+		middleware.Role(authModel.RoleAdmin, auditHdl.GetEntries)))
+	mux.HandleFunc("POST /admin/imports", middleware.Auth(cfg.Secret, nil,
+		middleware.Role(authModel.RoleAdmin, importHdl.Import)))
+	mux.HandleFunc("GET /admin/export", middleware.Auth(cfg.Secret, nil,
+		middleware.Role(authModel.RoleAdmin, exportHdl.Export)))
+
+	spec, err := openapi.Load(docs.OpenAPI)
+	if err != nil {
+		logger.Error("cant load openapi document", logs.Err(err))
+		os.Exit(1)
+	}
+
+	docsHdl := docsHandler.NewDocsHandler(logger, docs.OpenAPI)
+
+	mux.HandleFunc("GET /openapi.json", docsHdl.GetSpec)
+	mux.HandleFunc("GET /docs", docsHdl.GetUI)
+	mux.HandleFunc("GET /docs/{file}", docsHdl.GetAsset)
+
+	if cfg.OIDC.Issuer != "" {
+		provider, err := oidc.Discover(context.Background(), http.DefaultClient, cfg.OIDC.Issuer, oidc.Config{
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+		})
+		if err != nil {
+			logger.Error("cant discover oidc provider", logs.Err(err))
+			os.Exit(1)
+		}
+
+		identityRepo := authRepository.NewIdentityRepository(db)
+		authSrv := authService.NewAuthService(
+			logger,
+			authService.Config{
+				GroupsClaim: cfg.OIDC.GroupsClaim,
+				GroupRoles:  cfg.OIDC.GroupRoles,
+				TokenTTL:    cfg.AccessTokenTTL,
+				Secret:      cfg.Secret,
+			},
+			provider,
+			identityRepo,
+			identityRepo,
+		)
+		authHdl := authHandler.NewAuthHandler(logger, cfg.AccessTokenTTL, authSrv, authSrv)
+
+		mux.HandleFunc("GET /auth/oidc/login", authHdl.Login)
+		mux.HandleFunc("GET /auth/oidc/callback", authHdl.Callback)
+	}
+
+	postRPCServer := postRPC.NewPostServer(
+		logger,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+		postSrv,
+	)
+
+	rpcLimits := map[string]middleware.MethodLimit{
+		postv1.PostService_CreatePost_FullMethodName:  {Route: "posts-write", Rule: postsWrite},
+		postv1.PostService_GetPost_FullMethodName:     {Route: "posts-read", Rule: postsRead},
+		postv1.PostService_ListPosts_FullMethodName:   {Route: "posts-read", Rule: postsRead},
+		postv1.PostService_StreamPosts_FullMethodName: {Route: "posts-read", Rule: postsRead},
+		postv1.PostService_UpdatePost_FullMethodName:  {Route: "posts-write", Rule: postsWrite},
+		postv1.PostService_DeletePost_FullMethodName:  {Route: "posts-write", Rule: postsWrite},
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			middleware.UnaryRequestInfo(),
+			middleware.UnaryAuth(cfg.Secret, apiKeySrv),
+			limiter.UnaryLimit(rpcLimits),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.StreamRequestInfo(),
+			middleware.StreamAuth(cfg.Secret, apiKeySrv),
+			limiter.StreamLimit(rpcLimits),
+		),
+	)
+	postv1.RegisterPostServiceServer(grpcServer, postRPCServer)
+
+	grpcListener, err := net.Listen("tcp", cfg.GrpcPort)
+	if err != nil {
+		logger.Error("cant listen grpc port", logs.Err(err))
+		os.Exit(1)
+	}
+
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Error("grpc server stopped", logs.Err(err))
+		}
+	}()
+
+	s := http.Server{
+		Addr:    cfg.HttpPort,
+		Handler: middleware.RequestInfo(cfg.TrustForwardedFor, middleware.ValidateRequests(spec, mux)), // todo recover middleware
+	}
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.ListenAndServe()
+	}()
+
+	select {
+	case <-signalCtx.Done():
+		logger.Info("shutting down")
+	case err = <-serveErr:
+		logger.Error("http server stopped", logs.Err(err))
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err = s.Shutdown(shutdownCtx); err != nil {
+		logger.Error("cant shut down http server", logs.Err(err))
+	}
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		// streams that are still open are cut off
+		grpcServer.Stop()
+	}
+
+	stopWorkers()
+	workers.Wait()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"database/sql"
+	auditRepository "github.com/ananaslegend/news-crud/internal/audit/repository"
+	auditService "github.com/ananaslegend/news-crud/internal/audit/service"
+	"github.com/ananaslegend/news-crud/internal/config"
+	permissionRepository "github.com/ananaslegend/news-crud/internal/permission/repository"
+	permissionService "github.com/ananaslegend/news-crud/internal/permission/service"
+	postRepository "github.com/ananaslegend/news-crud/internal/post/repository"
+	postService "github.com/ananaslegend/news-crud/internal/post/service"
+	viewModel "github.com/ananaslegend/news-crud/internal/view/model"
+	viewRepository "github.com/ananaslegend/news-crud/internal/view/repository"
+	viewService "github.com/ananaslegend/news-crud/internal/view/service"
+	"log/slog"
+)
+
+// newPostService builds the post service for the commands, so posts they
+// create are rendered and audited like posts created over the API.
+func newPostService(cfg *config.AppConfig, logger *slog.Logger, db *sql.DB) *postService.PostService {
+	auditRepo := auditRepository.NewAuditRepository(db)
+	auditSrv := auditService.NewAuditService(logger, auditRepo, auditRepo)
+
+	permissionRepo := permissionRepository.NewPermissionRepository(db)
+	permissionSrv := permissionService.NewPermissionService(permissionRepo)
+
+	// views are never recorded by the commands, the recorder is not run
+	viewRepo := viewRepository.NewViewRepository(db)
+	viewSrv := viewService.NewViewService(logger, viewModel.RecorderConfig(cfg.Views), viewRepo, viewRepo)
+
+	postRepo := postRepository.NewPostRepository(db)
+	return postService.NewPostService(
+		logger,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		postRepo,
+		permissionSrv,
+		permissionSrv,
+		permissionSrv,
+		auditSrv,
+		viewSrv,
+	)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/config"
+	"github.com/ananaslegend/news-crud/pkg/jwt"
+	"strings"
+)
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runToken signs access tokens with the configured secret, the same tokens
+// the OIDC login hands out.
+func runToken(cfg *config.AppConfig, args []string) error {
+	flags := newFlagSet("token", "mint -user-id <id> [flags]")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 || flags.Arg(0) != "mint" {
+		return badUsage(flags, "token needs the mint subcommand")
+	}
+
+	mint := newFlagSet("token mint", "-user-id <id> [flags]")
+	userID := mint.Int("user-id", 0, "user the token is for")
+	ttl := mint.Duration("ttl", cfg.AccessTokenTTL, "how long the token is valid")
+	var roles stringList
+	mint.Var(&roles, "role", "role of the user, admin or editor, may be repeated")
+	if err := parseFlags(mint, flags.Args()[1:]); err != nil {
+		return err
+	}
+	if *userID <= 0 {
+		return badUsage(mint, "-user-id is required")
+	}
+	if *ttl <= 0 {
+		return badUsage(mint, "-ttl should be positive")
+	}
+
+	if cfg.Secret == "" {
+		return errors.New("SECRET is not set, tokens would be signed with an empty key")
+	}
+
+	token, err := jwt.NewToken(*userID, roles, *ttl, []byte(cfg.Secret))
+	if err != nil {
+		return fmt.Errorf("cant sign token: %w", err)
+	}
+
+	fmt.Println(token)
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v10"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// Config holds the connection settings of newsctl. They are read from the
+// config file, then from the environment, then from the flags, each
+// overriding the previous one.
+type Config struct {
+	Server string `yaml:"server" env:"NEWSCTL_SERVER"`
+	Token  string `yaml:"token" env:"NEWSCTL_TOKEN"`
+	APIKey string `yaml:"api_key" env:"NEWSCTL_API_KEY"`
+	Output string `yaml:"output" env:"NEWSCTL_OUTPUT"`
+}
+
+// configPath is the config file to read: the one given, $NEWSCTL_CONFIG or
+// newsctl/config.yaml in the user config directory. Only the default file
+// may be missing.
+func configPath(path string) (string, bool) {
+	if path != "" {
+		return path, true
+	}
+	if path = os.Getenv("NEWSCTL_CONFIG"); path != "" {
+		return path, true
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, "newsctl", "config.yaml"), false
+}
+
+func loadConfig(path string) (Config, error) {
+	cfg := Config{Server: defaultServer, Output: formatTable}
+
+	path, required := configPath(path)
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && !required:
+		case err != nil:
+			return Config{}, fmt.Errorf("cant read config: %w", err)
+		default:
+			if err = yaml.Unmarshal(data, &cfg); err != nil {
+				return Config{}, fmt.Errorf("cant decode config %s: %w", path, err)
+			}
+		}
+	}
+
+	if err := env.Parse(&cfg); err != nil {
+		return Config{}, fmt.Errorf("cant read environment: %w", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/pkg/client"
+	"github.com/ananaslegend/news-crud/pkg/frontmatter"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// postFile is the front matter of a Markdown file. A post with an id is
+// updated, any other is created.
+type postFile struct {
+	ID      int      `yaml:"id"`
+	Title   string   `yaml:"title"`
+	Format  string   `yaml:"format"`
+	Excerpt string   `yaml:"excerpt"`
+	Tags    []string `yaml:"tags"`
+}
+
+// importPosts creates or updates a post for every Markdown file given, the
+// .md files of directories are imported too. It goes on past the files it
+// cant import and fails at the end if any did.
+func importPosts(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "import", "[flags] <file or directory>...")
+	dryRun := flags.Bool("dry-run", false, "parse the files but do not send them")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return badUsage(flags, "no file given")
+	}
+
+	paths, err := markdownFiles(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, path := range paths {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		action, id, err := importFile(ctx, a.client, path, *dryRun)
+		if err != nil {
+			failed++
+			fmt.Fprintf(a.stderr, "%s: %v\n", path, err)
+			continue
+		}
+
+		fmt.Fprintf(a.stdout, "%s\t%d\t%s\n", action, id, path)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(paths))
+	}
+	return nil
+}
+
+// markdownFiles expands the directories among paths into the .md files
+// they hold.
+func markdownFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// importFile sends the post of the file and returns what was done to which
+// post.
+func importFile(ctx context.Context, c *client.Client, path string, dryRun bool) (string, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	var meta postFile
+	body, err := frontmatter.Parse(data, &meta)
+	if err != nil {
+		return "", 0, err
+	}
+
+	content := strings.TrimSpace(string(body))
+	switch {
+	case meta.Title == "":
+		return "", 0, errors.New("front matter has no title")
+	case content == "":
+		return "", 0, errors.New("file has no content")
+	}
+	if meta.Format == "" {
+		meta.Format = client.ContentFormatMarkdown
+	}
+
+	if meta.ID == 0 {
+		if dryRun {
+			return "create", 0, nil
+		}
+
+		id, err := c.CreatePost(ctx, client.NewPost{
+			Title:         meta.Title,
+			Content:       content,
+			ContentFormat: meta.Format,
+			Excerpt:       meta.Excerpt,
+			Tags:          meta.Tags,
+		})
+		return "created", id, err
+	}
+
+	post, err := c.GetPost(ctx, meta.ID, client.GetPostParams{Content: "source"})
+	if err != nil {
+		return "", 0, err
+	}
+	if dryRun {
+		return "update", post.ID, nil
+	}
+
+	post.Title = meta.Title
+	post.Content = content
+	post.ContentFormat = meta.Format
+	post.Excerpt = meta.Excerpt
+	post.Tags = meta.Tags
+
+	return "updated", post.ID, c.UpdatePost(ctx, post)
+}
@@ -0,0 +1,185 @@
+// Command newsctl manages the posts of a news-crud server from a terminal.
+//
+// Credentials and the server address are read from a YAML config file, by
+// default newsctl/config.yaml in the user config directory:
+//
+//	server: https://news.example.com
+//	token: <jwt>
+//	api_key: <key>
+//	output: table
+//
+// NEWSCTL_SERVER, NEWSCTL_TOKEN, NEWSCTL_API_KEY and NEWSCTL_OUTPUT override
+// the file, flags override both.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ananaslegend/news-crud/pkg/client"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: newsctl [flags] <command> [args]
+
+Commands:
+  posts list      list posts
+  posts search    search posts by title, excerpt and tags
+  posts get       show a post
+  posts create    create a post
+  posts update    update a post
+  posts delete    delete posts
+  import          create or update posts from Markdown files with front matter
+
+Run "newsctl <command> -h" for the flags of a command.
+
+Flags:
+`
+
+// errUsage is returned for a bad command line, the usage was printed.
+var errUsage = errors.New("usage")
+
+// app is what the commands run with.
+type app struct {
+	client *client.Client
+	output string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command func(ctx context.Context, a *app, args []string) error
+
+var postCommands = map[string]command{
+	"list":   listPosts,
+	"search": searchPosts,
+	"get":    getPost,
+	"create": createPost,
+	"update": updatePost,
+	"delete": deletePosts,
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "newsctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("newsctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	configFile := flags.String("config", "", "config file, $NEWSCTL_CONFIG or newsctl/config.yaml in the user config directory by default")
+	server := flags.String("server", "", "base URL of the server")
+	output := flags.String("o", "", "output format: table, json or yaml")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	cmd, args, err := lookup(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		flags.Usage()
+		return errUsage
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if *output != "" {
+		cfg.Output = *output
+	}
+	if err = validFormat(cfg.Output); err != nil {
+		return err
+	}
+
+	c, err := client.New(client.Config{
+		BaseURL:     cfg.Server,
+		TokenSource: client.StaticToken(cfg.Token),
+		APIKey:      cfg.APIKey,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return cmd(ctx, &app{
+		client: c,
+		output: cfg.Output,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}, args)
+}
+
+// lookup finds the command named by the first arguments and returns it
+// with the rest of them.
+func lookup(args []string) (command, []string, error) {
+	if len(args) == 0 {
+		return nil, nil, errors.New("no command given")
+	}
+
+	switch args[0] {
+	case "import":
+		return importPosts, args[1:], nil
+	case "posts":
+		if len(args) < 2 {
+			return nil, nil, errors.New("posts needs a subcommand")
+		}
+		cmd, ok := postCommands[args[1]]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown command posts %s", args[1])
+		}
+		return cmd, args[2:], nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown command %s", args[0])
+}
+
+// newFlagSet creates the flags of a command, usage describes its arguments.
+func newFlagSet(a *app, name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: newsctl %s %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the flags of a command, a bad command line is reported
+// as errUsage as the flag package already printed what went wrong.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
+
+// badUsage prints what is wrong with the arguments of a command and its
+// usage.
+func badUsage(flags *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(flags.Output(), format+"\n", args...)
+	flags.Usage()
+	return errUsage
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/ananaslegend/news-crud/pkg/client"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const token = "test-token"
+
+// server is an in-memory news-crud API, changes need the test token.
+type server struct {
+	mu     sync.Mutex
+	nextID int
+	posts  map[int]client.Post
+}
+
+func newServer(t *testing.T, posts ...client.Post) (*server, *httptest.Server) {
+	s := &server{posts: make(map[int]client.Post)}
+	for _, post := range posts {
+		s.posts[post.ID] = post
+		s.nextID = max(s.nextID, post.ID)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /posts", s.auth(s.createPost))
+	mux.HandleFunc("GET /posts", s.listPosts)
+	mux.HandleFunc("GET /posts/{id}", s.getPost)
+	mux.HandleFunc("PUT /posts/{id}", s.auth(s.updatePost))
+	mux.HandleFunc("DELETE /posts/{id}", s.auth(s.deletePost))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return s, srv
+}
+
+func (s *server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *server) createPost(w http.ResponseWriter, r *http.Request) {
+	var post client.NewPost
+	if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.posts[id] = client.Post{
+		ID:            id,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Excerpt:       post.Excerpt,
+		Tags:          post.Tags,
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]int{"post_id": id})
+}
+
+func (s *server) listPosts(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	s.mu.Lock()
+	list := make([]client.Post, 0, len(s.posts))
+	for _, post := range s.posts {
+		list = append(list, post)
+	}
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	list = list[min(offset, len(list)):]
+	if limit > 0 {
+		list = list[:min(limit, len(list))]
+	}
+	if len(list) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *server) getPost(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.post(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, post)
+}
+
+func (s *server) updatePost(w http.ResponseWriter, r *http.Request) {
+	old, ok := s.post(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var post client.Post
+	if err := json.NewDecoder(r.Body).Decode(&post); err != nil || post.ID != old.ID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.posts[post.ID] = post
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]int{"post_id": post.ID})
+}
+
+func (s *server) deletePost(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.post(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.posts, post.ID)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) post(r *http.Request) (client.Post, bool) {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post, ok := s.posts[id]
+	return post, ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeFile writes the file in dir and returns its path.
+func writeFile(t *testing.T, dir, name, data string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+// newsctl runs the command line against the server with a config file
+// holding its address and the test token.
+func newsctl(t *testing.T, serverURL string, stdin string, args ...string) (string, string, error) {
+	t.Setenv("NEWSCTL_CONFIG", "")
+	t.Setenv("NEWSCTL_SERVER", "")
+	t.Setenv("NEWSCTL_TOKEN", "")
+	t.Setenv("NEWSCTL_API_KEY", "")
+	t.Setenv("NEWSCTL_OUTPUT", "")
+
+	config := writeFile(t, t.TempDir(), "config.yaml", "server: "+serverURL+"\ntoken: "+token+"\n")
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), append([]string{"-config", config}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), err
+}
+
+var testPosts = []client.Post{
+	{ID: 1, Title: "First", Content: "one", ContentFormat: "markdown", AuthorID: 7, Tags: []string{"go", "news"}},
+	{ID: 2, Title: "Second", Content: "two", ContentFormat: "plain", AuthorID: 8},
+}
+
+func TestRun_Usage(t *testing.T) {
+	_, srv := newServer(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+		wantOut string
+	}{
+		{name: "No command", wantErr: errUsage, wantOut: "no command given"},
+		{name: "Unknown command", args: []string{"users"}, wantErr: errUsage, wantOut: "unknown command users"},
+		{name: "Posts without subcommand", args: []string{"posts"}, wantErr: errUsage, wantOut: "posts needs a subcommand"},
+		{name: "Unknown posts command", args: []string{"posts", "publish"}, wantErr: errUsage, wantOut: "unknown command posts publish"},
+		{name: "Unknown flag", args: []string{"-verbose", "posts", "list"}, wantErr: errUsage, wantOut: "flag provided but not defined"},
+		{name: "Help", args: []string{"-h"}, wantErr: flag.ErrHelp, wantOut: "Commands:"},
+		{name: "Command help", args: []string{"posts", "get", "-h"}, wantErr: flag.ErrHelp, wantOut: "Usage: newsctl posts get [flags] <id>"},
+		{name: "Missing post id", args: []string{"posts", "get"}, wantErr: errUsage, wantOut: "want exactly one post id"},
+		{name: "Invalid post id", args: []string{"posts", "delete", "1", "x"}, wantErr: errUsage, wantOut: `invalid post id "x"`},
+		{name: "Invalid date", args: []string{"posts", "list", "-from", "May"}, wantErr: errUsage, wantOut: "want a date like 2024-05-01"},
+		{name: "Search without query", args: []string{"posts", "search"}, wantErr: errUsage, wantOut: "no query given"},
+		{name: "Create without content", args: []string{"posts", "create", "-title", "t"}, wantErr: errUsage, wantOut: "a title and a content are required"},
+		{name: "Import without files", args: []string{"import"}, wantErr: errUsage, wantOut: "no file given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := newsctl(t, srv.URL, "", tt.args...)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Empty(t, stdout)
+			require.Contains(t, stderr, tt.wantOut)
+		})
+	}
+
+	_, _, err := newsctl(t, srv.URL, "", "-o", "xml", "posts", "list")
+	require.ErrorContains(t, err, `unknown output format "xml"`)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("NEWSCTL_CONFIG", "")
+	t.Setenv("NEWSCTL_SERVER", "")
+	t.Setenv("NEWSCTL_TOKEN", "")
+	t.Setenv("NEWSCTL_API_KEY", "")
+	t.Setenv("NEWSCTL_OUTPUT", "")
+
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", "server: https://news.example.com\ntoken: file-token\noutput: json\n")
+
+	cfg, err := loadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, Config{Server: "https://news.example.com", Token: "file-token", Output: formatJSON}, cfg)
+
+	t.Setenv("NEWSCTL_TOKEN", "env-token")
+	t.Setenv("NEWSCTL_CONFIG", path)
+
+	cfg, err = loadConfig("")
+	require.NoError(t, err)
+	require.Equal(t, "env-token", cfg.Token, "the environment overrides the file")
+	require.Equal(t, "https://news.example.com", cfg.Server)
+
+	_, err = loadConfig(filepath.Join(dir, "missing.yaml"))
+	require.ErrorContains(t, err, "cant read config", "a config file given has to exist")
+
+	_, err = loadConfig(writeFile(t, dir, "broken.yaml", "server: [\n"))
+	require.ErrorContains(t, err, "cant decode config")
+}
+
+func TestPosts_Output(t *testing.T) {
+	_, srv := newServer(t, testPosts...)
+
+	t.Run("Table", func(t *testing.T) {
+		stdout, _, err := newsctl(t, srv.URL, "", "posts", "list")
+		require.NoError(t, err)
+		require.Equal(t, strings.Join([]string{
+			"ID  TITLE   AUTHOR  TAGS     CREATED",
+			"1   First   7       go,news  ",
+			"2   Second  8                ",
+			"",
+		}, "\n"), stdout)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		stdout, _, err := newsctl(t, srv.URL, "", "-o", "json", "posts", "list", "-limit", "1")
+		require.NoError(t, err)
+
+		var posts []client.Post
+		require.NoError(t, json.Unmarshal([]byte(stdout), &posts))
+		require.Equal(t, testPosts[:1], posts)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		stdout, _, err := newsctl(t, srv.URL, "", "-o", "yaml", "posts", "get", "2")
+		require.NoError(t, err)
+
+		var post map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(stdout), &post))
+		require.Equal(t, 2, post["ID"])
+		require.Equal(t, "Second", post["Title"], "YAML uses the keys of the JSON")
+	})
+
+	t.Run("Single post table", func(t *testing.T) {
+		stdout, _, err := newsctl(t, srv.URL, "", "posts", "get", "1")
+		require.NoError(t, err)
+		require.Contains(t, stdout, "Title:    First\n")
+		require.Contains(t, stdout, "Tags:     go, news\n")
+		require.True(t, strings.HasSuffix(stdout, "\none\n"), "the content follows the fields")
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		stdout, _, err := newsctl(t, srv.URL, "", "-o", "json", "posts", "search", "NEWS")
+		require.NoError(t, err)
+
+		var posts []client.Post
+		require.NoError(t, json.Unmarshal([]byte(stdout), &posts))
+		require.Len(t, posts, 1)
+		require.Equal(t, 1, posts[0].ID, "tags are searched")
+	})
+
+	t.Run("Empty list", func(t *testing.T) {
+		_, empty := newServer(t)
+
+		stdout, _, err := newsctl(t, empty.URL, "", "-o", "json", "posts", "list")
+		require.NoError(t, err)
+		require.Equal(t, "[]\n", stdout)
+	})
+}
+
+func TestPosts_Edit(t *testing.T) {
+	s, srv := newServer(t, testPosts...)
+
+	stdout, _, err := newsctl(t, srv.URL, "from stdin", "posts", "create", "-title", "Third", "-file", "-", "-tag", "a", "-tag", "b")
+	require.NoError(t, err)
+	require.Equal(t, "3\n", stdout)
+	require.Equal(t, client.Post{
+		ID:            3,
+		Title:         "Third",
+		Content:       "from stdin",
+		ContentFormat: client.ContentFormatMarkdown,
+		Tags:          []string{"a", "b"},
+	}, s.posts[3])
+
+	_, _, err = newsctl(t, srv.URL, "", "posts", "update", "-title", "First!", "-clear-tags", "1")
+	require.NoError(t, err)
+	require.Equal(t, "First!", s.posts[1].Title)
+	require.Equal(t, "one", s.posts[1].Content, "fields not given are kept")
+	require.Empty(t, s.posts[1].Tags)
+
+	_, _, err = newsctl(t, srv.URL, "", "posts", "delete", "2", "9")
+	require.ErrorContains(t, err, "cant delete post 9")
+	require.NotContains(t, s.posts, 2)
+}
+
+func TestImport(t *testing.T) {
+	s, srv := newServer(t, testPosts...)
+
+	dir := t.TempDir()
+	writeFile(t, dir, "new.md", "---\ntitle: New post\ntags: [go]\n---\n\n# Hello\n")
+	writeFile(t, dir, "first.md", "---\nid: 1\ntitle: First again\nformat: plain\n---\nUpdated\n")
+	writeFile(t, dir, "untitled.md", "no front matter\n")
+	writeFile(t, dir, "notes.txt", "---\ntitle: Not imported\n---\ntext\n")
+
+	t.Run("Dry run", func(t *testing.T) {
+		stdout, _, err := newsctl(t, srv.URL, "", "import", "-dry-run", filepath.Join(dir, "new.md"), filepath.Join(dir, "first.md"))
+		require.NoError(t, err)
+		require.Equal(t, "create\t0\t"+filepath.Join(dir, "new.md")+"\n"+
+			"update\t1\t"+filepath.Join(dir, "first.md")+"\n", stdout)
+		require.Len(t, s.posts, 2, "nothing was sent")
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		stdout, stderr, err := newsctl(t, srv.URL, "", "import", dir)
+		require.ErrorContains(t, err, "1 of 3 files failed")
+		require.Equal(t, filepath.Join(dir, "untitled.md")+": front matter has no title\n", stderr)
+		require.Equal(t, "updated\t1\t"+filepath.Join(dir, "first.md")+"\n"+
+			"created\t3\t"+filepath.Join(dir, "new.md")+"\n", stdout)
+
+		require.Equal(t, client.Post{
+			ID:            3,
+			Title:         "New post",
+			Content:       "# Hello",
+			ContentFormat: client.ContentFormatMarkdown,
+			Tags:          []string{"go"},
+		}, s.posts[3])
+
+		first := s.posts[1]
+		require.Equal(t, "First again", first.Title)
+		require.Equal(t, "Updated", first.Content)
+		require.Equal(t, "plain", first.ContentFormat)
+		require.Equal(t, 7, first.AuthorID, "fields not in the front matter are kept")
+	})
+}
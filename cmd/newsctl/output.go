@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ananaslegend/news-crud/pkg/client"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+func validFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, want table, json or yaml", format)
+}
+
+// printPosts writes the posts as a table, one post per row, or as a JSON or
+// YAML list.
+func printPosts(w io.Writer, format string, posts []client.Post) error {
+	if format != formatTable {
+		return encode(w, format, posts)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tAUTHOR\tTAGS\tCREATED")
+	for _, post := range posts {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\n",
+			post.ID, truncate(post.Title, 60), post.AuthorID, strings.Join(post.Tags, ","), formatTime(post.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+// printPost writes the post as a list of fields followed by its content, or
+// as a JSON or YAML object.
+func printPost(w io.Writer, format string, post client.Post) error {
+	if format != formatTable {
+		return encode(w, format, post)
+	}
+
+	authors := make([]string, len(post.Authors))
+	for i, a := range post.Authors {
+		authors[i] = strconv.Itoa(a.UserID) + " (" + a.Role + ")"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", post.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", post.Title)
+	fmt.Fprintf(tw, "Format:\t%s\n", post.ContentFormat)
+	fmt.Fprintf(tw, "Author:\t%d\n", post.AuthorID)
+	if len(authors) > 0 {
+		fmt.Fprintf(tw, "Authors:\t%s\n", strings.Join(authors, ", "))
+	}
+	fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(post.Tags, ", "))
+	fmt.Fprintf(tw, "Excerpt:\t%s\n", post.Excerpt)
+	fmt.Fprintf(tw, "Words:\t%d (%d min)\n", post.WordCount, post.ReadingTime)
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(post.CreatedAt))
+	fmt.Fprintf(tw, "Updated:\t%s\n", formatTime(post.UpdatedAt))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if post.Content != "" {
+		_, err := fmt.Fprintf(w, "\n%s\n", strings.TrimRight(post.Content, "\n"))
+		return err
+	}
+	return nil
+}
+
+// encode writes v as indented JSON or as YAML. YAML goes through JSON so
+// both use the same keys.
+func encode(w io.Writer, format string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(json.RawMessage(data))
+	}
+
+	var generic any
+	if err = json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ananaslegend/news-crud/pkg/client"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// timeFlag is a flag holding an RFC 3339 time or a date.
+type timeFlag struct {
+	time.Time
+}
+
+func (t *timeFlag) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (t *timeFlag) Set(value string) error {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return errors.New("want a date like 2024-05-01 or an RFC 3339 time")
+}
+
+// listFlags are the filters shared by posts list and posts search.
+type listFlags struct {
+	from  timeFlag
+	to    timeFlag
+	sort  string
+	limit int
+}
+
+func (f *listFlags) register(flags *flag.FlagSet) {
+	flags.Var(&f.from, "from", "only posts created at or after this time")
+	flags.Var(&f.to, "to", "only posts created at or before this time")
+	flags.StringVar(&f.sort, "sort", "", "sort order: created_at or reactions")
+	flags.IntVar(&f.limit, "limit", 20, "show at most this many posts, 0 for all")
+}
+
+func (f *listFlags) params() client.ListPostsParams {
+	return client.ListPostsParams{
+		DateFrom: f.from.Time,
+		DateTo:   f.to.Time,
+		Sort:     f.sort,
+		View:     "compact",
+	}
+}
+
+// collect reads posts from it until limit of them were kept by match, a
+// zero limit keeps all.
+func collect(ctx context.Context, it *client.Iterator[client.Post], limit int, match func(client.Post) bool) ([]client.Post, error) {
+	posts := []client.Post{}
+	for it.Next(ctx) {
+		if match != nil && !match(it.Value()) {
+			continue
+		}
+		posts = append(posts, it.Value())
+		if limit > 0 && len(posts) == limit {
+			break
+		}
+	}
+	return posts, it.Err()
+}
+
+func listPosts(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "posts list", "[flags]")
+	var f listFlags
+	f.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	params := f.params()
+	if f.limit > 0 && f.limit < client.DefaultPageSize {
+		params.Limit = f.limit
+	}
+
+	posts, err := collect(ctx, a.client.Posts(params), f.limit, nil)
+	if err != nil {
+		return err
+	}
+	return printPosts(a.stdout, a.output, posts)
+}
+
+// searchPosts matches the words of the query against the title, excerpt and
+// tags of every post, the server has no search of its own.
+func searchPosts(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "posts search", "[flags] <query>")
+	var f listFlags
+	f.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	words := strings.Fields(strings.ToLower(strings.Join(flags.Args(), " ")))
+	if len(words) == 0 {
+		return badUsage(flags, "no query given")
+	}
+
+	match := func(post client.Post) bool {
+		text := strings.ToLower(post.Title + " " + post.Excerpt + " " + strings.Join(post.Tags, " "))
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				return false
+			}
+		}
+		return true
+	}
+
+	posts, err := collect(ctx, a.client.Posts(f.params()), f.limit, match)
+	if err != nil {
+		return err
+	}
+	return printPosts(a.stdout, a.output, posts)
+}
+
+func getPost(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "posts get", "[flags] <id>")
+	content := flags.String("content", "source", "form of the content: source, html or both when empty")
+	fields := flags.String("fields", "", "comma separated fields to return, all by default")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return badUsage(flags, "want exactly one post id")
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return badUsage(flags, "%v", err)
+	}
+
+	params := client.GetPostParams{Content: *content}
+	if *fields != "" {
+		params.Fields = strings.Split(*fields, ",")
+	}
+
+	post, err := a.client.GetPost(ctx, id, params)
+	if err != nil {
+		return err
+	}
+	return printPost(a.stdout, a.output, post)
+}
+
+// editFlags are the post fields posts create and posts update set.
+type editFlags struct {
+	title   string
+	content string
+	file    string
+	format  string
+	excerpt string
+	tags    stringList
+}
+
+func (f *editFlags) register(flags *flag.FlagSet, format string) {
+	flags.StringVar(&f.title, "title", "", "title of the post")
+	flags.StringVar(&f.content, "content", "", "content of the post")
+	flags.StringVar(&f.file, "file", "", `read the content from this file, "-" for stdin`)
+	flags.StringVar(&f.format, "format", format, "content format: markdown, html or plain")
+	flags.StringVar(&f.excerpt, "excerpt", "", "excerpt of the post, derived from the content when empty")
+	flags.Var(&f.tags, "tag", "tag of the post, may be repeated")
+}
+
+// readContent returns the content given by -content or -file and whether
+// one of them was set.
+func (f *editFlags) readContent(stdin io.Reader) (string, bool, error) {
+	switch {
+	case f.file != "" && f.content != "":
+		return "", false, errors.New("-content and -file are exclusive")
+	case f.file == "-":
+		data, err := io.ReadAll(stdin)
+		return string(data), true, err
+	case f.file != "":
+		data, err := os.ReadFile(f.file)
+		return string(data), true, err
+	}
+	return f.content, f.content != "", nil
+}
+
+func createPost(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "posts create", "[flags]")
+	var f editFlags
+	f.register(flags, client.ContentFormatMarkdown)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	content, _, err := f.readContent(a.stdin)
+	if err != nil {
+		return err
+	}
+	if f.title == "" || content == "" {
+		return badUsage(flags, "a title and a content are required")
+	}
+
+	id, err := a.client.CreatePost(ctx, client.NewPost{
+		Title:         f.title,
+		Content:       content,
+		ContentFormat: f.format,
+		Excerpt:       f.excerpt,
+		Tags:          f.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, id)
+	return nil
+}
+
+// updatePost changes the fields given on the command line and keeps the
+// others.
+func updatePost(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "posts update", "[flags] <id>")
+	var f editFlags
+	f.register(flags, "")
+	clearTags := flags.Bool("clear-tags", false, "remove all the tags")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return badUsage(flags, "want exactly one post id")
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return badUsage(flags, "%v", err)
+	}
+
+	content, hasContent, err := f.readContent(a.stdin)
+	if err != nil {
+		return err
+	}
+
+	post, err := a.client.GetPost(ctx, id, client.GetPostParams{Content: "source"})
+	if err != nil {
+		return err
+	}
+
+	if f.title != "" {
+		post.Title = f.title
+	}
+	if hasContent {
+		post.Content = content
+	}
+	if f.format != "" {
+		post.ContentFormat = f.format
+	}
+	if f.excerpt != "" {
+		post.Excerpt = f.excerpt
+	}
+	if *clearTags {
+		post.Tags = nil
+	}
+	if len(f.tags) > 0 {
+		post.Tags = f.tags
+	}
+
+	return a.client.UpdatePost(ctx, post)
+}
+
+func deletePosts(ctx context.Context, a *app, args []string) error {
+	flags := newFlagSet(a, "posts delete", "<id>...")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return badUsage(flags, "no post id given")
+	}
+
+	ids := make([]int, flags.NArg())
+	for i, arg := range flags.Args() {
+		id, err := parseID(arg)
+		if err != nil {
+			return badUsage(flags, "%v", err)
+		}
+		ids[i] = id
+	}
+
+	for _, id := range ids {
+		if err := a.client.DeletePost(ctx, id); err != nil {
+			return fmt.Errorf("cant delete post %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid post id %q", s)
+	}
+	return id, nil
+}
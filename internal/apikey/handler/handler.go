@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/ananaslegend/news-crud/internal/apikey/service"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type CreateAPIKeyService interface {
+	CreateAPIKey(ctx context.Context, userID int, name string, scopes []string, expiresAt *time.Time) (string, model.APIKey, error)
+}
+
+type GetAPIKeysByUserIDService interface {
+	GetAPIKeysByUserID(ctx context.Context, userID int) ([]model.APIKey, error)
+}
+
+type RevokeAPIKeyService interface {
+	RevokeAPIKey(ctx context.Context, userID, keyID int) error
+}
+
+type APIKeyHandler struct {
+	logger *slog.Logger
+
+	createAPIKeyService       CreateAPIKeyService
+	getAPIKeysByUserIDService GetAPIKeysByUserIDService
+	revokeAPIKeyService       RevokeAPIKeyService
+}
+
+func NewAPIKeyHandler(
+	logger *slog.Logger,
+	createAPIKeyService CreateAPIKeyService,
+	getAPIKeysByUserIDService GetAPIKeysByUserIDService,
+	revokeAPIKeyService RevokeAPIKeyService,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		logger:                    logger,
+		createAPIKeyService:       createAPIKeyService,
+		getAPIKeysByUserIDService: getAPIKeysByUserIDService,
+		revokeAPIKeyService:       revokeAPIKeyService,
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string       `json:"key"`
+	APIKey model.APIKey `json:"api_key"`
+}
+
+func (h APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.apikey.create.handler.CreateAPIKey"
+	logger := h.logger.With(slog.String("op", op))
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("cant decode request", logs.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validator.New().Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	plain, key, err := h.createAPIKeyService.CreateAPIKey(r.Context(), userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrUnknownScope),
+			errors.Is(err, model.ErrNoScopes),
+			errors.Is(err, model.ErrExpiresInPast):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		default:
+			logger.Error("cant create api key", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(CreateAPIKeyResponse{Key: plain, APIKey: key}); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func (h APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.apikey.get_by_user_id.handler.GetAPIKeys"
+	logger := h.logger.With(slog.String("op", op))
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	keys, err := h.getAPIKeysByUserIDService.GetAPIKeysByUserID(r.Context(), userID)
+	if err != nil {
+		logger.Error("cant get api keys", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func (h APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.apikey.revoke.handler.RevokeAPIKey"
+	logger := h.logger.With(slog.String("op", op))
+
+	keyID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || keyID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	if err = h.revokeAPIKeyService.RevokeAPIKey(r.Context(), userID, keyID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoAPIKeyWasFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			logger.Error("cant revoke api key", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
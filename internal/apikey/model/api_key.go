@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ScopePostsRead lets a key read posts, ScopePostsWrite create and change
+// them. Calls without a key are not limited by scopes.
+const (
+	ScopePostsRead  = "posts:read"
+	ScopePostsWrite = "posts:write"
+)
+
+var (
+	ErrUnknownScope  = errors.New("unknown scope")
+	ErrNoScopes      = errors.New("at least one scope is required")
+	ErrExpiresInPast = errors.New("expires_at should be in the future")
+)
+
+var knownScopes = map[string]struct{}{
+	ScopePostsRead:  {},
+	ScopePostsWrite: {},
+}
+
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return ErrNoScopes
+	}
+
+	for _, scope := range scopes {
+		if _, ok := knownScopes[scope]; !ok {
+			return ErrUnknownScope
+		}
+	}
+
+	return nil
+}
+
+func (k APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+func (k APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+var (
+	ErrNoAPIKeyWasFound = errors.New("api key not found")
+)
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/lib/pq"
+	"time"
+)
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (ar APIKeyRepository) CreateAPIKey(ctx context.Context, key model.APIKey) (int, error) {
+	const op = "news-crud.internal.apikey.create.repository.CreateAPIKey"
+
+	var keyID int
+	err := ar.db.QueryRowContext(ctx, `
+		insert into
+		    api_keys (user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id
+	`, key.UserID, key.Name, key.Prefix, key.Hash, pq.Array(key.Scopes), key.ExpiresAt, key.CreatedAt,
+	).Scan(&keyID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keyID, nil
+}
+
+func (ar APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	const op = "news-crud.internal.apikey.get_by_prefix.repository.GetAPIKeyByPrefix"
+
+	key, err := scanAPIKey(ar.db.QueryRowContext(ctx, `
+		select id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at, revoked_at
+		from api_keys
+		where prefix = $1
+	`, prefix))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.APIKey{}, ErrNoAPIKeyWasFound
+		}
+
+		return model.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (ar APIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID int) ([]model.APIKey, error) {
+	const op = "news-crud.internal.apikey.get_by_user_id.repository.GetAPIKeysByUserID"
+
+	rows, err := ar.db.QueryContext(ctx, `
+		select id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at, revoked_at
+		from api_keys
+		where user_id = $1
+		order by id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	keys := make([]model.APIKey, 0)
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (ar APIKeyRepository) RevokeAPIKey(ctx context.Context, userID, keyID int, revokedAt time.Time) error {
+	const op = "news-crud.internal.apikey.revoke.repository.RevokeAPIKey"
+
+	res, err := ar.db.ExecContext(ctx, `
+		update api_keys
+		set revoked_at = $1
+		where id = $2 and user_id = $3 and revoked_at is null
+	`, revokedAt, keyID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return ErrNoAPIKeyWasFound
+	}
+
+	return nil
+}
+
+func (ar APIKeyRepository) TouchAPIKey(ctx context.Context, keyID int, usedAt time.Time) error {
+	const op = "news-crud.internal.apikey.touch.repository.TouchAPIKey"
+
+	if _, err := ar.db.ExecContext(ctx, `
+		update api_keys
+		set last_used_at = $1
+		where id = $2
+	`, usedAt, keyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (model.APIKey, error) {
+	var (
+		key        model.APIKey
+		expiresAt  sql.NullTime
+		lastUsedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+
+	err := row.Scan(
+		&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.Hash, pq.Array(&key.Scopes),
+		&expiresAt, &lastUsedAt, &key.CreatedAt, &revokedAt,
+	)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	key.ExpiresAt = nullTimePtr(expiresAt)
+	key.LastUsedAt = nullTimePtr(lastUsedAt)
+	key.RevokedAt = nullTimePtr(revokedAt)
+
+	return key, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,11 @@
+package service
+
+import "errors"
+
+var (
+	ErrCantCreateAPIKey = errors.New("api key can not be created")
+	ErrNoAPIKeyWasFound = errors.New("api key not found")
+	ErrInvalidAPIKey    = errors.New("invalid api key")
+	ErrAPIKeyExpired    = errors.New("api key expired")
+	ErrAPIKeyRevoked    = errors.New("api key revoked")
+)
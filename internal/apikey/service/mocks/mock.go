@@ -0,0 +1,207 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ananaslegend/news-crud/internal/apikey/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCreateAPIKeyRepository is a mock of CreateAPIKeyRepository interface.
+type MockCreateAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateAPIKeyRepositoryMockRecorder
+}
+
+// MockCreateAPIKeyRepositoryMockRecorder is the mock recorder for MockCreateAPIKeyRepository.
+type MockCreateAPIKeyRepositoryMockRecorder struct {
+	mock *MockCreateAPIKeyRepository
+}
+
+// NewMockCreateAPIKeyRepository creates a new mock instance.
+func NewMockCreateAPIKeyRepository(ctrl *gomock.Controller) *MockCreateAPIKeyRepository {
+	mock := &MockCreateAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockCreateAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateAPIKeyRepository) EXPECT() *MockCreateAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockCreateAPIKeyRepository) CreateAPIKey(ctx context.Context, key model.APIKey) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockCreateAPIKeyRepositoryMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockCreateAPIKeyRepository)(nil).CreateAPIKey), ctx, key)
+}
+
+// MockGetAPIKeyByPrefixRepository is a mock of GetAPIKeyByPrefixRepository interface.
+type MockGetAPIKeyByPrefixRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetAPIKeyByPrefixRepositoryMockRecorder
+}
+
+// MockGetAPIKeyByPrefixRepositoryMockRecorder is the mock recorder for MockGetAPIKeyByPrefixRepository.
+type MockGetAPIKeyByPrefixRepositoryMockRecorder struct {
+	mock *MockGetAPIKeyByPrefixRepository
+}
+
+// NewMockGetAPIKeyByPrefixRepository creates a new mock instance.
+func NewMockGetAPIKeyByPrefixRepository(ctrl *gomock.Controller) *MockGetAPIKeyByPrefixRepository {
+	mock := &MockGetAPIKeyByPrefixRepository{ctrl: ctrl}
+	mock.recorder = &MockGetAPIKeyByPrefixRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetAPIKeyByPrefixRepository) EXPECT() *MockGetAPIKeyByPrefixRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAPIKeyByPrefix mocks base method.
+func (m *MockGetAPIKeyByPrefixRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByPrefix indicates an expected call of GetAPIKeyByPrefix.
+func (mr *MockGetAPIKeyByPrefixRepositoryMockRecorder) GetAPIKeyByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByPrefix", reflect.TypeOf((*MockGetAPIKeyByPrefixRepository)(nil).GetAPIKeyByPrefix), ctx, prefix)
+}
+
+// MockGetAPIKeysByUserIDRepository is a mock of GetAPIKeysByUserIDRepository interface.
+type MockGetAPIKeysByUserIDRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetAPIKeysByUserIDRepositoryMockRecorder
+}
+
+// MockGetAPIKeysByUserIDRepositoryMockRecorder is the mock recorder for MockGetAPIKeysByUserIDRepository.
+type MockGetAPIKeysByUserIDRepositoryMockRecorder struct {
+	mock *MockGetAPIKeysByUserIDRepository
+}
+
+// NewMockGetAPIKeysByUserIDRepository creates a new mock instance.
+func NewMockGetAPIKeysByUserIDRepository(ctrl *gomock.Controller) *MockGetAPIKeysByUserIDRepository {
+	mock := &MockGetAPIKeysByUserIDRepository{ctrl: ctrl}
+	mock.recorder = &MockGetAPIKeysByUserIDRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetAPIKeysByUserIDRepository) EXPECT() *MockGetAPIKeysByUserIDRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAPIKeysByUserID mocks base method.
+func (m *MockGetAPIKeysByUserIDRepository) GetAPIKeysByUserID(ctx context.Context, userID int) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeysByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeysByUserID indicates an expected call of GetAPIKeysByUserID.
+func (mr *MockGetAPIKeysByUserIDRepositoryMockRecorder) GetAPIKeysByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeysByUserID", reflect.TypeOf((*MockGetAPIKeysByUserIDRepository)(nil).GetAPIKeysByUserID), ctx, userID)
+}
+
+// MockRevokeAPIKeyRepository is a mock of RevokeAPIKeyRepository interface.
+type MockRevokeAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevokeAPIKeyRepositoryMockRecorder
+}
+
+// MockRevokeAPIKeyRepositoryMockRecorder is the mock recorder for MockRevokeAPIKeyRepository.
+type MockRevokeAPIKeyRepositoryMockRecorder struct {
+	mock *MockRevokeAPIKeyRepository
+}
+
+// NewMockRevokeAPIKeyRepository creates a new mock instance.
+func NewMockRevokeAPIKeyRepository(ctrl *gomock.Controller) *MockRevokeAPIKeyRepository {
+	mock := &MockRevokeAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockRevokeAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevokeAPIKeyRepository) EXPECT() *MockRevokeAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockRevokeAPIKeyRepository) RevokeAPIKey(ctx context.Context, userID, keyID int, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, userID, keyID, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockRevokeAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, userID, keyID, revokedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockRevokeAPIKeyRepository)(nil).RevokeAPIKey), ctx, userID, keyID, revokedAt)
+}
+
+// MockTouchAPIKeyRepository is a mock of TouchAPIKeyRepository interface.
+type MockTouchAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTouchAPIKeyRepositoryMockRecorder
+}
+
+// MockTouchAPIKeyRepositoryMockRecorder is the mock recorder for MockTouchAPIKeyRepository.
+type MockTouchAPIKeyRepositoryMockRecorder struct {
+	mock *MockTouchAPIKeyRepository
+}
+
+// NewMockTouchAPIKeyRepository creates a new mock instance.
+func NewMockTouchAPIKeyRepository(ctrl *gomock.Controller) *MockTouchAPIKeyRepository {
+	mock := &MockTouchAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockTouchAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTouchAPIKeyRepository) EXPECT() *MockTouchAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockTouchAPIKeyRepository) TouchAPIKey(ctx context.Context, keyID int, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, keyID, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockTouchAPIKeyRepositoryMockRecorder) TouchAPIKey(ctx, keyID, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockTouchAPIKeyRepository)(nil).TouchAPIKey), ctx, keyID, usedAt)
+}
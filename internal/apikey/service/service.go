@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/ananaslegend/news-crud/internal/apikey/repository"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+const (
+	// KeyPrefix marks a token as an api key, so it can be told apart from a JWT.
+	KeyPrefix = "nck"
+
+	prefixBytes = 6
+	secretBytes = 32
+)
+
+type CreateAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (int, error)
+}
+
+type GetAPIKeyByPrefixRepository interface {
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+}
+
+type GetAPIKeysByUserIDRepository interface {
+	GetAPIKeysByUserID(ctx context.Context, userID int) ([]model.APIKey, error)
+}
+
+type RevokeAPIKeyRepository interface {
+	RevokeAPIKey(ctx context.Context, userID, keyID int, revokedAt time.Time) error
+}
+
+type TouchAPIKeyRepository interface {
+	TouchAPIKey(ctx context.Context, keyID int, usedAt time.Time) error
+}
+
+type APIKeyService struct {
+	logger *slog.Logger
+
+	createAPIKeyRepository       CreateAPIKeyRepository
+	getAPIKeyByPrefixRepository  GetAPIKeyByPrefixRepository
+	getAPIKeysByUserIDRepository GetAPIKeysByUserIDRepository
+	revokeAPIKeyRepository       RevokeAPIKeyRepository
+	touchAPIKeyRepository        TouchAPIKeyRepository
+}
+
+func NewAPIKeyService(
+	logger *slog.Logger,
+	createAPIKeyRepository CreateAPIKeyRepository,
+	getAPIKeyByPrefixRepository GetAPIKeyByPrefixRepository,
+	getAPIKeysByUserIDRepository GetAPIKeysByUserIDRepository,
+	revokeAPIKeyRepository RevokeAPIKeyRepository,
+	touchAPIKeyRepository TouchAPIKeyRepository,
+) *APIKeyService {
+	return &APIKeyService{
+		logger:                       logger,
+		createAPIKeyRepository:       createAPIKeyRepository,
+		getAPIKeyByPrefixRepository:  getAPIKeyByPrefixRepository,
+		getAPIKeysByUserIDRepository: getAPIKeysByUserIDRepository,
+		revokeAPIKeyRepository:       revokeAPIKeyRepository,
+		touchAPIKeyRepository:        touchAPIKeyRepository,
+	}
+}
+
+// CreateAPIKey issues a new key for the user. The plain key is returned only once,
+// only its hash is stored.
+func (s APIKeyService) CreateAPIKey(
+	ctx context.Context,
+	userID int,
+	name string,
+	scopes []string,
+	expiresAt *time.Time,
+) (string, model.APIKey, error) {
+	const op = "news-crud.internal.apikey.create.service.CreateAPIKey"
+	logger := s.logger.With(slog.String("op", op))
+
+	if err := model.ValidateScopes(scopes); err != nil {
+		return "", model.APIKey{}, err
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return "", model.APIKey{}, model.ErrExpiresInPast
+	}
+
+	prefix, err := randomHex(prefixBytes)
+	if err != nil {
+		logger.Error("cant generate key prefix", logs.Err(err))
+		return "", model.APIKey{}, ErrCantCreateAPIKey
+	}
+
+	secret, err := randomHex(secretBytes)
+	if err != nil {
+		logger.Error("cant generate key secret", logs.Err(err))
+		return "", model.APIKey{}, ErrCantCreateAPIKey
+	}
+
+	plain := strings.Join([]string{KeyPrefix, prefix, secret}, "_")
+
+	key := model.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hashKey(plain),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+
+	key.ID, err = s.createAPIKeyRepository.CreateAPIKey(ctx, key)
+	if err != nil {
+		logger.Error("cant create api key", logs.Err(err))
+		return "", model.APIKey{}, ErrCantCreateAPIKey
+	}
+
+	return plain, key, nil
+}
+
+func (s APIKeyService) GetAPIKeysByUserID(ctx context.Context, userID int) ([]model.APIKey, error) {
+	const op = "news-crud.internal.apikey.get_by_user_id.service.GetAPIKeysByUserID"
+
+	keys, err := s.getAPIKeysByUserIDRepository.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (s APIKeyService) RevokeAPIKey(ctx context.Context, userID, keyID int) error {
+	const op = "news-crud.internal.apikey.revoke.service.RevokeAPIKey"
+
+	if err := s.revokeAPIKeyRepository.RevokeAPIKey(ctx, userID, keyID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNoAPIKeyWasFound) {
+			return ErrNoAPIKeyWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Authenticate resolves a plain key into the stored api key and records its usage.
+func (s APIKeyService) Authenticate(ctx context.Context, plain string) (model.APIKey, error) {
+	const op = "news-crud.internal.apikey.authenticate.service.Authenticate"
+	logger := s.logger.With(slog.String("op", op))
+
+	prefix, ok := ParsePrefix(plain)
+	if !ok {
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.getAPIKeyByPrefixRepository.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoAPIKeyWasFound) {
+			return model.APIKey{}, ErrInvalidAPIKey
+		}
+
+		return model.APIKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashKey(plain))) != 1 {
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+
+	if key.IsRevoked() {
+		return model.APIKey{}, ErrAPIKeyRevoked
+	}
+
+	if key.IsExpired(now) {
+		return model.APIKey{}, ErrAPIKeyExpired
+	}
+
+	if err = s.touchAPIKeyRepository.TouchAPIKey(ctx, key.ID, now); err != nil {
+		logger.Error("cant update api key last usage", logs.Err(err))
+	}
+	key.LastUsedAt = &now
+
+	return key, nil
+}
+
+// IsAPIKey reports whether the token looks like an api key rather than a JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, KeyPrefix+"_")
+}
+
+// ParsePrefix extracts the public lookup prefix out of a plain key.
+func ParsePrefix(plain string) (string, bool) {
+	parts := strings.Split(plain, "_")
+	if len(parts) != 3 || parts[0] != KeyPrefix || len(parts[1]) != prefixBytes*2 || len(parts[2]) != secretBytes*2 {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+func hashKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/ananaslegend/news-crud/internal/apikey/repository"
+	mock_service "github.com/ananaslegend/news-crud/internal/apikey/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_service.NewMockCreateAPIKeyRepository(c)
+	byPrefix := mock_service.NewMockGetAPIKeyByPrefixRepository(c)
+	touch := mock_service.NewMockTouchAPIKeyRepository(c)
+
+	var stored model.APIKey
+	repo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, key model.APIKey) (int, error) {
+			stored = key
+			stored.ID = 7
+			return stored.ID, nil
+		})
+
+	s := NewAPIKeyService(slogdiscard.NewDiscardLogger(), repo, byPrefix, nil, nil, touch)
+
+	plain, key, err := s.CreateAPIKey(context.Background(), 1, "bot", []string{model.ScopePostsWrite}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 7, key.ID)
+	require.True(t, IsAPIKey(plain))
+	require.NotContains(t, stored.Hash, plain)
+
+	prefix, ok := ParsePrefix(plain)
+	require.True(t, ok)
+	require.Equal(t, stored.Prefix, prefix)
+
+	byPrefix.EXPECT().GetAPIKeyByPrefix(gomock.Any(), prefix).Return(stored, nil)
+	touch.EXPECT().TouchAPIKey(gomock.Any(), stored.ID, gomock.Any()).Return(nil)
+
+	authenticated, err := s.Authenticate(context.Background(), plain)
+	require.NoError(t, err)
+	require.Equal(t, 1, authenticated.UserID)
+	require.NotNil(t, authenticated.LastUsedAt)
+}
+
+func TestAPIKeyService_CreateAPIKey_InvalidScope(t *testing.T) {
+	s := NewAPIKeyService(slogdiscard.NewDiscardLogger(), nil, nil, nil, nil, nil)
+
+	_, _, err := s.CreateAPIKey(context.Background(), 1, "bot", []string{"posts:everything"}, nil)
+	require.ErrorIs(t, err, model.ErrUnknownScope)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	const plain = "nck_0123456789ab_" +
+		"0000000000000000000000000000000000000000000000000000000000000000"
+
+	past := time.Now().Add(-time.Hour)
+
+	type mockBehavior func(s *mock_service.MockGetAPIKeyByPrefixRepository)
+
+	tests := []struct {
+		name         string
+		plain        string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:         "Malformed key",
+			plain:        "nck_short",
+			mockBehavior: func(s *mock_service.MockGetAPIKeyByPrefixRepository) {},
+			wantErr:      ErrInvalidAPIKey,
+		},
+		{
+			name:  "Unknown prefix",
+			plain: plain,
+			mockBehavior: func(s *mock_service.MockGetAPIKeyByPrefixRepository) {
+				s.EXPECT().GetAPIKeyByPrefix(gomock.Any(), "0123456789ab").
+					Return(model.APIKey{}, repository.ErrNoAPIKeyWasFound)
+			},
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name:  "Hash mismatch",
+			plain: plain,
+			mockBehavior: func(s *mock_service.MockGetAPIKeyByPrefixRepository) {
+				s.EXPECT().GetAPIKeyByPrefix(gomock.Any(), "0123456789ab").
+					Return(model.APIKey{Hash: hashKey("other")}, nil)
+			},
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name:  "Expired key",
+			plain: plain,
+			mockBehavior: func(s *mock_service.MockGetAPIKeyByPrefixRepository) {
+				s.EXPECT().GetAPIKeyByPrefix(gomock.Any(), "0123456789ab").
+					Return(model.APIKey{Hash: hashKey(plain), ExpiresAt: &past}, nil)
+			},
+			wantErr: ErrAPIKeyExpired,
+		},
+		{
+			name:  "Revoked key",
+			plain: plain,
+			mockBehavior: func(s *mock_service.MockGetAPIKeyByPrefixRepository) {
+				s.EXPECT().GetAPIKeyByPrefix(gomock.Any(), "0123456789ab").
+					Return(model.APIKey{Hash: hashKey(plain), RevokedAt: &past}, nil)
+			},
+			wantErr: ErrAPIKeyRevoked,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			byPrefix := mock_service.NewMockGetAPIKeyByPrefixRepository(c)
+			testCase.mockBehavior(byPrefix)
+
+			s := NewAPIKeyService(slogdiscard.NewDiscardLogger(), nil, byPrefix, nil, nil, nil)
+
+			_, err := s.Authenticate(context.Background(), testCase.plain)
+			require.ErrorIs(t, err, testCase.wantErr)
+		})
+	}
+}
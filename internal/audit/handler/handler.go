@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ananaslegend/news-crud/internal/audit/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type GetEntriesByFilterService interface {
+	GetEntriesByFilter(ctx context.Context, filter model.Filter) (model.Page, error)
+}
+
+type AuditHandler struct {
+	logger *slog.Logger
+
+	getEntriesByFilterService GetEntriesByFilterService
+}
+
+func NewAuditHandler(logger *slog.Logger, getEntriesByFilterService GetEntriesByFilterService) *AuditHandler {
+	return &AuditHandler{
+		logger:                    logger,
+		getEntriesByFilterService: getEntriesByFilterService,
+	}
+}
+
+func (h AuditHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.audit.get_by_filter.handler.GetEntries"
+	logger := h.logger.With(slog.String("op", op))
+
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err = filter.Validation(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	page, err := h.getEntriesByFilterService.GetEntriesByFilter(r.Context(), filter)
+	if err != nil {
+		logger.Error("cant get audit entries", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(page); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func parseFilter(q url.Values) (model.Filter, error) {
+	filter := model.Filter{
+		Action: q.Get("action"),
+		Limit:  model.DefaultLimit,
+	}
+
+	ints := map[string]*int{
+		"actor_id": &filter.ActorID,
+		"post_id":  &filter.PostID,
+		"limit":    &filter.Limit,
+	}
+	for name, dst := range ints {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return model.Filter{}, err
+			}
+			*dst = n
+		}
+	}
+
+	times := map[string]*time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+	for name, dst := range times {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return model.Filter{}, err
+			}
+			*dst = t
+		}
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return model.Filter{}, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionPostCreate       = "post.create"
+	ActionPostUpdate       = "post.update"
+	ActionPostDelete       = "post.delete"
+	ActionPostUpdateDenied = "post.update.denied"
+	ActionPostDeleteDenied = "post.delete.denied"
+	ActionPostImport       = "post.import"
+
+	ActionPostAuthorsUpdate       = "post.authors.update"
+	ActionPostAuthorsUpdateDenied = "post.authors.update.denied"
+)
+
+// Entry is an append-only record of a mutating action. Before and After hold
+// JSON snapshots of the post, they are null when not applicable.
+type Entry struct {
+	ID        int64           `json:"id"`
+	ActorID   int             `json:"actor_id"`
+	Action    string          `json:"action"`
+	PostID    int             `json:"post_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	RequestID string          `json:"request_id"`
+	IP        string          `json:"ip"`
+	CreatedAt time.Time       `json:"created_at"`
+}
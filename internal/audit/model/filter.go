@@ -0,0 +1,45 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+var (
+	ErrFromAfterTo  = errors.New("from should be before to")
+	ErrInvalidLimit = errors.New("limit should be between 1 and 500")
+)
+
+// Filter selects audit entries, zero values do not filter. Entries are returned
+// newest first, Cursor is the ID of the last entry of the previous page.
+type Filter struct {
+	ActorID int
+	PostID  int
+	Action  string
+	From    time.Time
+	To      time.Time
+	Cursor  int64
+	Limit   int
+}
+
+func (f Filter) Validation() error {
+	if !f.From.IsZero() && !f.To.IsZero() && f.From.After(f.To) {
+		return ErrFromAfterTo
+	}
+
+	if f.Limit < 1 || f.Limit > MaxLimit {
+		return ErrInvalidLimit
+	}
+
+	return nil
+}
+
+type Page struct {
+	Entries    []Entry `json:"entries"`
+	NextCursor int64   `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/audit/model"
+	"strconv"
+	"strings"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (ar AuditRepository) CreateEntry(ctx context.Context, entry model.Entry) (int64, error) {
+	const op = "news-crud.internal.audit.create.repository.CreateEntry"
+
+	var entryID int64
+	err := ar.db.QueryRowContext(ctx, `
+		insert into
+		    audit_log (actor_id, action, post_id, before, after, request_id, ip, created_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		returning id
+	`, entry.ActorID, entry.Action, entry.PostID, nullJSON(entry.Before), nullJSON(entry.After),
+		entry.RequestID, entry.IP, entry.CreatedAt,
+	).Scan(&entryID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entryID, nil
+}
+
+func (ar AuditRepository) GetEntriesByFilter(ctx context.Context, filter model.Filter) ([]model.Entry, error) {
+	const op = "news-crud.internal.audit.get_by_filter.repository.GetEntriesByFilter"
+
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.ActorID != 0 {
+		where = append(where, "actor_id = "+arg(filter.ActorID))
+	}
+	if filter.PostID != 0 {
+		where = append(where, "post_id = "+arg(filter.PostID))
+	}
+	if filter.Action != "" {
+		where = append(where, "action = "+arg(filter.Action))
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "created_at <= "+arg(filter.To))
+	}
+	if filter.Cursor != 0 {
+		where = append(where, "id < "+arg(filter.Cursor))
+	}
+
+	query := `
+		select id, actor_id, action, post_id, before, after, request_id, ip, created_at
+		from audit_log`
+	if len(where) > 0 {
+		query += "\n\t\twhere " + strings.Join(where, " and ")
+	}
+	query += "\n\t\torder by id desc\n\t\tlimit " + arg(filter.Limit)
+
+	rows, err := ar.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := make([]model.Entry, 0)
+
+	for rows.Next() {
+		var (
+			entry         model.Entry
+			before, after []byte
+		)
+		err = rows.Scan(&entry.ID, &entry.ActorID, &entry.Action, &entry.PostID, &before, &after,
+			&entry.RequestID, &entry.IP, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		entry.Before, entry.After = before, after
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func nullJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
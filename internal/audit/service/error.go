@@ -0,0 +1,7 @@
+package service
+
+import "errors"
+
+var (
+	ErrCantRecordEntry = errors.New("audit entry can not be recorded")
+)
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/audit/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCreateEntryRepository is a mock of CreateEntryRepository interface.
+type MockCreateEntryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateEntryRepositoryMockRecorder
+}
+
+// MockCreateEntryRepositoryMockRecorder is the mock recorder for MockCreateEntryRepository.
+type MockCreateEntryRepositoryMockRecorder struct {
+	mock *MockCreateEntryRepository
+}
+
+// NewMockCreateEntryRepository creates a new mock instance.
+func NewMockCreateEntryRepository(ctrl *gomock.Controller) *MockCreateEntryRepository {
+	mock := &MockCreateEntryRepository{ctrl: ctrl}
+	mock.recorder = &MockCreateEntryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateEntryRepository) EXPECT() *MockCreateEntryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEntry mocks base method.
+func (m *MockCreateEntryRepository) CreateEntry(ctx context.Context, entry model.Entry) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", ctx, entry)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockCreateEntryRepositoryMockRecorder) CreateEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockCreateEntryRepository)(nil).CreateEntry), ctx, entry)
+}
+
+// MockGetEntriesByFilterRepository is a mock of GetEntriesByFilterRepository interface.
+type MockGetEntriesByFilterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetEntriesByFilterRepositoryMockRecorder
+}
+
+// MockGetEntriesByFilterRepositoryMockRecorder is the mock recorder for MockGetEntriesByFilterRepository.
+type MockGetEntriesByFilterRepositoryMockRecorder struct {
+	mock *MockGetEntriesByFilterRepository
+}
+
+// NewMockGetEntriesByFilterRepository creates a new mock instance.
+func NewMockGetEntriesByFilterRepository(ctrl *gomock.Controller) *MockGetEntriesByFilterRepository {
+	mock := &MockGetEntriesByFilterRepository{ctrl: ctrl}
+	mock.recorder = &MockGetEntriesByFilterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetEntriesByFilterRepository) EXPECT() *MockGetEntriesByFilterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetEntriesByFilter mocks base method.
+func (m *MockGetEntriesByFilterRepository) GetEntriesByFilter(ctx context.Context, filter model.Filter) ([]model.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesByFilter indicates an expected call of GetEntriesByFilter.
+func (mr *MockGetEntriesByFilterRepositoryMockRecorder) GetEntriesByFilter(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesByFilter", reflect.TypeOf((*MockGetEntriesByFilterRepository)(nil).GetEntriesByFilter), ctx, filter)
+}
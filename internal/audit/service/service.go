@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/audit/model"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type CreateEntryRepository interface {
+	CreateEntry(ctx context.Context, entry model.Entry) (int64, error)
+}
+
+type GetEntriesByFilterRepository interface {
+	GetEntriesByFilter(ctx context.Context, filter model.Filter) ([]model.Entry, error)
+}
+
+type AuditService struct {
+	logger *slog.Logger
+
+	createEntryRepository        CreateEntryRepository
+	getEntriesByFilterRepository GetEntriesByFilterRepository
+}
+
+func NewAuditService(
+	logger *slog.Logger,
+	createEntryRepository CreateEntryRepository,
+	getEntriesByFilterRepository GetEntriesByFilterRepository,
+) *AuditService {
+	return &AuditService{
+		logger:                       logger,
+		createEntryRepository:        createEntryRepository,
+		getEntriesByFilterRepository: getEntriesByFilterRepository,
+	}
+}
+
+// Record appends an entry for the action. The request ID and the client IP are
+// taken from the context, before and after are stored as JSON snapshots, nil
+// snapshots are stored as null.
+func (s AuditService) Record(ctx context.Context, actorID int, action string, postID int, before, after any) error {
+	const op = "news-crud.internal.audit.record.service.Record"
+	logger := s.logger.With(slog.String("op", op))
+
+	entry := model.Entry{
+		ActorID:   actorID,
+		Action:    action,
+		PostID:    postID,
+		RequestID: contexts.GetRequestID(ctx),
+		CreatedAt: time.Now(),
+	}
+	entry.IP, _ = contexts.GetClientIP(ctx)
+
+	var err error
+	if entry.Before, err = snapshot(before); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// the entry must outlive a cancelled request, the action has already happened
+	if _, err = s.createEntryRepository.CreateEntry(context.WithoutCancel(ctx), entry); err != nil {
+		logger.Error("cant record audit entry",
+			slog.String("action", action), slog.Int("post_id", postID), slog.Int("actor_id", actorID), logs.Err(err))
+		return ErrCantRecordEntry
+	}
+
+	return nil
+}
+
+func (s AuditService) GetEntriesByFilter(ctx context.Context, filter model.Filter) (model.Page, error) {
+	const op = "news-crud.internal.audit.get_by_filter.service.GetEntriesByFilter"
+
+	entries, err := s.getEntriesByFilterRepository.GetEntriesByFilter(ctx, filter)
+	if err != nil {
+		return model.Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := model.Page{Entries: entries}
+	if len(entries) == filter.Limit {
+		page.NextCursor = entries[len(entries)-1].ID
+	}
+
+	return page, nil
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/audit/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/audit/service/mocks"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_service.NewMockCreateEntryRepository(c)
+	repo.EXPECT().CreateEntry(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, entry model.Entry) (int64, error) {
+			require.Equal(t, 1, entry.ActorID)
+			require.Equal(t, model.ActionPostUpdate, entry.Action)
+			require.Equal(t, 2, entry.PostID)
+			require.JSONEq(t, `{"title":"old"}`, string(entry.Before))
+			require.JSONEq(t, `{"title":"new"}`, string(entry.After))
+			require.Equal(t, "req-1", entry.RequestID)
+			require.Equal(t, "10.0.0.1", entry.IP)
+			return 1, nil
+		})
+
+	s := NewAuditService(slogdiscard.NewDiscardLogger(), repo, nil)
+
+	ctx := contexts.SetRequestID(context.Background(), "req-1")
+	ctx = contexts.SetClientIP(ctx, "10.0.0.1")
+
+	err := s.Record(ctx, 1, model.ActionPostUpdate, 2,
+		map[string]string{"title": "old"}, map[string]string{"title": "new"})
+	require.NoError(t, err)
+}
+
+func TestAuditService_Record_NilSnapshots(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_service.NewMockCreateEntryRepository(c)
+	repo.EXPECT().CreateEntry(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, entry model.Entry) (int64, error) {
+			require.Nil(t, entry.Before)
+			require.Nil(t, entry.After)
+			return 0, errors.New("db is down")
+		})
+
+	s := NewAuditService(slogdiscard.NewDiscardLogger(), repo, nil)
+
+	err := s.Record(context.Background(), 1, model.ActionPostDeleteDenied, 2, nil, nil)
+	require.ErrorIs(t, err, ErrCantRecordEntry)
+}
+
+func TestAuditService_GetEntriesByFilter(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_service.NewMockGetEntriesByFilterRepository(c)
+	repo.EXPECT().GetEntriesByFilter(gomock.Any(), model.Filter{Limit: 2}).
+		Return([]model.Entry{{ID: 9}, {ID: 7}}, nil)
+	repo.EXPECT().GetEntriesByFilter(gomock.Any(), model.Filter{Limit: 2, Cursor: 7}).
+		Return([]model.Entry{{ID: 3}}, nil)
+
+	s := NewAuditService(slogdiscard.NewDiscardLogger(), nil, repo)
+
+	page, err := s.GetEntriesByFilter(context.Background(), model.Filter{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(7), page.NextCursor)
+
+	page, err = s.GetEntriesByFilter(context.Background(), model.Filter{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.Zero(t, page.NextCursor)
+}
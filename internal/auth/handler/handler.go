@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/auth/service"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	LoginStateCookie = "oidc_login"
+
+	loginStateCookiePath = "/auth/oidc"
+)
+
+type BeginLoginService interface {
+	BeginLogin() (string, string, error)
+}
+
+type CompleteLoginService interface {
+	CompleteLogin(ctx context.Context, signedState, state, code string) (string, error)
+}
+
+type AuthHandler struct {
+	logger   *slog.Logger
+	tokenTTL time.Duration
+
+	beginLoginService    BeginLoginService
+	completeLoginService CompleteLoginService
+}
+
+func NewAuthHandler(
+	logger *slog.Logger,
+	tokenTTL time.Duration,
+	beginLoginService BeginLoginService,
+	completeLoginService CompleteLoginService,
+) *AuthHandler {
+	return &AuthHandler{
+		logger:               logger,
+		tokenTTL:             tokenTTL,
+		beginLoginService:    beginLoginService,
+		completeLoginService: completeLoginService,
+	}
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (h AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.auth.login.handler.Login"
+	logger := h.logger.With(slog.String("op", op))
+
+	redirectURL, state, err := h.beginLoginService.BeginLogin()
+	if err != nil {
+		logger.Error("cant begin login", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     LoginStateCookie,
+		Value:    state,
+		Path:     loginStateCookiePath,
+		MaxAge:   int(service.LoginStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+func (h AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.auth.callback.handler.Callback"
+	logger := h.logger.With(slog.String("op", op))
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		// the parameter comes from whoever sent the browser here, it is
+		// logged and never written back
+		logger.Warn("provider refused the login", slog.String("error", providerErr))
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("login was refused by the provider"))
+		return
+	}
+
+	cookie, err := r.Cookie(LoginStateCookie)
+	if err != nil || query.Get("code") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     LoginStateCookie,
+		Path:     loginStateCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	token, err := h.completeLoginService.CompleteLogin(r.Context(), cookie.Value, query.Get("state"), query.Get("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidLoginState):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, service.ErrLoginFailed):
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			logger.Error("cant complete login", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(TokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(h.tokenTTL.Seconds()),
+	}); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Identity links a subject of an external identity provider to a local user.
+type Identity struct {
+	UserID    int
+	Issuer    string
+	Subject   string
+	Email     string
+	Name      string
+	CreatedAt time.Time
+}
+
+func NewIdentity(issuer, subject, email, name string) Identity {
+	return Identity{
+		Issuer:    issuer,
+		Subject:   subject,
+		Email:     email,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+var (
+	ErrNoIdentityWasFound = errors.New("identity not found")
+)
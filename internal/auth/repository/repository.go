@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/auth/model"
+	"time"
+)
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// GetUserIDByIdentity returns the local user the identity belongs to and records the login.
+func (ir IdentityRepository) GetUserIDByIdentity(ctx context.Context, issuer, subject string) (int, error) {
+	const op = "news-crud.internal.auth.get_by_identity.repository.GetUserIDByIdentity"
+
+	var userID int
+	err := ir.db.QueryRowContext(ctx, `
+		update user_identities
+		set last_login_at = $1
+		where issuer = $2 and subject = $3
+		returning user_id
+	`, time.Now(), issuer, subject).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNoIdentityWasFound
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+// CreateUserWithIdentity creates a local user for a first time login. When a concurrent
+// login has already linked the identity, its user is returned instead.
+func (ir IdentityRepository) CreateUserWithIdentity(ctx context.Context, identity model.Identity) (int, error) {
+	const op = "news-crud.internal.auth.create.repository.CreateUserWithIdentity"
+
+	tx, err := ir.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var userID int
+	if err = tx.QueryRowContext(ctx, `
+		insert into
+		    users (email, name, created_at)
+		values ($1, $2, $3)
+		returning id
+	`, identity.Email, identity.Name, identity.CreatedAt).Scan(&userID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		insert into
+		    user_identities (issuer, subject, user_id, created_at, last_login_at)
+		values ($1, $2, $3, $4, $4)
+		on conflict (issuer, subject) do nothing
+	`, identity.Issuer, identity.Subject, userID, identity.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		_ = tx.Rollback()
+		return ir.GetUserIDByIdentity(ctx, identity.Issuer, identity.Subject)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
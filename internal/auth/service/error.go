@@ -0,0 +1,9 @@
+package service
+
+import "errors"
+
+var (
+	ErrInvalidLoginState = errors.New("invalid or expired login state")
+	ErrLoginFailed       = errors.New("login failed")
+	ErrCantStartLogin    = errors.New("login can not be started")
+)
@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/auth/model"
+	oidc "github.com/ananaslegend/news-crud/pkg/oidc"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOIDCProvider is a mock of OIDCProvider interface.
+type MockOIDCProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCProviderMockRecorder
+}
+
+// MockOIDCProviderMockRecorder is the mock recorder for MockOIDCProvider.
+type MockOIDCProviderMockRecorder struct {
+	mock *MockOIDCProvider
+}
+
+// NewMockOIDCProvider creates a new mock instance.
+func NewMockOIDCProvider(ctrl *gomock.Controller) *MockOIDCProvider {
+	mock := &MockOIDCProvider{ctrl: ctrl}
+	mock.recorder = &MockOIDCProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCProvider) EXPECT() *MockOIDCProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockOIDCProvider) AuthCodeURL(state, nonce, verifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, nonce, verifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockOIDCProviderMockRecorder) AuthCodeURL(state, nonce, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockOIDCProvider)(nil).AuthCodeURL), state, nonce, verifier)
+}
+
+// Exchange mocks base method.
+func (m *MockOIDCProvider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, verifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockOIDCProviderMockRecorder) Exchange(ctx, code, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockOIDCProvider)(nil).Exchange), ctx, code, verifier)
+}
+
+// Issuer mocks base method.
+func (m *MockOIDCProvider) Issuer() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issuer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Issuer indicates an expected call of Issuer.
+func (mr *MockOIDCProviderMockRecorder) Issuer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issuer", reflect.TypeOf((*MockOIDCProvider)(nil).Issuer))
+}
+
+// Verify mocks base method.
+func (m *MockOIDCProvider) Verify(ctx context.Context, raw, nonce, groupsClaim string) (oidc.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, raw, nonce, groupsClaim)
+	ret0, _ := ret[0].(oidc.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockOIDCProviderMockRecorder) Verify(ctx, raw, nonce, groupsClaim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockOIDCProvider)(nil).Verify), ctx, raw, nonce, groupsClaim)
+}
+
+// MockGetUserIDByIdentityRepository is a mock of GetUserIDByIdentityRepository interface.
+type MockGetUserIDByIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetUserIDByIdentityRepositoryMockRecorder
+}
+
+// MockGetUserIDByIdentityRepositoryMockRecorder is the mock recorder for MockGetUserIDByIdentityRepository.
+type MockGetUserIDByIdentityRepositoryMockRecorder struct {
+	mock *MockGetUserIDByIdentityRepository
+}
+
+// NewMockGetUserIDByIdentityRepository creates a new mock instance.
+func NewMockGetUserIDByIdentityRepository(ctrl *gomock.Controller) *MockGetUserIDByIdentityRepository {
+	mock := &MockGetUserIDByIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockGetUserIDByIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetUserIDByIdentityRepository) EXPECT() *MockGetUserIDByIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUserIDByIdentity mocks base method.
+func (m *MockGetUserIDByIdentityRepository) GetUserIDByIdentity(ctx context.Context, issuer, subject string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDByIdentity", ctx, issuer, subject)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDByIdentity indicates an expected call of GetUserIDByIdentity.
+func (mr *MockGetUserIDByIdentityRepositoryMockRecorder) GetUserIDByIdentity(ctx, issuer, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDByIdentity", reflect.TypeOf((*MockGetUserIDByIdentityRepository)(nil).GetUserIDByIdentity), ctx, issuer, subject)
+}
+
+// MockCreateUserWithIdentityRepository is a mock of CreateUserWithIdentityRepository interface.
+type MockCreateUserWithIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateUserWithIdentityRepositoryMockRecorder
+}
+
+// MockCreateUserWithIdentityRepositoryMockRecorder is the mock recorder for MockCreateUserWithIdentityRepository.
+type MockCreateUserWithIdentityRepositoryMockRecorder struct {
+	mock *MockCreateUserWithIdentityRepository
+}
+
+// NewMockCreateUserWithIdentityRepository creates a new mock instance.
+func NewMockCreateUserWithIdentityRepository(ctrl *gomock.Controller) *MockCreateUserWithIdentityRepository {
+	mock := &MockCreateUserWithIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockCreateUserWithIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateUserWithIdentityRepository) EXPECT() *MockCreateUserWithIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateUserWithIdentity mocks base method.
+func (m *MockCreateUserWithIdentityRepository) CreateUserWithIdentity(ctx context.Context, identity model.Identity) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWithIdentity", ctx, identity)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithIdentity indicates an expected call of CreateUserWithIdentity.
+func (mr *MockCreateUserWithIdentityRepositoryMockRecorder) CreateUserWithIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithIdentity", reflect.TypeOf((*MockCreateUserWithIdentityRepository)(nil).CreateUserWithIdentity), ctx, identity)
+}
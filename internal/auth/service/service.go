@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/auth/model"
+	"github.com/ananaslegend/news-crud/internal/auth/repository"
+	"github.com/ananaslegend/news-crud/pkg/jwt"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/oidc"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+const (
+	// LoginStateTTL is how long a login may take, from BeginLogin to the callback.
+	LoginStateTTL = 10 * time.Minute
+)
+
+type OIDCProvider interface {
+	Issuer() string
+	AuthCodeURL(state, nonce, verifier string) string
+	Exchange(ctx context.Context, code, verifier string) (string, error)
+	Verify(ctx context.Context, raw, nonce, groupsClaim string) (oidc.Claims, error)
+}
+
+type GetUserIDByIdentityRepository interface {
+	GetUserIDByIdentity(ctx context.Context, issuer, subject string) (int, error)
+}
+
+type CreateUserWithIdentityRepository interface {
+	CreateUserWithIdentity(ctx context.Context, identity model.Identity) (int, error)
+}
+
+type Config struct {
+	// GroupsClaim is the ID token claim holding the user groups.
+	GroupsClaim string
+	// GroupRoles maps IdP groups to local roles.
+	GroupRoles map[string]string
+	// TokenTTL is how long the issued access token is valid.
+	TokenTTL time.Duration
+	// Secret signs the login state and the issued access tokens.
+	Secret string
+}
+
+type AuthService struct {
+	logger   *slog.Logger
+	config   Config
+	provider OIDCProvider
+
+	getUserIDByIdentityRepository    GetUserIDByIdentityRepository
+	createUserWithIdentityRepository CreateUserWithIdentityRepository
+}
+
+func NewAuthService(
+	logger *slog.Logger,
+	config Config,
+	provider OIDCProvider,
+	getUserIDByIdentityRepository GetUserIDByIdentityRepository,
+	createUserWithIdentityRepository CreateUserWithIdentityRepository,
+) *AuthService {
+	return &AuthService{
+		logger:                           logger,
+		config:                           config,
+		provider:                         provider,
+		getUserIDByIdentityRepository:    getUserIDByIdentityRepository,
+		createUserWithIdentityRepository: createUserWithIdentityRepository,
+	}
+}
+
+type loginState struct {
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// BeginLogin returns the IdP authorization URL together with the signed login
+// state, which the caller must hand back to CompleteLogin (it is kept in a cookie).
+func (s AuthService) BeginLogin() (string, string, error) {
+	const op = "news-crud.internal.auth.login.service.BeginLogin"
+	logger := s.logger.With(slog.String("op", op))
+
+	var (
+		ls  = loginState{ExpiresAt: time.Now().Add(LoginStateTTL).Unix()}
+		err error
+	)
+
+	for _, v := range []*string{&ls.State, &ls.Nonce, &ls.Verifier} {
+		if *v, err = oidc.NewVerifier(); err != nil {
+			logger.Error("cant generate login state", logs.Err(err))
+			return "", "", ErrCantStartLogin
+		}
+	}
+
+	signed, err := s.signState(ls)
+	if err != nil {
+		logger.Error("cant sign login state", logs.Err(err))
+		return "", "", ErrCantStartLogin
+	}
+
+	return s.provider.AuthCodeURL(ls.State, ls.Nonce, ls.Verifier), signed, nil
+}
+
+// CompleteLogin finishes the authorization code flow and issues a local access token.
+func (s AuthService) CompleteLogin(ctx context.Context, signedState, state, code string) (string, error) {
+	const op = "news-crud.internal.auth.callback.service.CompleteLogin"
+	logger := s.logger.With(slog.String("op", op))
+
+	ls, err := s.verifyState(signedState)
+	if err != nil || !hmac.Equal([]byte(ls.State), []byte(state)) {
+		return "", ErrInvalidLoginState
+	}
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, ls.Verifier)
+	if err != nil {
+		logger.Error("cant exchange code", logs.Err(err))
+		return "", ErrLoginFailed
+	}
+
+	claims, err := s.provider.Verify(ctx, rawIDToken, ls.Nonce, s.config.GroupsClaim)
+	if err != nil {
+		logger.Error("cant verify id token", logs.Err(err))
+		return "", ErrLoginFailed
+	}
+
+	userID, err := s.userID(ctx, claims)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := jwt.NewToken(userID, s.Roles(claims.Groups), s.config.TokenTTL, []byte(s.config.Secret))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// Roles maps IdP groups onto local roles, unknown groups are ignored.
+func (s AuthService) Roles(groups []string) []string {
+	roles := make([]string, 0)
+	for _, group := range groups {
+		role, ok := s.config.GroupRoles[group]
+		if ok && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	slices.Sort(roles)
+
+	return roles
+}
+
+func (s AuthService) userID(ctx context.Context, claims oidc.Claims) (int, error) {
+	userID, err := s.getUserIDByIdentityRepository.GetUserIDByIdentity(ctx, s.provider.Issuer(), claims.Subject)
+	if err == nil {
+		return userID, nil
+	}
+
+	if !errors.Is(err, repository.ErrNoIdentityWasFound) {
+		return 0, err
+	}
+
+	return s.createUserWithIdentityRepository.CreateUserWithIdentity(ctx,
+		model.NewIdentity(s.provider.Issuer(), claims.Subject, claims.Email, claims.Name))
+}
+
+func (s AuthService) signState(ls loginState) (string, error) {
+	payload, err := json.Marshal(ls)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + s.mac(encoded), nil
+}
+
+func (s AuthService) verifyState(signed string) (loginState, error) {
+	encoded, mac, ok := strings.Cut(signed, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(s.mac(encoded))) {
+		return loginState{}, ErrInvalidLoginState
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return loginState{}, ErrInvalidLoginState
+	}
+
+	var ls loginState
+	if err = json.Unmarshal(payload, &ls); err != nil {
+		return loginState{}, ErrInvalidLoginState
+	}
+
+	if time.Now().Unix() > ls.ExpiresAt {
+		return loginState{}, ErrInvalidLoginState
+	}
+
+	return ls, nil
+}
+
+func (s AuthService) mac(payload string) string {
+	h := hmac.New(sha256.New, []byte("oidc-login-state:"+s.config.Secret))
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
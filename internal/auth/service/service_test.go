@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/auth/model"
+	"github.com/ananaslegend/news-crud/internal/auth/repository"
+	mock_service "github.com/ananaslegend/news-crud/internal/auth/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/jwt"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/oidc"
+	"github.com/ananaslegend/news-crud/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	secret      = "secret"
+	redirectURL = "http://news.local/auth/oidc/callback"
+)
+
+func newTestAuthService(t *testing.T, c *gomock.Controller) (
+	*AuthService,
+	*oidctest.Provider,
+	*mock_service.MockGetUserIDByIdentityRepository,
+	*mock_service.MockCreateUserWithIdentityRepository,
+) {
+	idp := oidctest.NewProvider("news", "news-secret")
+	t.Cleanup(idp.Close)
+
+	provider, err := oidc.Discover(context.Background(), idp.Server.Client(), idp.Issuer(), oidc.Config{
+		ClientID:     "news",
+		ClientSecret: "news-secret",
+		RedirectURL:  redirectURL,
+	})
+	require.NoError(t, err)
+
+	getRepo := mock_service.NewMockGetUserIDByIdentityRepository(c)
+	createRepo := mock_service.NewMockCreateUserWithIdentityRepository(c)
+
+	s := NewAuthService(
+		slogdiscard.NewDiscardLogger(),
+		Config{
+			GroupsClaim: "groups",
+			GroupRoles:  map[string]string{"news-admins": model.RoleAdmin, "news-editors": model.RoleEditor},
+			TokenTTL:    time.Hour,
+			Secret:      secret,
+		},
+		provider,
+		getRepo,
+		createRepo,
+	)
+
+	return s, idp, getRepo, createRepo
+}
+
+// authorize follows the authorization URL and returns the callback query.
+func authorize(t *testing.T, idp *oidctest.Provider, authURL string) url.Values {
+	client := idp.Server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	return location.Query()
+}
+
+func TestAuthService_Login(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, idp, getRepo, createRepo := newTestAuthService(t, c)
+
+	idp.SignIn(oidctest.User{
+		Subject: "staff-1",
+		Email:   "staff@news.local",
+		Name:    "Staff",
+		Groups:  []string{"news-editors", "everyone"},
+	})
+
+	getRepo.EXPECT().GetUserIDByIdentity(gomock.Any(), idp.Issuer(), "staff-1").
+		Return(0, repository.ErrNoIdentityWasFound)
+	createRepo.EXPECT().CreateUserWithIdentity(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, identity model.Identity) (int, error) {
+			require.Equal(t, "staff@news.local", identity.Email)
+			return 42, nil
+		})
+
+	authURL, state, err := s.BeginLogin()
+	require.NoError(t, err)
+
+	callback := authorize(t, idp, authURL)
+
+	token, err := s.CompleteLogin(context.Background(), state, callback.Get("state"), callback.Get("code"))
+	require.NoError(t, err)
+
+	claims, err := jwt.ParseToken(token, []byte(secret))
+	require.NoError(t, err)
+	require.Equal(t, 42, claims.UserID)
+	require.Equal(t, []string{model.RoleEditor}, claims.Roles)
+}
+
+func TestAuthService_CompleteLogin_StateMismatch(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, idp, _, _ := newTestAuthService(t, c)
+	idp.SignIn(oidctest.User{Subject: "staff-1"})
+
+	authURL, _, err := s.BeginLogin()
+	require.NoError(t, err)
+	_, otherState, err := s.BeginLogin()
+	require.NoError(t, err)
+
+	callback := authorize(t, idp, authURL)
+
+	_, err = s.CompleteLogin(context.Background(), otherState, callback.Get("state"), callback.Get("code"))
+	require.ErrorIs(t, err, ErrInvalidLoginState)
+}
+
+func TestAuthService_CompleteLogin_TamperedState(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, _, _, _ := newTestAuthService(t, c)
+
+	_, state, err := s.BeginLogin()
+	require.NoError(t, err)
+
+	_, err = s.CompleteLogin(context.Background(), "x"+state, "state", "code")
+	require.ErrorIs(t, err, ErrInvalidLoginState)
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/comment/model"
+	"github.com/ananaslegend/news-crud/internal/comment/service"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type CreateCommentService interface {
+	CreateComment(ctx context.Context, userID, postID int, parentID *int, content string) (int, error)
+}
+
+type GetCommentsByPostIDService interface {
+	GetCommentsByPostID(ctx context.Context, postID int, page model.Pagination) (model.Page, error)
+}
+
+type GetModerationQueueService interface {
+	GetModerationQueue(ctx context.Context, page model.Pagination) (model.Page, error)
+}
+
+type UpdateCommentService interface {
+	UpdateComment(ctx context.Context, userID, commentID int, content string) error
+}
+
+type DeleteCommentService interface {
+	DeleteComment(ctx context.Context, userID, commentID int) error
+}
+
+type ModerateCommentService interface {
+	ApproveComment(ctx context.Context, moderatorID, commentID int) error
+	RejectComment(ctx context.Context, moderatorID, commentID int) error
+}
+
+type CommentHandler struct {
+	logger *slog.Logger
+
+	createCommentService       CreateCommentService
+	getCommentsByPostIDService GetCommentsByPostIDService
+	getModerationQueueService  GetModerationQueueService
+	updateCommentService       UpdateCommentService
+	deleteCommentService       DeleteCommentService
+	moderateCommentService     ModerateCommentService
+}
+
+func NewCommentHandler(
+	logger *slog.Logger,
+	createCommentService CreateCommentService,
+	getCommentsByPostIDService GetCommentsByPostIDService,
+	getModerationQueueService GetModerationQueueService,
+	updateCommentService UpdateCommentService,
+	deleteCommentService DeleteCommentService,
+	moderateCommentService ModerateCommentService,
+) *CommentHandler {
+	return &CommentHandler{
+		logger:                     logger,
+		createCommentService:       createCommentService,
+		getCommentsByPostIDService: getCommentsByPostIDService,
+		getModerationQueueService:  getModerationQueueService,
+		updateCommentService:       updateCommentService,
+		deleteCommentService:       deleteCommentService,
+		moderateCommentService:     moderateCommentService,
+	}
+}
+
+type CreateCommentRequest struct {
+	Content  string `json:"content" validate:"required,max=10000"`
+	ParentID *int   `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+func (h CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.create.handler.CreateComment"
+	logger := h.logger.With(slog.String("op", op))
+
+	postID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || postID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var req CreateCommentRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("cant decode request", logs.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err = validator.New().Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	commentID, err := h.createCommentService.CreateComment(r.Context(), userID, postID, req.ParentID, req.Content)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoPostWasFound), errors.Is(err, service.ErrNoCommentWasFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, service.ErrParentOnOtherPost):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		default:
+			logger.Error("cant create comment", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(map[string]any{
+		"comment_id": commentID,
+		"status":     model.StatusPending,
+	}); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func (h CommentHandler) GetCommentsByPostID(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.get_by_post_id.handler.GetCommentsByPostID"
+	logger := h.logger.With(slog.String("op", op))
+
+	postID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || postID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	page, err := parsePagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	comments, err := h.getCommentsByPostIDService.GetCommentsByPostID(r.Context(), postID, page)
+	if err != nil {
+		logger.Error("cant get comments", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, logger, comments)
+}
+
+func (h CommentHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.get_pending.handler.GetModerationQueue"
+	logger := h.logger.With(slog.String("op", op))
+
+	page, err := parsePagination(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	comments, err := h.getModerationQueueService.GetModerationQueue(r.Context(), page)
+	if err != nil {
+		logger.Error("cant get moderation queue", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, logger, comments)
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" validate:"required,max=10000"`
+}
+
+func (h CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.update.handler.UpdateComment"
+	logger := h.logger.With(slog.String("op", op))
+
+	commentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || commentID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("cant decode request", logs.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err = validator.New().Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	err = h.updateCommentService.UpdateComment(r.Context(), userID, commentID, req.Content)
+	h.writeStatus(w, logger, err, http.StatusOK)
+}
+
+func (h CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.delete.handler.DeleteComment"
+	logger := h.logger.With(slog.String("op", op))
+
+	commentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || commentID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	err = h.deleteCommentService.DeleteComment(r.Context(), userID, commentID)
+	h.writeStatus(w, logger, err, http.StatusNoContent)
+}
+
+func (h CommentHandler) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.moderate.handler.ApproveComment"
+	h.moderate(w, r, h.logger.With(slog.String("op", op)), h.moderateCommentService.ApproveComment)
+}
+
+func (h CommentHandler) RejectComment(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.comment.moderate.handler.RejectComment"
+	h.moderate(w, r, h.logger.With(slog.String("op", op)), h.moderateCommentService.RejectComment)
+}
+
+func (h CommentHandler) moderate(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *slog.Logger,
+	moderate func(ctx context.Context, moderatorID, commentID int) error,
+) {
+	commentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || commentID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	moderatorID := contexts.MustGetUserID(r.Context())
+
+	err = moderate(r.Context(), moderatorID, commentID)
+	h.writeStatus(w, logger, err, http.StatusOK)
+}
+
+func (h CommentHandler) writeStatus(w http.ResponseWriter, logger *slog.Logger, err error, okStatus int) {
+	switch {
+	case err == nil:
+		w.WriteHeader(okStatus)
+	case errors.Is(err, service.ErrNoCommentWasFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrUserHasNoPermission):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, service.ErrCommentIsDeleted):
+		w.WriteHeader(http.StatusGone)
+	default:
+		logger.Error("cant process comment", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (h CommentHandler) writeJSON(w http.ResponseWriter, logger *slog.Logger, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func parsePagination(r *http.Request) (model.Pagination, error) {
+	page := model.Pagination{Limit: model.DefaultLimit}
+
+	var err error
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if page.Limit, err = strconv.Atoi(v); err != nil {
+			return model.Pagination{}, err
+		}
+	}
+
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		if page.Cursor, err = strconv.Atoi(v); err != nil {
+			return model.Pagination{}, err
+		}
+	}
+
+	return page, page.Validation()
+}
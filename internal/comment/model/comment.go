@@ -0,0 +1,79 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidLimit = errors.New("limit should be between 1 and 100")
+)
+
+// Comment is a reader comment on a post. Top level comments have no ParentID
+// and no RootID, replies point to their parent and to the top level comment
+// of the thread. New and edited comments wait for moderation.
+type Comment struct {
+	ID        int        `json:"id"`
+	PostID    int        `json:"post_id"`
+	ParentID  *int       `json:"parent_id,omitempty"`
+	RootID    *int       `json:"-"`
+	AuthorID  int        `json:"author_id"`
+	Content   string     `json:"content"`
+	Status    string     `json:"status"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Replies   []*Comment `json:"replies,omitempty"`
+}
+
+func NewComment(postID, authorID int, content string) Comment {
+	now := time.Now()
+
+	return Comment{
+		PostID:    postID,
+		AuthorID:  authorID,
+		Content:   content,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// ReplyTo makes the comment a reply in the thread of parent.
+func (c *Comment) ReplyTo(parent Comment) {
+	c.ParentID = &parent.ID
+
+	rootID := parent.ID
+	if parent.RootID != nil {
+		rootID = *parent.RootID
+	}
+	c.RootID = &rootID
+}
+
+// Page is a page of comment threads, Cursor of the next page is the ID of the
+// last comment of this page.
+type Page struct {
+	Comments   []*Comment `json:"comments"`
+	NextCursor int        `json:"next_cursor,omitempty"`
+}
+
+type Pagination struct {
+	Cursor int
+	Limit  int
+}
+
+func (p Pagination) Validation() error {
+	if p.Limit < 1 || p.Limit > MaxLimit {
+		return ErrInvalidLimit
+	}
+	return nil
+}
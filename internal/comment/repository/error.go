@@ -0,0 +1,8 @@
+package repository
+
+import "errors"
+
+var (
+	ErrNoCommentWasFound = errors.New("comment not found")
+	ErrNoPostWasFound    = errors.New("post not found")
+)
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/comment/model"
+	"github.com/lib/pq"
+	"time"
+)
+
+const foreignKeyViolation = "23503"
+
+const selectComments = `
+		select id, post_id, parent_id, root_id, author_id, content, status, deleted_at is not null, created_at, updated_at
+		from comments`
+
+type CommentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (cr CommentRepository) CreateComment(ctx context.Context, comment model.Comment) (int, error) {
+	const op = "news-crud.internal.comment.create.repository.CreateComment"
+
+	var commentID int
+	err := cr.db.QueryRowContext(ctx, `
+		insert into
+		    comments (post_id, parent_id, root_id, author_id, content, status, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		returning id
+	`, comment.PostID, comment.ParentID, comment.RootID, comment.AuthorID, comment.Content, comment.Status,
+		comment.CreatedAt, comment.UpdatedAt,
+	).Scan(&commentID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return 0, ErrNoPostWasFound
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return commentID, nil
+}
+
+func (cr CommentRepository) GetCommentByID(ctx context.Context, id int) (model.Comment, error) {
+	const op = "news-crud.internal.comment.get_by_id.repository.GetCommentByID"
+
+	comment, err := scanComment(cr.db.QueryRowContext(ctx, selectComments+`
+		where id = $1
+	`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Comment{}, ErrNoCommentWasFound
+		}
+
+		return model.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comment, nil
+}
+
+// GetThreadsByPostID returns a page of approved top level comments of the post
+// followed by all approved replies in their threads, ordered by ID.
+func (cr CommentRepository) GetThreadsByPostID(ctx context.Context, postID int, page model.Pagination) ([]model.Comment, error) {
+	const op = "news-crud.internal.comment.get_by_post_id.repository.GetThreadsByPostID"
+
+	rows, err := cr.db.QueryContext(ctx, `
+		with roots as (
+			select id
+			from comments
+			where post_id = $1 and parent_id is null and status = 'approved' and id > $2
+			order by id
+			limit $3
+		)`+selectComments+`
+		where id in (select id from roots)
+			or (root_id in (select id from roots) and status = 'approved')
+		order by id
+	`, postID, page.Cursor, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	comments, err := scanComments(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comments, nil
+}
+
+// GetPendingComments returns the moderation queue, oldest first.
+func (cr CommentRepository) GetPendingComments(ctx context.Context, page model.Pagination) ([]model.Comment, error) {
+	const op = "news-crud.internal.comment.get_pending.repository.GetPendingComments"
+
+	rows, err := cr.db.QueryContext(ctx, selectComments+`
+		where status = 'pending' and deleted_at is null and id > $1
+		order by id
+		limit $2
+	`, page.Cursor, page.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	comments, err := scanComments(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comments, nil
+}
+
+// UpdateCommentContent changes the content and sends the comment back to moderation.
+func (cr CommentRepository) UpdateCommentContent(ctx context.Context, id int, content string, updatedAt time.Time) error {
+	const op = "news-crud.internal.comment.update.repository.UpdateCommentContent"
+
+	return cr.exec(ctx, op, `
+		update comments
+		set content = $1, updated_at = $2, status = 'pending', moderated_by = null, moderated_at = null
+		where id = $3 and deleted_at is null
+	`, content, updatedAt, id)
+}
+
+// DeleteComment soft deletes the comment, so its replies stay in the thread.
+func (cr CommentRepository) DeleteComment(ctx context.Context, id int, deletedAt time.Time) error {
+	const op = "news-crud.internal.comment.delete.repository.DeleteComment"
+
+	return cr.exec(ctx, op, `
+		update comments
+		set content = '', deleted_at = $1
+		where id = $2 and deleted_at is null
+	`, deletedAt, id)
+}
+
+func (cr CommentRepository) ModerateComment(ctx context.Context, id int, status string, moderatorID int, moderatedAt time.Time) error {
+	const op = "news-crud.internal.comment.moderate.repository.ModerateComment"
+
+	return cr.exec(ctx, op, `
+		update comments
+		set status = $1, moderated_by = $2, moderated_at = $3
+		where id = $4 and deleted_at is null
+	`, status, moderatorID, moderatedAt, id)
+}
+
+func (cr CommentRepository) exec(ctx context.Context, op, query string, args ...any) error {
+	res, err := cr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return ErrNoCommentWasFound
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanComment(row rowScanner) (model.Comment, error) {
+	var (
+		comment  model.Comment
+		parentID sql.NullInt64
+		rootID   sql.NullInt64
+	)
+
+	err := row.Scan(&comment.ID, &comment.PostID, &parentID, &rootID, &comment.AuthorID, &comment.Content,
+		&comment.Status, &comment.Deleted, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	if rootID.Valid {
+		id := int(rootID.Int64)
+		comment.RootID = &id
+	}
+
+	return comment, nil
+}
+
+func scanComments(rows *sql.Rows) ([]model.Comment, error) {
+	defer rows.Close()
+
+	comments := make([]model.Comment, 0)
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
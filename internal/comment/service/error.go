@@ -0,0 +1,12 @@
+package service
+
+import "errors"
+
+var (
+	ErrCantCreateComment   = errors.New("comment can not be created")
+	ErrNoCommentWasFound   = errors.New("comment not found")
+	ErrNoPostWasFound      = errors.New("post not found")
+	ErrUserHasNoPermission = errors.New("user has no permission")
+	ErrParentOnOtherPost   = errors.New("parent comment belongs to another post")
+	ErrCommentIsDeleted    = errors.New("comment is deleted")
+)
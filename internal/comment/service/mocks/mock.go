@@ -0,0 +1,282 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ananaslegend/news-crud/internal/comment/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCreateCommentRepository is a mock of CreateCommentRepository interface.
+type MockCreateCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateCommentRepositoryMockRecorder
+}
+
+// MockCreateCommentRepositoryMockRecorder is the mock recorder for MockCreateCommentRepository.
+type MockCreateCommentRepositoryMockRecorder struct {
+	mock *MockCreateCommentRepository
+}
+
+// NewMockCreateCommentRepository creates a new mock instance.
+func NewMockCreateCommentRepository(ctrl *gomock.Controller) *MockCreateCommentRepository {
+	mock := &MockCreateCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockCreateCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateCommentRepository) EXPECT() *MockCreateCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockCreateCommentRepository) CreateComment(ctx context.Context, comment model.Comment) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, comment)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCreateCommentRepositoryMockRecorder) CreateComment(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockCreateCommentRepository)(nil).CreateComment), ctx, comment)
+}
+
+// MockGetCommentByIDRepository is a mock of GetCommentByIDRepository interface.
+type MockGetCommentByIDRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetCommentByIDRepositoryMockRecorder
+}
+
+// MockGetCommentByIDRepositoryMockRecorder is the mock recorder for MockGetCommentByIDRepository.
+type MockGetCommentByIDRepositoryMockRecorder struct {
+	mock *MockGetCommentByIDRepository
+}
+
+// NewMockGetCommentByIDRepository creates a new mock instance.
+func NewMockGetCommentByIDRepository(ctrl *gomock.Controller) *MockGetCommentByIDRepository {
+	mock := &MockGetCommentByIDRepository{ctrl: ctrl}
+	mock.recorder = &MockGetCommentByIDRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetCommentByIDRepository) EXPECT() *MockGetCommentByIDRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCommentByID mocks base method.
+func (m *MockGetCommentByIDRepository) GetCommentByID(ctx context.Context, id int) (model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentByID", ctx, id)
+	ret0, _ := ret[0].(model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentByID indicates an expected call of GetCommentByID.
+func (mr *MockGetCommentByIDRepositoryMockRecorder) GetCommentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentByID", reflect.TypeOf((*MockGetCommentByIDRepository)(nil).GetCommentByID), ctx, id)
+}
+
+// MockGetThreadsByPostIDRepository is a mock of GetThreadsByPostIDRepository interface.
+type MockGetThreadsByPostIDRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetThreadsByPostIDRepositoryMockRecorder
+}
+
+// MockGetThreadsByPostIDRepositoryMockRecorder is the mock recorder for MockGetThreadsByPostIDRepository.
+type MockGetThreadsByPostIDRepositoryMockRecorder struct {
+	mock *MockGetThreadsByPostIDRepository
+}
+
+// NewMockGetThreadsByPostIDRepository creates a new mock instance.
+func NewMockGetThreadsByPostIDRepository(ctrl *gomock.Controller) *MockGetThreadsByPostIDRepository {
+	mock := &MockGetThreadsByPostIDRepository{ctrl: ctrl}
+	mock.recorder = &MockGetThreadsByPostIDRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetThreadsByPostIDRepository) EXPECT() *MockGetThreadsByPostIDRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetThreadsByPostID mocks base method.
+func (m *MockGetThreadsByPostIDRepository) GetThreadsByPostID(ctx context.Context, postID int, page model.Pagination) ([]model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThreadsByPostID", ctx, postID, page)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThreadsByPostID indicates an expected call of GetThreadsByPostID.
+func (mr *MockGetThreadsByPostIDRepositoryMockRecorder) GetThreadsByPostID(ctx, postID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThreadsByPostID", reflect.TypeOf((*MockGetThreadsByPostIDRepository)(nil).GetThreadsByPostID), ctx, postID, page)
+}
+
+// MockGetPendingCommentsRepository is a mock of GetPendingCommentsRepository interface.
+type MockGetPendingCommentsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPendingCommentsRepositoryMockRecorder
+}
+
+// MockGetPendingCommentsRepositoryMockRecorder is the mock recorder for MockGetPendingCommentsRepository.
+type MockGetPendingCommentsRepositoryMockRecorder struct {
+	mock *MockGetPendingCommentsRepository
+}
+
+// NewMockGetPendingCommentsRepository creates a new mock instance.
+func NewMockGetPendingCommentsRepository(ctrl *gomock.Controller) *MockGetPendingCommentsRepository {
+	mock := &MockGetPendingCommentsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetPendingCommentsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPendingCommentsRepository) EXPECT() *MockGetPendingCommentsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPendingComments mocks base method.
+func (m *MockGetPendingCommentsRepository) GetPendingComments(ctx context.Context, page model.Pagination) ([]model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingComments", ctx, page)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingComments indicates an expected call of GetPendingComments.
+func (mr *MockGetPendingCommentsRepositoryMockRecorder) GetPendingComments(ctx, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingComments", reflect.TypeOf((*MockGetPendingCommentsRepository)(nil).GetPendingComments), ctx, page)
+}
+
+// MockUpdateCommentRepository is a mock of UpdateCommentRepository interface.
+type MockUpdateCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdateCommentRepositoryMockRecorder
+}
+
+// MockUpdateCommentRepositoryMockRecorder is the mock recorder for MockUpdateCommentRepository.
+type MockUpdateCommentRepositoryMockRecorder struct {
+	mock *MockUpdateCommentRepository
+}
+
+// NewMockUpdateCommentRepository creates a new mock instance.
+func NewMockUpdateCommentRepository(ctrl *gomock.Controller) *MockUpdateCommentRepository {
+	mock := &MockUpdateCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockUpdateCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdateCommentRepository) EXPECT() *MockUpdateCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// UpdateCommentContent mocks base method.
+func (m *MockUpdateCommentRepository) UpdateCommentContent(ctx context.Context, id int, content string, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCommentContent", ctx, id, content, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCommentContent indicates an expected call of UpdateCommentContent.
+func (mr *MockUpdateCommentRepositoryMockRecorder) UpdateCommentContent(ctx, id, content, updatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommentContent", reflect.TypeOf((*MockUpdateCommentRepository)(nil).UpdateCommentContent), ctx, id, content, updatedAt)
+}
+
+// MockDeleteCommentRepository is a mock of DeleteCommentRepository interface.
+type MockDeleteCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeleteCommentRepositoryMockRecorder
+}
+
+// MockDeleteCommentRepositoryMockRecorder is the mock recorder for MockDeleteCommentRepository.
+type MockDeleteCommentRepositoryMockRecorder struct {
+	mock *MockDeleteCommentRepository
+}
+
+// NewMockDeleteCommentRepository creates a new mock instance.
+func NewMockDeleteCommentRepository(ctrl *gomock.Controller) *MockDeleteCommentRepository {
+	mock := &MockDeleteCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockDeleteCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeleteCommentRepository) EXPECT() *MockDeleteCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteComment mocks base method.
+func (m *MockDeleteCommentRepository) DeleteComment(ctx context.Context, id int, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, id, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockDeleteCommentRepositoryMockRecorder) DeleteComment(ctx, id, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockDeleteCommentRepository)(nil).DeleteComment), ctx, id, deletedAt)
+}
+
+// MockModerateCommentRepository is a mock of ModerateCommentRepository interface.
+type MockModerateCommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerateCommentRepositoryMockRecorder
+}
+
+// MockModerateCommentRepositoryMockRecorder is the mock recorder for MockModerateCommentRepository.
+type MockModerateCommentRepositoryMockRecorder struct {
+	mock *MockModerateCommentRepository
+}
+
+// NewMockModerateCommentRepository creates a new mock instance.
+func NewMockModerateCommentRepository(ctrl *gomock.Controller) *MockModerateCommentRepository {
+	mock := &MockModerateCommentRepository{ctrl: ctrl}
+	mock.recorder = &MockModerateCommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerateCommentRepository) EXPECT() *MockModerateCommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ModerateComment mocks base method.
+func (m *MockModerateCommentRepository) ModerateComment(ctx context.Context, id int, status string, moderatorID int, moderatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateComment", ctx, id, status, moderatorID, moderatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateComment indicates an expected call of ModerateComment.
+func (mr *MockModerateCommentRepositoryMockRecorder) ModerateComment(ctx, id, status, moderatorID, moderatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateComment", reflect.TypeOf((*MockModerateCommentRepository)(nil).ModerateComment), ctx, id, status, moderatorID, moderatedAt)
+}
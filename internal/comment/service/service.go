@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/comment/model"
+	"github.com/ananaslegend/news-crud/internal/comment/repository"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type CreateCommentRepository interface {
+	CreateComment(ctx context.Context, comment model.Comment) (int, error)
+}
+
+type GetCommentByIDRepository interface {
+	GetCommentByID(ctx context.Context, id int) (model.Comment, error)
+}
+
+type GetThreadsByPostIDRepository interface {
+	GetThreadsByPostID(ctx context.Context, postID int, page model.Pagination) ([]model.Comment, error)
+}
+
+type GetPendingCommentsRepository interface {
+	GetPendingComments(ctx context.Context, page model.Pagination) ([]model.Comment, error)
+}
+
+type UpdateCommentRepository interface {
+	UpdateCommentContent(ctx context.Context, id int, content string, updatedAt time.Time) error
+}
+
+type DeleteCommentRepository interface {
+	DeleteComment(ctx context.Context, id int, deletedAt time.Time) error
+}
+
+type ModerateCommentRepository interface {
+	ModerateComment(ctx context.Context, id int, status string, moderatorID int, moderatedAt time.Time) error
+}
+
+type CommentService struct {
+	logger *slog.Logger
+
+	createCommentRepository   CreateCommentRepository
+	commentByIDRepository     GetCommentByIDRepository
+	threadsByPostIDRepository GetThreadsByPostIDRepository
+	pendingCommentsRepository GetPendingCommentsRepository
+	updateCommentRepository   UpdateCommentRepository
+	deleteCommentRepository   DeleteCommentRepository
+	moderateCommentRepository ModerateCommentRepository
+}
+
+func NewCommentService(
+	logger *slog.Logger,
+	createCommentRepository CreateCommentRepository,
+	commentByIDRepository GetCommentByIDRepository,
+	threadsByPostIDRepository GetThreadsByPostIDRepository,
+	pendingCommentsRepository GetPendingCommentsRepository,
+	updateCommentRepository UpdateCommentRepository,
+	deleteCommentRepository DeleteCommentRepository,
+	moderateCommentRepository ModerateCommentRepository,
+) *CommentService {
+	return &CommentService{
+		logger:                    logger,
+		createCommentRepository:   createCommentRepository,
+		commentByIDRepository:     commentByIDRepository,
+		threadsByPostIDRepository: threadsByPostIDRepository,
+		pendingCommentsRepository: pendingCommentsRepository,
+		updateCommentRepository:   updateCommentRepository,
+		deleteCommentRepository:   deleteCommentRepository,
+		moderateCommentRepository: moderateCommentRepository,
+	}
+}
+
+// CreateComment adds a comment to the post, or a reply when parentID is set.
+// Only approved comments can be replied to.
+func (cs CommentService) CreateComment(ctx context.Context, userID, postID int, parentID *int, content string) (int, error) {
+	const op = "news-crud.internal.comment.create.service.CreateComment"
+	logger := cs.logger.With(slog.String("op", op))
+
+	comment := model.NewComment(postID, userID, content)
+
+	if parentID != nil {
+		parent, err := cs.commentByID(ctx, *parentID)
+		if err != nil {
+			return 0, err
+		}
+
+		if parent.Status != model.StatusApproved || parent.Deleted {
+			return 0, ErrNoCommentWasFound
+		}
+
+		if parent.PostID != postID {
+			return 0, ErrParentOnOtherPost
+		}
+
+		comment.ReplyTo(parent)
+	}
+
+	commentID, err := cs.createCommentRepository.CreateComment(ctx, comment)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return 0, ErrNoPostWasFound
+		}
+
+		logger.Error("cant create comment", logs.Err(err))
+		return 0, ErrCantCreateComment
+	}
+
+	return commentID, nil
+}
+
+// GetCommentsByPostID returns a page of approved comment threads with nested replies.
+func (cs CommentService) GetCommentsByPostID(ctx context.Context, postID int, page model.Pagination) (model.Page, error) {
+	const op = "news-crud.internal.comment.get_by_post_id.service.GetCommentsByPostID"
+
+	comments, err := cs.threadsByPostIDRepository.GetThreadsByPostID(ctx, postID, page)
+	if err != nil {
+		return model.Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	roots := BuildThreads(comments)
+
+	result := model.Page{Comments: roots}
+	if len(roots) == page.Limit {
+		result.NextCursor = roots[len(roots)-1].ID
+	}
+
+	return result, nil
+}
+
+func (cs CommentService) GetModerationQueue(ctx context.Context, page model.Pagination) (model.Page, error) {
+	const op = "news-crud.internal.comment.get_pending.service.GetModerationQueue"
+
+	comments, err := cs.pendingCommentsRepository.GetPendingComments(ctx, page)
+	if err != nil {
+		return model.Page{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := model.Page{Comments: make([]*model.Comment, len(comments))}
+	for i := range comments {
+		result.Comments[i] = &comments[i]
+	}
+
+	if len(comments) == page.Limit {
+		result.NextCursor = comments[len(comments)-1].ID
+	}
+
+	return result, nil
+}
+
+// UpdateComment lets the author edit the comment, the edit is moderated again.
+func (cs CommentService) UpdateComment(ctx context.Context, userID, commentID int, content string) error {
+	const op = "news-crud.internal.comment.update.service.UpdateComment"
+
+	if err := cs.checkOwner(ctx, userID, commentID); err != nil {
+		return err
+	}
+
+	if err := cs.updateCommentRepository.UpdateCommentContent(ctx, commentID, content, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNoCommentWasFound) {
+			return ErrNoCommentWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (cs CommentService) DeleteComment(ctx context.Context, userID, commentID int) error {
+	const op = "news-crud.internal.comment.delete.service.DeleteComment"
+
+	if err := cs.checkOwner(ctx, userID, commentID); err != nil {
+		return err
+	}
+
+	if err := cs.deleteCommentRepository.DeleteComment(ctx, commentID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNoCommentWasFound) {
+			return ErrNoCommentWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (cs CommentService) ApproveComment(ctx context.Context, moderatorID, commentID int) error {
+	return cs.moderate(ctx, moderatorID, commentID, model.StatusApproved)
+}
+
+func (cs CommentService) RejectComment(ctx context.Context, moderatorID, commentID int) error {
+	return cs.moderate(ctx, moderatorID, commentID, model.StatusRejected)
+}
+
+func (cs CommentService) moderate(ctx context.Context, moderatorID, commentID int, status string) error {
+	const op = "news-crud.internal.comment.moderate.service.moderate"
+
+	err := cs.moderateCommentRepository.ModerateComment(ctx, commentID, status, moderatorID, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCommentWasFound) {
+			return ErrNoCommentWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (cs CommentService) checkOwner(ctx context.Context, userID, commentID int) error {
+	comment, err := cs.commentByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.Deleted {
+		return ErrCommentIsDeleted
+	}
+
+	if comment.AuthorID != userID {
+		return ErrUserHasNoPermission
+	}
+
+	return nil
+}
+
+func (cs CommentService) commentByID(ctx context.Context, commentID int) (model.Comment, error) {
+	const op = "news-crud.internal.comment.get_by_id.service.commentByID"
+
+	comment, err := cs.commentByIDRepository.GetCommentByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCommentWasFound) {
+			return model.Comment{}, ErrNoCommentWasFound
+		}
+
+		return model.Comment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comment, nil
+}
+
+// BuildThreads nests comments ordered by ID under their parents. Replies whose
+// parent is missing, e.g. not approved, are left out together with their subtree.
+func BuildThreads(comments []model.Comment) []*model.Comment {
+	byID := make(map[int]*model.Comment, len(comments))
+	roots := make([]*model.Comment, 0)
+
+	for i := range comments {
+		comment := &comments[i]
+
+		if comment.ParentID == nil {
+			byID[comment.ID] = comment
+			roots = append(roots, comment)
+			continue
+		}
+
+		// parents always have lower IDs, so they have been seen already
+		parent, ok := byID[*comment.ParentID]
+		if !ok {
+			continue
+		}
+
+		byID[comment.ID] = comment
+		parent.Replies = append(parent.Replies, comment)
+	}
+
+	return roots
+}
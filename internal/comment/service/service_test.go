@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/comment/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/comment/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func ptr(v int) *int {
+	return &v
+}
+
+func TestBuildThreads(t *testing.T) {
+	comments := []model.Comment{
+		{ID: 1},
+		{ID: 2},
+		{ID: 3, ParentID: ptr(1), RootID: ptr(1)},
+		{ID: 5, ParentID: ptr(3), RootID: ptr(1)},
+		// reply to a comment which is not approved
+		{ID: 6, ParentID: ptr(4), RootID: ptr(1)},
+		{ID: 7, ParentID: ptr(2), RootID: ptr(2)},
+	}
+
+	roots := BuildThreads(comments)
+
+	require.Len(t, roots, 2)
+	require.Equal(t, 1, roots[0].ID)
+	require.Len(t, roots[0].Replies, 1)
+	require.Equal(t, 3, roots[0].Replies[0].ID)
+	require.Len(t, roots[0].Replies[0].Replies, 1)
+	require.Equal(t, 5, roots[0].Replies[0].Replies[0].ID)
+	require.Len(t, roots[1].Replies, 1)
+	require.Equal(t, 7, roots[1].Replies[0].ID)
+}
+
+func TestCommentService_CreateComment_Reply(t *testing.T) {
+	type mockBehavior func(byID *mock_service.MockGetCommentByIDRepository, create *mock_service.MockCreateCommentRepository)
+
+	tests := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name: "Reply joins the thread of the parent",
+			mockBehavior: func(byID *mock_service.MockGetCommentByIDRepository, create *mock_service.MockCreateCommentRepository) {
+				byID.EXPECT().GetCommentByID(gomock.Any(), 3).
+					Return(model.Comment{ID: 3, PostID: 1, RootID: ptr(1), Status: model.StatusApproved}, nil)
+				create.EXPECT().CreateComment(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, c model.Comment) (int, error) {
+						require.Equal(t, 3, *c.ParentID)
+						require.Equal(t, 1, *c.RootID)
+						require.Equal(t, model.StatusPending, c.Status)
+						return 10, nil
+					})
+			},
+		},
+		{
+			name: "Can not reply to a pending comment",
+			mockBehavior: func(byID *mock_service.MockGetCommentByIDRepository, create *mock_service.MockCreateCommentRepository) {
+				byID.EXPECT().GetCommentByID(gomock.Any(), 3).
+					Return(model.Comment{ID: 3, PostID: 1, Status: model.StatusPending}, nil)
+			},
+			wantErr: ErrNoCommentWasFound,
+		},
+		{
+			name: "Can not reply to a comment on another post",
+			mockBehavior: func(byID *mock_service.MockGetCommentByIDRepository, create *mock_service.MockCreateCommentRepository) {
+				byID.EXPECT().GetCommentByID(gomock.Any(), 3).
+					Return(model.Comment{ID: 3, PostID: 2, Status: model.StatusApproved}, nil)
+			},
+			wantErr: ErrParentOnOtherPost,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			byID := mock_service.NewMockGetCommentByIDRepository(c)
+			create := mock_service.NewMockCreateCommentRepository(c)
+			testCase.mockBehavior(byID, create)
+
+			s := NewCommentService(slogdiscard.NewDiscardLogger(), create, byID, nil, nil, nil, nil, nil)
+
+			_, err := s.CreateComment(context.Background(), 1, 1, ptr(3), "reply")
+			require.ErrorIs(t, err, testCase.wantErr)
+		})
+	}
+}
+
+func TestCommentService_UpdateComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment model.Comment
+		wantErr error
+	}{
+		{name: "Owner can edit comment", comment: model.Comment{ID: 1, AuthorID: 1}},
+		{name: "Others can not edit comment", comment: model.Comment{ID: 1, AuthorID: 2}, wantErr: ErrUserHasNoPermission},
+		{name: "Deleted comment can not be edited", comment: model.Comment{ID: 1, AuthorID: 1, Deleted: true}, wantErr: ErrCommentIsDeleted},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			byID := mock_service.NewMockGetCommentByIDRepository(c)
+			byID.EXPECT().GetCommentByID(gomock.Any(), 1).Return(testCase.comment, nil)
+
+			update := mock_service.NewMockUpdateCommentRepository(c)
+			if testCase.wantErr == nil {
+				update.EXPECT().UpdateCommentContent(gomock.Any(), 1, "edited", gomock.Any()).Return(nil)
+			}
+
+			s := NewCommentService(slogdiscard.NewDiscardLogger(), nil, byID, nil, nil, update, nil, nil)
+
+			err := s.UpdateComment(context.Background(), 1, 1, "edited")
+			require.ErrorIs(t, err, testCase.wantErr)
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"github.com/ananaslegend/news-crud/pkg/ratelimit"
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
+	"time"
 )
 
 type Env string
@@ -16,8 +18,136 @@ const (
 type AppConfig struct {
 	Env      Env    `env:"ENV" envDefault:"local"`
 	HttpPort string `env:"HTTP_PORT" envDefault:"8080"`
+	GrpcPort string `env:"GRPC_PORT" envDefault:":9090"`
 	DBConn   string `env:"DB_CONN" envDefault:""`
 	Secret   string `env:"SECRET"`
+
+	// MigrateOnStart applies the pending migrations before serving, replicas
+	// take turns through a database lock.
+	MigrateOnStart bool `env:"MIGRATE_ON_START" envDefault:"true"`
+
+	// PublicURL is where clients reach the API, used for links in feeds.
+	PublicURL string `env:"PUBLIC_URL" envDefault:"http://localhost:8080"`
+
+	AccessTokenTTL time.Duration `env:"ACCESS_TOKEN_TTL" envDefault:"1h"`
+
+	// ShutdownTimeout bounds how long the servers wait for requests and
+	// streams in flight on SIGINT or SIGTERM.
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+
+	// TrustForwardedFor takes the client IP from X-Forwarded-For, enable it behind a proxy only.
+	TrustForwardedFor bool `env:"TRUST_FORWARDED_FOR" envDefault:"false"`
+
+	OIDC OIDCConfig `envPrefix:"OIDC_"`
+
+	RateLimit RateLimitConfig `envPrefix:"RATE_LIMIT_"`
+
+	// Reactions readers may leave on posts in addition to "like".
+	Reactions []string `env:"REACTIONS" envDefault:"❤️,😂,😮,😢,😡"`
+
+	Views ViewsConfig `envPrefix:"VIEWS_"`
+
+	Feed FeedConfig `envPrefix:"FEED_"`
+
+	Media MediaConfig `envPrefix:"MEDIA_"`
+
+	GraphQL GraphQLConfig `envPrefix:"GRAPHQL_"`
+
+	Import ImportConfig `envPrefix:"IMPORT_"`
+
+	// NewsLanguage is the ISO 639 language of the posts, announced in the news sitemap.
+	NewsLanguage string `env:"NEWS_LANGUAGE" envDefault:"en"`
+}
+
+// FeedConfig describes the RSS and Atom feeds. Clients may ask for up to
+// MaxItemLimit items with the limit query parameter.
+type FeedConfig struct {
+	Title        string `env:"TITLE" envDefault:"News"`
+	Description  string `env:"DESCRIPTION" envDefault:"Latest news"`
+	ItemLimit    int    `env:"ITEM_LIMIT" envDefault:"20"`
+	MaxItemLimit int    `env:"MAX_ITEM_LIMIT" envDefault:"100"`
+}
+
+// OIDCConfig configures staff login through an external identity provider.
+// The login is disabled while Issuer is empty.
+type OIDCConfig struct {
+	Issuer       string            `env:"ISSUER"`
+	ClientID     string            `env:"CLIENT_ID"`
+	ClientSecret string            `env:"CLIENT_SECRET"`
+	RedirectURL  string            `env:"REDIRECT_URL"`
+	GroupsClaim  string            `env:"GROUPS_CLAIM" envDefault:"groups"`
+	GroupRoles   map[string]string `env:"GROUP_ROLES"`
+}
+
+// RateLimitConfig holds the per route limits, each as "<count>/<period>".
+// Buckets are kept in memory unless RedisAddr is set.
+type RateLimitConfig struct {
+	RedisAddr string `env:"REDIS_ADDR"`
+
+	PostsRead  RateLimitRule `envPrefix:"POSTS_READ_"`
+	PostsWrite RateLimitRule `envPrefix:"POSTS_WRITE_"`
+}
+
+// RateLimitRule limits a route per api key, per authenticated user and per
+// client IP for anonymous requests. An empty limit disables it.
+type RateLimitRule struct {
+	APIKey ratelimit.Limit `env:"API_KEY" envDefault:"600/1m"`
+	User   ratelimit.Limit `env:"USER" envDefault:"120/1m"`
+	IP     ratelimit.Limit `env:"IP" envDefault:"60/1m"`
+}
+
+// MediaConfig limits uploads and picks where media is stored, "local" keeps it
+// in LocalDir and "s3" in a bucket of an S3-compatible object store.
+type MediaConfig struct {
+	MaxSize      int64    `env:"MAX_SIZE" envDefault:"10485760"`
+	AllowedTypes []string `env:"ALLOWED_TYPES" envDefault:"image/jpeg,image/png,image/gif,image/webp"`
+
+	Storage  string `env:"STORAGE" envDefault:"local"`
+	LocalDir string `env:"LOCAL_DIR" envDefault:"./data/media"`
+
+	S3Endpoint  string `env:"S3_ENDPOINT"`
+	S3Region    string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket    string `env:"S3_BUCKET"`
+	S3AccessKey string `env:"S3_ACCESS_KEY"`
+	S3SecretKey string `env:"S3_SECRET_KEY"`
+
+	Images ImagesConfig `envPrefix:"IMAGES_"`
+}
+
+// ImagesConfig sets the renditions generated for uploaded images, as
+// "name:WIDTHxHEIGHT[:crop]", and the formats each of them is encoded in.
+// WebP renditions are lossless, the quality of 1 to 100 sets JPEG only.
+type ImagesConfig struct {
+	Renditions   []string      `env:"RENDITIONS" envDefault:"thumb:320x320:crop,small:640x0,medium:1280x0,large:1920x0"`
+	Formats      []string      `env:"FORMATS" envDefault:"webp,jpeg"`
+	JPEGQuality  int           `env:"JPEG_QUALITY" envDefault:"82"`
+	MaxPixels    int           `env:"MAX_PIXELS" envDefault:"50000000"`
+	Workers      int           `env:"WORKERS" envDefault:"2"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"30s"`
+	Lease        time.Duration `env:"LEASE" envDefault:"5m"`
+	MaxAttempts  int           `env:"MAX_ATTEMPTS" envDefault:"3"`
+}
+
+// ViewsConfig tunes the in-memory buffer views are collected in before they
+// are written in batches.
+type ViewsConfig struct {
+	BufferSize    int           `env:"BUFFER_SIZE" envDefault:"10000"`
+	BatchSize     int           `env:"BATCH_SIZE" envDefault:"500"`
+	FlushInterval time.Duration `env:"FLUSH_INTERVAL" envDefault:"10s"`
+	DedupWindow   time.Duration `env:"DEDUP_WINDOW" envDefault:"30m"`
+}
+
+// GraphQLConfig bounds the depth and the complexity of GraphQL queries.
+type GraphQLConfig struct {
+	MaxDepth      int `env:"MAX_DEPTH" envDefault:"8"`
+	MaxComplexity int `env:"MAX_COMPLEXITY" envDefault:"2000"`
+}
+
+// ImportConfig limits the exports posted to the import endpoint, posts are
+// stored BatchSize at a time.
+type ImportConfig struct {
+	MaxSize   int64 `env:"MAX_SIZE" envDefault:"1073741824"`
+	BatchSize int   `env:"BATCH_SIZE" envDefault:"100"`
 }
 
 func NewConfig() (*AppConfig, error) {
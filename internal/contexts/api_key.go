@@ -0,0 +1,34 @@
+package contexts
+
+import (
+	"context"
+	"slices"
+)
+
+type apiKeyKey struct{}
+
+type apiKey struct {
+	id     int
+	scopes []string
+}
+
+// SetAPIKey marks the request as authenticated by an api key limited to scopes.
+func SetAPIKey(ctx context.Context, keyID int, scopes []string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, apiKey{id: keyID, scopes: scopes})
+}
+
+func GetAPIKeyID(ctx context.Context) (int, bool) {
+	key, ok := ctx.Value(apiKeyKey{}).(apiKey)
+	return key.id, ok
+}
+
+// HasScope reports whether the request may act within scope. Requests
+// authenticated by a user token are not limited by scopes.
+func HasScope(ctx context.Context, scope string) bool {
+	key, ok := ctx.Value(apiKeyKey{}).(apiKey)
+	if !ok {
+		return true
+	}
+
+	return slices.Contains(key.scopes, scope)
+}
@@ -0,0 +1,21 @@
+package contexts
+
+import (
+	"context"
+	"slices"
+)
+
+type rolesKey struct{}
+
+func SetRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, rolesKey{}, roles)
+}
+
+func GetRoles(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesKey{}).([]string)
+	return roles
+}
+
+func HasRole(ctx context.Context, role string) bool {
+	return slices.Contains(GetRoles(ctx), role)
+}
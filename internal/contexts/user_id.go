@@ -13,3 +13,8 @@ func SetUserID(ctx context.Context, userID int) context.Context {
 func MustGetUserID(ctx context.Context) int {
 	return ctx.Value(userIDKey{}).(int)
 }
+
+func GetUserID(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int)
+	return userID, ok
+}
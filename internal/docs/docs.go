@@ -0,0 +1,10 @@
+// Package docs holds the OpenAPI document of the HTTP API. It describes every
+// route registered in cmd/app and is kept in sync by hand.
+package docs
+
+import (
+	_ "embed"
+)
+
+//go:embed openapi.json
+var OpenAPI []byte
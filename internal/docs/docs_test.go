@@ -0,0 +1,189 @@
+package docs
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	docsHandler "github.com/ananaslegend/news-crud/internal/docs/handler"
+	"github.com/ananaslegend/news-crud/internal/middleware"
+	postHandler "github.com/ananaslegend/news-crud/internal/post/handler"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	postService "github.com/ananaslegend/news-crud/internal/post/service"
+	reactionHandler "github.com/ananaslegend/news-crud/internal/reaction/handler"
+	reactionModel "github.com/ananaslegend/news-crud/internal/reaction/model"
+	reactionService "github.com/ananaslegend/news-crud/internal/reaction/service"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/openapi"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadOpenAPI(t *testing.T) *openapi.Document {
+	doc, err := openapi.Load(OpenAPI)
+	require.NoError(t, err)
+	return doc
+}
+
+var routePattern = regexp.MustCompile(`mux\.HandleFunc\("([A-Z]+) ([^"]+)"`)
+
+func TestOpenAPI_DescribesEveryRoute(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	main, err := os.ReadFile("../../cmd/app/serve.go")
+	require.NoError(t, err)
+
+	routes := routePattern.FindAllStringSubmatch(string(main), -1)
+	require.NotEmpty(t, routes)
+
+	for _, route := range routes {
+		method, pattern := route[1], route[2]
+
+		template := strings.ReplaceAll(pattern, "...}", "}")
+		path := regexp.MustCompile(`\{[^}]+\}`).ReplaceAllString(template, "1")
+
+		m, ok := doc.Find(method, path)
+		if !ok {
+			t.Errorf("%s %s is not described", method, pattern)
+			continue
+		}
+		require.Equal(t, template, m.Template, pattern)
+	}
+}
+
+// posts serves a fixed set of posts to the handlers.
+type posts []model.Post
+
+func (p posts) CreatePost(context.Context, string, string, string, string, []string, int) (int, error) {
+	return len(p) + 1, nil
+}
+
+func (p posts) GetPostByFilter(context.Context, model.Filter) ([]model.Post, error) {
+	return p, nil
+}
+
+func (p posts) GetPostByID(_ context.Context, id int, _ []string) (model.Post, error) {
+	for _, post := range p {
+		if post.ID == id {
+			return post, nil
+		}
+	}
+	return model.Post{}, postService.ErrNoPostWasFound
+}
+
+func (p posts) UpdatePost(context.Context, int, model.Post) error {
+	return nil
+}
+
+func (p posts) DeletePost(context.Context, int, int) error {
+	return nil
+}
+
+func (p posts) UpdatePostAuthors(context.Context, int, int, []model.Author) error {
+	return nil
+}
+
+// ApplyBatch fails the operations on posts that do not exist.
+func (p posts) ApplyBatch(ctx context.Context, _ int, ops []model.BatchOperation, _ bool) ([]model.BatchResult, error) {
+	results := make([]model.BatchResult, len(ops))
+	for i, op := range ops {
+		results[i].PostID = op.Post.ID
+		if op.Op == model.BatchCreate {
+			results[i].PostID = len(p) + 1 + i
+		} else if _, err := p.GetPostByID(ctx, op.Post.ID, nil); err != nil {
+			results[i].Err = err
+		}
+	}
+	return results, nil
+}
+
+func TestOpenAPI_Responses(t *testing.T) {
+	doc := loadOpenAPI(t)
+	logger := slogdiscard.NewDiscardLogger()
+
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := posts{
+		{
+			ID: 1, Title: "First", Content: "# hi", ContentFormat: "markdown", ContentHTML: "<h1>hi</h1>",
+			Excerpt: "hi", WordCount: 1, ReadingTime: 1, AuthorID: 7,
+			Authors:   []model.Author{{UserID: 7, Role: model.AuthorRoleLead}},
+			Tags:      []string{"go"},
+			Media:     []model.Media{{ID: 3, URL: "/media/a.png", Variants: []model.MediaVariant{{Name: "thumb"}}}},
+			Reactions: map[string]int{"like": 2},
+			CreatedAt: created, UpdatedAt: created,
+		},
+		{ID: 2, Title: "Second", AuthorID: 7, CreatedAt: created, UpdatedAt: created},
+	}
+
+	postHdl := postHandler.NewPostHandler(logger, store, store, store, store, store, store, store)
+	reactions := reactionService.NewReactionService(reactionModel.NewSet([]string{"wow"}), nil, nil)
+	reactionHdl := reactionHandler.NewReactionHandler(logger, reactions, reactions, reactions)
+	docsHdl := docsHandler.NewDocsHandler(logger, OpenAPI)
+
+	asUser := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			next(w, r.WithContext(contexts.SetUserID(r.Context(), 7)))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /posts", asUser(postHdl.CreatePost))
+	mux.HandleFunc("POST /posts:batch", asUser(postHdl.ApplyBatch))
+	mux.HandleFunc("GET /posts", postHdl.GetPostByFilter)
+	mux.HandleFunc("GET /posts/{id}", postHdl.GetPostByID)
+	mux.HandleFunc("PUT /posts/{id}", asUser(postHdl.UpdatePostByID))
+	mux.HandleFunc("PUT /posts/{id}/authors", asUser(postHdl.UpdatePostAuthors))
+	mux.HandleFunc("GET /reactions", reactionHdl.GetReactions)
+	mux.HandleFunc("GET /openapi.json", docsHdl.GetSpec)
+	mux.HandleFunc("GET /docs", docsHdl.GetUI)
+	mux.HandleFunc("GET /docs/{file}", docsHdl.GetAsset)
+
+	handler := middleware.ValidateResponses(doc, func(r *http.Request, err error) {
+		t.Errorf("%s %s: %v", r.Method, r.URL, err)
+	}, middleware.ValidateRequests(doc, mux))
+
+	tests := []struct {
+		method, target, body string
+		wantStatus           int
+		wantBody             string
+	}{
+		{method: http.MethodGet, target: "/posts/1", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/posts/1?fields=id,title&content=html", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/posts/9", wantStatus: http.StatusNotFound},
+		{method: http.MethodGet, target: "/posts?dateFrom=2024-01-01T00:00:00Z&dateTo=2024-12-01T00:00:00Z", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/posts?view=compact&limit=1&offset=1", wantStatus: http.StatusOK},
+		{method: http.MethodPost, target: "/posts", body: `{"title": "t", "content": "c", "tags": ["go"]}`, wantStatus: http.StatusCreated},
+		{method: http.MethodPut, target: "/posts/1", body: `{"ID": 1, "Title": "t", "Content": "c"}`, wantStatus: http.StatusOK},
+		{method: http.MethodPut, target: "/posts/1/authors", body: `{"authors": [{"user_id": 7, "role": "lead"}]}`, wantStatus: http.StatusOK},
+		{method: http.MethodPost, target: "/posts:batch", body: `{"operations": [{"op": "create", "post": {"title": "t", "content": "c"}}, {"op": "update", "id": 1, "post": {"title": "t", "content": "c"}}, {"op": "delete", "id": 9}]}`, wantStatus: http.StatusOK, wantBody: `{"status":404,"post_id":9,"error":"post not found"}`},
+		{method: http.MethodGet, target: "/reactions", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/openapi.json", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/docs", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/docs/swagger-ui-bundle.js", wantStatus: http.StatusOK, wantBody: "SwaggerUIBundle"},
+
+		{method: http.MethodGet, target: "/posts/abc", wantStatus: http.StatusBadRequest, wantBody: `path parameter "id"`},
+		{method: http.MethodGet, target: "/posts?sort=title", wantStatus: http.StatusBadRequest, wantBody: `query parameter "sort"`},
+		{method: http.MethodPost, target: "/posts", body: `{"title": "t"}`, wantStatus: http.StatusBadRequest, wantBody: "content is required"},
+		{method: http.MethodPost, target: "/posts:batch", body: `{"operations": [{"op": "update", "post": {"title": "t", "content": "c"}}]}`, wantStatus: http.StatusBadRequest, wantBody: "ID"},
+		{method: http.MethodPut, target: "/posts/1/authors", body: `{"authors": [{"user_id": 7, "role": "editor"}]}`, wantStatus: http.StatusBadRequest, wantBody: "authors[0].role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				r.Header.Set("Content-Type", "application/json")
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			require.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"io/fs"
+	"log/slog"
+	"net/http"
+)
+
+// swaggerUI loads the Swagger UI assets served by GetAsset and points it at
+// the served document.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>news-crud API</title>
+	<link rel="stylesheet" href="/docs/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="/docs/swagger-ui-bundle.js"></script>
+	<script>
+		window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+	</script>
+</body>
+</html>
+`
+
+// assets are the Swagger UI files the page loads and their content types.
+// They are embedded in the binary, the version is pinned in go.mod.
+var assets = map[string]string{
+	"swagger-ui.css":       "text/css; charset=utf-8",
+	"swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+}
+
+type DocsHandler struct {
+	logger *slog.Logger
+
+	spec []byte
+}
+
+func NewDocsHandler(logger *slog.Logger, spec []byte) *DocsHandler {
+	return &DocsHandler{
+		logger: logger,
+		spec:   spec,
+	}
+}
+
+// GetSpec serves the OpenAPI document.
+func (h DocsHandler) GetSpec(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.docs.get_spec.handler.GetSpec"
+	logger := h.logger.With(slog.String("op", op))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(h.spec); err != nil {
+		logger.Error("cant write spec", logs.Err(err))
+	}
+}
+
+// GetUI serves a Swagger UI page for the document.
+func (h DocsHandler) GetUI(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.docs.get_ui.handler.GetUI"
+	logger := h.logger.With(slog.String("op", op))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(swaggerUI)); err != nil {
+		logger.Error("cant write page", logs.Err(err))
+	}
+}
+
+// GetAsset serves a Swagger UI file of the page.
+func (h DocsHandler) GetAsset(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.docs.get_asset.handler.GetAsset"
+	logger := h.logger.With(slog.String("op", op))
+
+	name := r.PathValue("file")
+	contentType, ok := assets[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	asset, err := fs.ReadFile(swaggerFiles.FS, name)
+	if err != nil {
+		logger.Error("cant read asset", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(asset); err != nil {
+		logger.Error("cant write asset", logs.Err(err))
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/export/model"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ExportService interface {
+	Export(ctx context.Context, w io.Writer, req model.Request) (int, error)
+}
+
+type ExportHandler struct {
+	logger *slog.Logger
+
+	exportService ExportService
+}
+
+func NewExportHandler(logger *slog.Logger, exportService ExportService) *ExportHandler {
+	return &ExportHandler{
+		logger:        logger,
+		exportService: exportService,
+	}
+}
+
+// Export streams the posts of the filters of GET /posts as a file. Every post
+// comes with its cursor, an export that broke off is resumed by passing the
+// last one it got as the cursor parameter.
+func (h ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.export.export.handler.Export"
+	logger := h.logger.With(slog.String("op", op))
+
+	req, err := parseRequest(r)
+	if err == nil {
+		err = req.Validation()
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", model.ContentType(req.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="posts.%s"`, req.Format))
+	w.WriteHeader(http.StatusOK)
+
+	n, err := h.exportService.Export(r.Context(), w, req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		// the status is sent already, breaking the connection off tells the
+		// client the file is not complete
+		logger.Error("cant export posts", logs.Err(err), slog.Int("posts", n))
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func parseRequest(r *http.Request) (model.Request, error) {
+	q := r.URL.Query()
+
+	req := model.Request{
+		Format:         q.Get("format"),
+		Representation: q.Get("content"),
+	}
+	if req.Format == "" {
+		req.Format = model.FormatNDJSON
+	}
+
+	switch req.Representation {
+	case "", postModel.RepresentationSource, postModel.RepresentationHTML:
+	default:
+		return model.Request{}, fmt.Errorf("content should be one of source, html, got %q", req.Representation)
+	}
+
+	var err error
+	if v := q.Get("dateFrom"); v != "" {
+		if req.Filter.DateFrom, err = time.Parse(time.RFC3339, v); err != nil {
+			return model.Request{}, err
+		}
+	}
+	if v := q.Get("dateTo"); v != "" {
+		if req.Filter.DateTo, err = time.Parse(time.RFC3339, v); err != nil {
+			return model.Request{}, err
+		}
+	}
+	req.Filter.SortBy = q.Get("sort")
+
+	if v := q.Get("limit"); v != "" {
+		if req.Filter.Limit, err = strconv.Atoi(v); err != nil {
+			return model.Request{}, err
+		}
+	}
+
+	if req.Filter.Fields, err = postModel.ParseListFields(q.Get("view"), q.Get("fields")); err != nil {
+		return model.Request{}, err
+	}
+
+	if token := q.Get("cursor"); token != "" {
+		after, err := postModel.ParseCursor(token, req.Filter.SortBy)
+		if err != nil {
+			return model.Request{}, err
+		}
+		req.After = &after
+	}
+
+	return req, nil
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+var ErrUnknownFormat = errors.New("format should be one of ndjson, csv, parquet")
+
+// ContentType is the media type of an export in the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return ""
+	}
+}
+
+// Request is an export of the posts of Filter, after the cursor when it is
+// set. Representation is the form of the content as for lists of posts.
+// Every post comes with the cursor to resume the export after it.
+type Request struct {
+	Format         string
+	Filter         postModel.Filter
+	After          *postModel.Cursor
+	Representation string
+}
+
+func (r Request) Validation() error {
+	if ContentType(r.Format) == "" {
+		return ErrUnknownFormat
+	}
+
+	// a zero DateTo means no end, not one before DateFrom
+	filter := r.Filter
+	if filter.DateTo.IsZero() {
+		filter.DateTo = filter.DateFrom
+	}
+	return filter.Validation()
+}
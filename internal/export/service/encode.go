@@ -0,0 +1,296 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/ananaslegend/news-crud/internal/export/model"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/parquet-go/parquet-go"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parquetRowGroupSize bounds the rows a parquet export keeps in memory, a row
+// group is written out once it has that many.
+const parquetRowGroupSize = 10000
+
+// columns are the fields of the flat formats in column order. Authors and
+// media are written as JSON, tags separated by commas as the importer reads
+// them. Reactions are not loaded for lists.
+var columns = []string{
+	"id", "title", "content", "content_format", "content_html", "excerpt", "word_count", "reading_time",
+	"author_id", "authors", "tags", "media", "created_at", "updated_at",
+}
+
+// encoder writes the posts of an export, close writes what is left.
+type encoder interface {
+	encode(post postModel.Post, cursor postModel.Cursor) error
+	close() error
+}
+
+// newEncoder writes the fields of the posts, nil means all fields. Every post
+// is written with the cursor after it.
+func newEncoder(format string, w io.Writer, fields []string) (encoder, error) {
+	selected := selectColumns(fields)
+
+	switch format {
+	case model.FormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w), fields: fields}, nil
+	case model.FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w), columns: selected}, nil
+	case model.FormatParquet:
+		return &parquetEncoder{
+			w: parquet.NewGenericWriter[parquetPost](w,
+				parquet.Compression(&parquet.Snappy),
+				parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+			),
+			columns: selected,
+			rows:    make([]parquetPost, 1),
+		}, nil
+	default:
+		return nil, model.ErrUnknownFormat
+	}
+}
+
+func selectColumns(fields []string) map[string]bool {
+	selected := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		selected[c] = fields == nil
+	}
+	for _, f := range fields {
+		selected[f] = true
+	}
+	selected["id"] = true
+	return selected
+}
+
+// ndjsonEncoder writes a post per line as the posts API shows it, with a
+// cursor key.
+type ndjsonEncoder struct {
+	enc    *json.Encoder
+	fields []string
+}
+
+func (e *ndjsonEncoder) encode(post postModel.Post, cursor postModel.Cursor) error {
+	if e.fields == nil {
+		return e.enc.Encode(struct {
+			Cursor string `json:"cursor"`
+			postModel.Post
+		}{cursor.String(), post})
+	}
+
+	data, err := json.Marshal(post)
+	if err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err = json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	projected := make(map[string]any, len(e.fields)+1)
+	projected["cursor"] = cursor.String()
+	for _, field := range e.fields {
+		key := postModel.Fields[field]
+		if value, ok := all[key]; ok {
+			projected[key] = value
+		}
+	}
+
+	return e.enc.Encode(projected)
+}
+
+func (e *ndjsonEncoder) close() error {
+	return nil
+}
+
+// csvEncoder writes a header row and a row per post, the cursor first.
+type csvEncoder struct {
+	w       *csv.Writer
+	columns map[string]bool
+	started bool
+	record  []string
+}
+
+func (e *csvEncoder) encode(post postModel.Post, cursor postModel.Cursor) error {
+	if !e.started {
+		if err := e.w.Write(e.header()); err != nil {
+			return err
+		}
+		e.started = true
+	}
+
+	e.record = append(e.record[:0], cursor.String())
+	for _, c := range columns {
+		if !e.columns[c] {
+			continue
+		}
+
+		value, err := csvValue(post, c)
+		if err != nil {
+			return err
+		}
+		e.record = append(e.record, value)
+	}
+
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) header() []string {
+	header := []string{"cursor"}
+	for _, c := range columns {
+		if e.columns[c] {
+			header = append(header, c)
+		}
+	}
+	return header
+}
+
+// close writes the header when there were no posts, so the columns are known.
+func (e *csvEncoder) close() error {
+	if !e.started {
+		if err := e.w.Write(e.header()); err != nil {
+			return err
+		}
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvValue(post postModel.Post, column string) (string, error) {
+	switch column {
+	case "id":
+		return strconv.Itoa(post.ID), nil
+	case "title":
+		return post.Title, nil
+	case "content":
+		return post.Content, nil
+	case "content_format":
+		return post.ContentFormat, nil
+	case "content_html":
+		return post.ContentHTML, nil
+	case "excerpt":
+		return post.Excerpt, nil
+	case "word_count":
+		return strconv.Itoa(post.WordCount), nil
+	case "reading_time":
+		return strconv.Itoa(post.ReadingTime), nil
+	case "author_id":
+		return strconv.Itoa(post.AuthorID), nil
+	case "authors":
+		return jsonValue(post.Authors)
+	case "tags":
+		return strings.Join(post.Tags, ","), nil
+	case "media":
+		return jsonValue(post.Media)
+	case "created_at":
+		return post.CreatedAt.Format(time.RFC3339Nano), nil
+	case "updated_at":
+		return post.UpdatedAt.Format(time.RFC3339Nano), nil
+	default:
+		return "", nil
+	}
+}
+
+func jsonValue(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// parquetPost is a row of a parquet export, the columns that were not
+// selected are null.
+type parquetPost struct {
+	Cursor        string     `parquet:"cursor"`
+	ID            int64      `parquet:"id"`
+	Title         *string    `parquet:"title,optional"`
+	Content       *string    `parquet:"content,optional"`
+	ContentFormat *string    `parquet:"content_format,optional"`
+	ContentHTML   *string    `parquet:"content_html,optional"`
+	Excerpt       *string    `parquet:"excerpt,optional"`
+	WordCount     *int64     `parquet:"word_count,optional"`
+	ReadingTime   *int64     `parquet:"reading_time,optional"`
+	AuthorID      *int64     `parquet:"author_id,optional"`
+	Authors       *string    `parquet:"authors,optional"`
+	Tags          []string   `parquet:"tags,list"`
+	Media         *string    `parquet:"media,optional"`
+	CreatedAt     *time.Time `parquet:"created_at,optional"`
+	UpdatedAt     *time.Time `parquet:"updated_at,optional"`
+}
+
+type parquetEncoder struct {
+	w       *parquet.GenericWriter[parquetPost]
+	columns map[string]bool
+	rows    []parquetPost
+}
+
+func (e *parquetEncoder) encode(post postModel.Post, cursor postModel.Cursor) error {
+	row := parquetPost{Cursor: cursor.String(), ID: int64(post.ID)}
+
+	strs := map[string]struct {
+		dst **string
+		v   string
+	}{
+		"title":          {&row.Title, post.Title},
+		"content":        {&row.Content, post.Content},
+		"content_format": {&row.ContentFormat, post.ContentFormat},
+		"content_html":   {&row.ContentHTML, post.ContentHTML},
+		"excerpt":        {&row.Excerpt, post.Excerpt},
+	}
+	for c, s := range strs {
+		if e.columns[c] {
+			v := s.v
+			*s.dst = &v
+		}
+	}
+
+	ints := map[string]struct {
+		dst **int64
+		v   int
+	}{
+		"word_count":   {&row.WordCount, post.WordCount},
+		"reading_time": {&row.ReadingTime, post.ReadingTime},
+		"author_id":    {&row.AuthorID, post.AuthorID},
+	}
+	for c, n := range ints {
+		if e.columns[c] {
+			v := int64(n.v)
+			*n.dst = &v
+		}
+	}
+
+	if e.columns["authors"] {
+		v, err := jsonValue(post.Authors)
+		if err != nil {
+			return err
+		}
+		row.Authors = &v
+	}
+	if e.columns["media"] {
+		v, err := jsonValue(post.Media)
+		if err != nil {
+			return err
+		}
+		row.Media = &v
+	}
+	if e.columns["tags"] {
+		row.Tags = post.Tags
+	}
+	if e.columns["created_at"] {
+		row.CreatedAt = &post.CreatedAt
+	}
+	if e.columns["updated_at"] {
+		row.UpdatedAt = &post.UpdatedAt
+	}
+
+	e.rows[0] = row
+	_, err := e.w.Write(e.rows)
+	return err
+}
+
+func (e *parquetEncoder) close() error {
+	return e.w.Close()
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/post/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExportPostsService is a mock of ExportPostsService interface.
+type MockExportPostsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportPostsServiceMockRecorder
+}
+
+// MockExportPostsServiceMockRecorder is the mock recorder for MockExportPostsService.
+type MockExportPostsServiceMockRecorder struct {
+	mock *MockExportPostsService
+}
+
+// NewMockExportPostsService creates a new mock instance.
+func NewMockExportPostsService(ctrl *gomock.Controller) *MockExportPostsService {
+	mock := &MockExportPostsService{ctrl: ctrl}
+	mock.recorder = &MockExportPostsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportPostsService) EXPECT() *MockExportPostsServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportPosts mocks base method.
+func (m *MockExportPostsService) ExportPosts(ctx context.Context, filter model.Filter, after *model.Cursor, fn func(model.Post, model.Cursor) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPosts", ctx, filter, after, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportPosts indicates an expected call of ExportPosts.
+func (mr *MockExportPostsServiceMockRecorder) ExportPosts(ctx, filter, after, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPosts", reflect.TypeOf((*MockExportPostsService)(nil).ExportPosts), ctx, filter, after, fn)
+}
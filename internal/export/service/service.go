@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/export/model"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+	"io"
+	"log/slog"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type ExportPostsService interface {
+	ExportPosts(
+		ctx context.Context,
+		filter postModel.Filter,
+		after *postModel.Cursor,
+		fn func(post postModel.Post, cursor postModel.Cursor) error,
+	) error
+}
+
+type ExportService struct {
+	logger *slog.Logger
+
+	exportPostsService ExportPostsService
+}
+
+func NewExportService(logger *slog.Logger, exportPostsService ExportPostsService) *ExportService {
+	return &ExportService{
+		logger:             logger,
+		exportPostsService: exportPostsService,
+	}
+}
+
+// Export writes the posts of the request to w as they are read and returns
+// how many were written. On an error the output ends after the last of them,
+// the export can be resumed from its cursor.
+func (s ExportService) Export(ctx context.Context, w io.Writer, req model.Request) (int, error) {
+	const op = "news-crud.internal.export.export.service.Export"
+
+	if err := req.Validation(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	enc, err := newEncoder(req.Format, w, req.Filter.Fields)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var n int
+	err = s.exportPostsService.ExportPosts(ctx, req.Filter, req.After, func(post postModel.Post, cursor postModel.Cursor) error {
+		post.Represent(req.Representation)
+		if err := enc.encode(post, cursor); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		// the posts written so far are flushed, the export resumes after them
+		_ = enc.close()
+		return n, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = enc.close(); err != nil {
+		return n, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/export/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/export/service/mocks"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+var posts = []postModel.Post{
+	{
+		ID:            1,
+		Title:         "First",
+		Content:       "one",
+		ContentFormat: "plain",
+		ContentHTML:   "<p>one</p>",
+		AuthorID:      7,
+		Authors:       []postModel.Author{{UserID: 7, Role: postModel.AuthorRoleLead}},
+		Tags:          []string{"city", "rain"},
+		CreatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+	},
+	{
+		ID:        2,
+		Title:     "Second",
+		Content:   "two",
+		AuthorID:  8,
+		CreatedAt: time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC),
+	},
+}
+
+// streamPosts makes the mock pass the posts to the callback.
+func streamPosts(c *gomock.Controller, filter postModel.Filter, err error) *mock_service.MockExportPostsService {
+	m := mock_service.NewMockExportPostsService(c)
+	m.EXPECT().ExportPosts(gomock.Any(), filter, gomock.Nil(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ postModel.Filter, _ *postModel.Cursor, fn func(postModel.Post, postModel.Cursor) error) error {
+			for _, post := range posts {
+				if err := fn(post, postModel.NewCursor(filter.SortBy, post, 0)); err != nil {
+					return err
+				}
+			}
+			return err
+		})
+	return m
+}
+
+func TestExportService_Export_NDJSON(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	filter := postModel.Filter{Fields: []string{"title", "tags"}}
+	s := NewExportService(slogdiscard.NewDiscardLogger(), streamPosts(c, filter, nil))
+
+	var buf bytes.Buffer
+	n, err := s.Export(context.Background(), &buf, model.Request{Format: model.FormatNDJSON, Filter: filter})
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	lines := bufio.NewScanner(&buf)
+	require.True(t, lines.Scan())
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal(lines.Bytes(), &first))
+	require.Equal(t, map[string]any{
+		"cursor": postModel.Cursor{ID: 1}.String(),
+		"Title":  "First",
+		"Tags":   []any{"city", "rain"},
+	}, first)
+
+	require.True(t, lines.Scan())
+	require.False(t, lines.Scan())
+}
+
+func TestExportService_Export_CSV(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	filter := postModel.Filter{SortBy: postModel.SortByCreatedAt}
+	s := NewExportService(slogdiscard.NewDiscardLogger(), streamPosts(c, filter, nil))
+
+	var buf bytes.Buffer
+	_, err := s.Export(context.Background(), &buf, model.Request{
+		Format:         model.FormatCSV,
+		Filter:         filter,
+		Representation: postModel.RepresentationSource,
+	})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, append([]string{"cursor"}, columns...), records[0])
+
+	require.Equal(t, []string{
+		postModel.NewCursor(postModel.SortByCreatedAt, posts[0], 0).String(),
+		"1", "First", "one", "plain", "", "", "0", "0", "7",
+		`[{"UserID":7,"Role":"lead","Position":0}]`, "city,rain", "null",
+		"2024-05-01T10:00:00Z", "2024-05-02T10:00:00Z",
+	}, records[1])
+}
+
+func TestExportService_Export_Parquet(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	filter := postModel.Filter{Fields: []string{"title", "tags", "created_at"}}
+	s := NewExportService(slogdiscard.NewDiscardLogger(), streamPosts(c, filter, nil))
+
+	var buf bytes.Buffer
+	_, err := s.Export(context.Background(), &buf, model.Request{Format: model.FormatParquet, Filter: filter})
+	require.NoError(t, err)
+
+	rows, err := parquet.Read[parquetPost](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.Equal(t, int64(1), rows[0].ID)
+	require.Equal(t, "First", *rows[0].Title)
+	require.Equal(t, []string{"city", "rain"}, rows[0].Tags)
+	require.True(t, posts[0].CreatedAt.Equal(*rows[0].CreatedAt))
+	require.Nil(t, rows[0].Content, "columns that were not selected are null")
+}
+
+func TestExportService_Export_Fails(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	dbErr := errors.New("db is down")
+	s := NewExportService(slogdiscard.NewDiscardLogger(), streamPosts(c, postModel.Filter{}, dbErr))
+
+	var buf bytes.Buffer
+	n, err := s.Export(context.Background(), &buf, model.Request{Format: model.FormatCSV})
+	require.ErrorIs(t, err, dbErr)
+	require.Equal(t, 2, n)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3, "the posts written before the error are flushed")
+}
+
+func TestExportService_Export_UnknownFormat(t *testing.T) {
+	s := NewExportService(slogdiscard.NewDiscardLogger(), nil)
+
+	_, err := s.Export(context.Background(), &bytes.Buffer{}, model.Request{Format: "xlsx"})
+	require.ErrorIs(t, err, model.ErrUnknownFormat)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/feed/service"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/feed"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GetFeedService interface {
+	GetFeed(ctx context.Context, self string, filter model.FeedFilter) (feed.Feed, error)
+}
+
+type FeedHandler struct {
+	logger *slog.Logger
+
+	getFeedService GetFeedService
+}
+
+func NewFeedHandler(logger *slog.Logger, getFeedService GetFeedService) *FeedHandler {
+	return &FeedHandler{
+		logger:         logger,
+		getFeedService: getFeedService,
+	}
+}
+
+type format struct {
+	contentType string
+	write       func(w io.Writer, f feed.Feed) error
+}
+
+var (
+	rss  = format{contentType: "application/rss+xml; charset=utf-8", write: feed.WriteRSS}
+	atom = format{contentType: "application/atom+xml; charset=utf-8", write: feed.WriteAtom}
+)
+
+func (h FeedHandler) GetRSS(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, rss, model.FeedFilter{})
+}
+
+func (h FeedHandler) GetAtom(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, atom, model.FeedFilter{})
+}
+
+func (h FeedHandler) GetAuthorRSS(w http.ResponseWriter, r *http.Request) {
+	h.serveAuthor(w, r, rss)
+}
+
+func (h FeedHandler) GetAuthorAtom(w http.ResponseWriter, r *http.Request) {
+	h.serveAuthor(w, r, atom)
+}
+
+func (h FeedHandler) GetTagRSS(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, rss, model.FeedFilter{Tag: strings.ToLower(r.PathValue("tag"))})
+}
+
+func (h FeedHandler) GetTagAtom(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, atom, model.FeedFilter{Tag: strings.ToLower(r.PathValue("tag"))})
+}
+
+func (h FeedHandler) serveAuthor(w http.ResponseWriter, r *http.Request, f format) {
+	authorID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || authorID <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.serve(w, r, f, model.FeedFilter{AuthorID: authorID})
+}
+
+// serve renders the feed and answers conditional requests with 304 when the
+// client's copy matches the ETag or is not older than the newest item.
+func (h FeedHandler) serve(w http.ResponseWriter, r *http.Request, f format, filter model.FeedFilter) {
+	const op = "news-crud.internal.feed.get.handler.serve"
+	logger := h.logger.With(slog.String("op", op))
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	fd, err := h.getFeedService.GetFeed(r.Context(), r.URL.Path, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLimit) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		logger.Error("cant get feed", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var body bytes.Buffer
+	if err = f.write(&body, fd); err != nil {
+		logger.Error("cant render feed", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	updated := fd.Updated()
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if !updated.IsZero() {
+		w.Header().Set("Last-Modified", updated.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, updated) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", f.contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err = body.WriteTo(w); err != nil {
+		logger.Error("cant write feed", logs.Err(err))
+	}
+}
+
+// notModified follows RFC 9110, If-Modified-Since is ignored when If-None-Match is sent.
+func notModified(r *http.Request, etag string, updated time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !updated.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !updated.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"github.com/ananaslegend/news-crud/internal/feed/service"
+	mock_service "github.com/ananaslegend/news-crud/internal/feed/service/mocks"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var updated = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newFeedHandler(t *testing.T) *FeedHandler {
+	ctrl := gomock.NewController(t)
+
+	repo := mock_service.NewMockGetFeedPostsRepository(ctrl)
+	repo.EXPECT().GetFeedPosts(gomock.Any(), gomock.Any()).Return([]model.Post{
+		{
+			ID:          2,
+			Title:       "Tom & Jerry",
+			ContentHTML: `<script>alert("x")</script>`,
+			CreatedAt:   updated.Add(-time.Hour),
+			UpdatedAt:   updated,
+		},
+	}, nil).AnyTimes()
+
+	feedSrv := service.NewFeedService(service.Config{
+		BaseURL:      "https://example.com",
+		Title:        "News",
+		ItemLimit:    20,
+		MaxItemLimit: 100,
+	}, repo)
+
+	return NewFeedHandler(slogdiscard.NewDiscardLogger(), feedSrv)
+}
+
+func TestFeedHandler_GetRSS(t *testing.T) {
+	h := newFeedHandler(t)
+
+	w := httptest.NewRecorder()
+	h.GetRSS(w, httptest.NewRequest(http.MethodGet, "/feeds/rss.xml", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/rss+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	require.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+	require.NotEmpty(t, w.Header().Get("ETag"))
+
+	body := w.Body.String()
+	require.Contains(t, body, `<title>Tom &amp; Jerry</title>`)
+	require.Contains(t, body, `<guid isPermaLink="false">https://example.com/posts/2</guid>`)
+	require.Contains(t, body, `&lt;script&gt;`, "the content is escaped")
+	require.NotContains(t, body, `<script>`)
+}
+
+func TestFeedHandler_Conditional(t *testing.T) {
+	h := newFeedHandler(t)
+
+	w := httptest.NewRecorder()
+	h.GetAtom(w, httptest.NewRequest(http.MethodGet, "/feeds/atom.xml", nil))
+	etag := w.Header().Get("ETag")
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		wantCode int
+	}{
+		{name: "Matching ETag", header: "If-None-Match", value: etag, wantCode: http.StatusNotModified},
+		{name: "Weak matching ETag", header: "If-None-Match", value: `"other", W/` + etag, wantCode: http.StatusNotModified},
+		{name: "Any ETag", header: "If-None-Match", value: "*", wantCode: http.StatusNotModified},
+		{name: "Stale ETag", header: "If-None-Match", value: `"other"`, wantCode: http.StatusOK},
+		{name: "Not modified since", header: "If-Modified-Since", value: "Wed, 01 May 2024 12:00:00 GMT", wantCode: http.StatusNotModified},
+		{name: "Modified since", header: "If-Modified-Since", value: "Wed, 01 May 2024 11:59:59 GMT", wantCode: http.StatusOK},
+		{name: "Invalid date", header: "If-Modified-Since", value: "yesterday", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/feeds/atom.xml", nil)
+			r.Header.Set(tt.header, tt.value)
+
+			w := httptest.NewRecorder()
+			h.GetAtom(w, r)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			require.Equal(t, etag, w.Header().Get("ETag"))
+			if tt.wantCode == http.StatusNotModified {
+				require.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFeedHandler_BadRequest(t *testing.T) {
+	h := newFeedHandler(t)
+
+	tests := []struct {
+		name   string
+		target string
+		id     string
+	}{
+		{name: "Limit is not a number", target: "/feeds/rss.xml?limit=ten"},
+		{name: "Zero limit", target: "/feeds/rss.xml?limit=0"},
+		{name: "Limit is too big", target: "/feeds/rss.xml?limit=101"},
+		{name: "Invalid author", target: "/feeds/authors/x/rss.xml", id: "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			w := httptest.NewRecorder()
+			if tt.id != "" {
+				r.SetPathValue("id", tt.id)
+				h.GetAuthorRSS(w, r)
+			} else {
+				h.GetRSS(w, r)
+			}
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
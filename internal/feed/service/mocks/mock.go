@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/post/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGetFeedPostsRepository is a mock of GetFeedPostsRepository interface.
+type MockGetFeedPostsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetFeedPostsRepositoryMockRecorder
+}
+
+// MockGetFeedPostsRepositoryMockRecorder is the mock recorder for MockGetFeedPostsRepository.
+type MockGetFeedPostsRepositoryMockRecorder struct {
+	mock *MockGetFeedPostsRepository
+}
+
+// NewMockGetFeedPostsRepository creates a new mock instance.
+func NewMockGetFeedPostsRepository(ctrl *gomock.Controller) *MockGetFeedPostsRepository {
+	mock := &MockGetFeedPostsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetFeedPostsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetFeedPostsRepository) EXPECT() *MockGetFeedPostsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetFeedPosts mocks base method.
+func (m *MockGetFeedPostsRepository) GetFeedPosts(ctx context.Context, filter model.FeedFilter) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedPosts", ctx, filter)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeedPosts indicates an expected call of GetFeedPosts.
+func (mr *MockGetFeedPostsRepositoryMockRecorder) GetFeedPosts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedPosts", reflect.TypeOf((*MockGetFeedPostsRepository)(nil).GetFeedPosts), ctx, filter)
+}
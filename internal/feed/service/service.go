@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/feed"
+	"strconv"
+	"strings"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+var ErrInvalidLimit = errors.New("limit is out of range")
+
+type GetFeedPostsRepository interface {
+	GetFeedPosts(ctx context.Context, filter model.FeedFilter) ([]model.Post, error)
+}
+
+// Config describes the site the feeds are published for. BaseURL has no
+// trailing slash, ItemLimit is used when the client asks for no limit.
+type Config struct {
+	BaseURL      string
+	Title        string
+	Description  string
+	ItemLimit    int
+	MaxItemLimit int
+}
+
+type FeedService struct {
+	config Config
+
+	getFeedPostsRepository GetFeedPostsRepository
+}
+
+func NewFeedService(config Config, getFeedPostsRepository GetFeedPostsRepository) *FeedService {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &FeedService{
+		config:                 config,
+		getFeedPostsRepository: getFeedPostsRepository,
+	}
+}
+
+// GetFeed builds the feed of the newest posts matching the filter. self is the
+// path of the requested feed, it becomes the feed ID.
+func (s FeedService) GetFeed(ctx context.Context, self string, filter model.FeedFilter) (feed.Feed, error) {
+	const op = "news-crud.internal.feed.get.service.GetFeed"
+
+	if filter.Limit == 0 {
+		filter.Limit = s.config.ItemLimit
+	}
+
+	if filter.Limit < 1 || filter.Limit > s.config.MaxItemLimit {
+		return feed.Feed{}, ErrInvalidLimit
+	}
+
+	posts, err := s.getFeedPostsRepository.GetFeedPosts(ctx, filter)
+	if err != nil {
+		return feed.Feed{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	f := feed.Feed{
+		Title:       s.title(filter),
+		Description: s.config.Description,
+		Link:        s.config.BaseURL + "/posts",
+		Self:        s.config.BaseURL + self,
+		Author:      s.config.Title,
+		Items:       make([]feed.Item, len(posts)),
+	}
+
+	for i, post := range posts {
+		link := s.config.BaseURL + "/posts/" + strconv.Itoa(post.ID)
+
+		f.Items[i] = feed.Item{
+			ID:         link,
+			Title:      post.Title,
+			Link:       link,
+			Content:    post.ContentHTML,
+			Categories: post.Tags,
+			Published:  post.CreatedAt,
+			Updated:    post.UpdatedAt,
+		}
+	}
+
+	return f, nil
+}
+
+func (s FeedService) title(filter model.FeedFilter) string {
+	switch {
+	case filter.AuthorID != 0:
+		return fmt.Sprintf("%s: posts by author %d", s.config.Title, filter.AuthorID)
+	case filter.Tag != "":
+		return fmt.Sprintf("%s: posts tagged %s", s.config.Title, filter.Tag)
+	default:
+		return s.config.Title
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	mock_service "github.com/ananaslegend/news-crud/internal/feed/service/mocks"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+var testConfig = Config{
+	BaseURL:      "https://example.com/",
+	Title:        "News",
+	Description:  "Latest news",
+	ItemLimit:    20,
+	MaxItemLimit: 100,
+}
+
+func TestFeedService_GetFeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    model.FeedFilter
+		wantLimit int
+		wantTitle string
+		wantErr   error
+	}{
+		{name: "Default limit", filter: model.FeedFilter{}, wantLimit: 20, wantTitle: "News"},
+		{name: "Requested limit", filter: model.FeedFilter{Limit: 100}, wantLimit: 100, wantTitle: "News"},
+		{name: "Limit is too big", filter: model.FeedFilter{Limit: 101}, wantErr: ErrInvalidLimit},
+		{name: "Negative limit", filter: model.FeedFilter{Limit: -1}, wantErr: ErrInvalidLimit},
+		{name: "Author feed", filter: model.FeedFilter{AuthorID: 7}, wantLimit: 20, wantTitle: "News: posts by author 7"},
+		{name: "Tag feed", filter: model.FeedFilter{Tag: "go"}, wantLimit: 20, wantTitle: "News: posts tagged go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			repo := mock_service.NewMockGetFeedPostsRepository(ctrl)
+			if tt.wantErr == nil {
+				want := tt.filter
+				want.Limit = tt.wantLimit
+				repo.EXPECT().GetFeedPosts(gomock.Any(), want).Return(nil, nil)
+			}
+
+			s := NewFeedService(testConfig, repo)
+
+			f, err := s.GetFeed(context.Background(), "/feeds/rss.xml", tt.filter)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTitle, f.Title)
+			require.Equal(t, "https://example.com/feeds/rss.xml", f.Self)
+			require.Empty(t, f.Items)
+		})
+	}
+}
+
+func TestFeedService_GetFeed_Items(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	published := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	repo := mock_service.NewMockGetFeedPostsRepository(ctrl)
+	repo.EXPECT().GetFeedPosts(gomock.Any(), gomock.Any()).Return([]model.Post{
+		{
+			ID:          2,
+			Title:       "Second",
+			Content:     "# source",
+			ContentHTML: "<h1>html</h1>",
+			Tags:        []string{"go"},
+			CreatedAt:   published,
+			UpdatedAt:   published.Add(time.Hour),
+		},
+	}, nil)
+
+	s := NewFeedService(testConfig, repo)
+
+	f, err := s.GetFeed(context.Background(), "/feeds/atom.xml", model.FeedFilter{})
+	require.NoError(t, err)
+
+	require.Len(t, f.Items, 1)
+	item := f.Items[0]
+	require.Equal(t, "https://example.com/posts/2", item.ID, "the link of the post is its guid")
+	require.Equal(t, "https://example.com/posts/2", item.Link)
+	require.Equal(t, "<h1>html</h1>", item.Content, "feeds carry the rendered content")
+	require.Equal(t, []string{"go"}, item.Categories)
+	require.Equal(t, published, item.Published)
+	require.Equal(t, published.Add(time.Hour), f.Updated())
+}
+
+func TestFeedService_GetFeed_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	dbErr := errors.New("db is down")
+
+	repo := mock_service.NewMockGetFeedPostsRepository(ctrl)
+	repo.EXPECT().GetFeedPosts(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	s := NewFeedService(testConfig, repo)
+
+	_, err := s.GetFeed(context.Background(), "/feeds/rss.xml", model.FeedFilter{})
+	require.ErrorIs(t, err, dbErr)
+}
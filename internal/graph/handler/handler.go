@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ananaslegend/news-crud/internal/graph/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/graphql-go/graphql"
+	"log/slog"
+	"net/http"
+)
+
+// maxRequestSize bounds the body of a GraphQL request.
+const maxRequestSize = 1 << 20
+
+type ExecuteService interface {
+	Execute(ctx context.Context, req model.Request) *graphql.Result
+}
+
+type GraphHandler struct {
+	logger *slog.Logger
+
+	executeService ExecuteService
+}
+
+func NewGraphHandler(logger *slog.Logger, executeService ExecuteService) *GraphHandler {
+	return &GraphHandler{
+		logger:         logger,
+		executeService: executeService,
+	}
+}
+
+// Query runs a GraphQL request, a JSON body for POST or the query, variables
+// and operationName parameters for GET. GET requests may only run queries.
+func (h GraphHandler) Query(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.graph.query.handler.Query"
+	logger := h.logger.With(slog.String("op", op))
+
+	var req model.Request
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		req.ReadOnly = true
+
+		if v := r.URL.Query().Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("variables should be a JSON object"))
+				return
+			}
+		}
+	default:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+	}
+
+	if req.Query == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("query is required"))
+		return
+	}
+
+	res := h.executeService.Execute(r.Context(), req)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
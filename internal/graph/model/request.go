@@ -0,0 +1,18 @@
+package model
+
+// Request is a GraphQL request. ReadOnly requests, the ones sent with GET, may
+// not run mutations.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	ReadOnly      bool           `json:"-"`
+}
+
+// Limits bound the queries clients may run. Depth counts nested selections,
+// complexity counts the fields to resolve with list fields multiplied by the
+// number of items they ask for.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
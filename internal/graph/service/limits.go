@@ -0,0 +1,129 @@
+package service
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"strconv"
+)
+
+// pageArgument is the argument list fields take the number of items with.
+const pageArgument = "first"
+
+// fielder is an object or an interface of the schema.
+type fielder interface {
+	Fields() graphql.FieldDefinitionMap
+}
+
+// measure finds the depth and the complexity of the operation of a valid
+// document. Introspection fields cost one and are not looked into.
+func measure(schema graphql.Schema, doc *ast.Document, operation *ast.OperationDefinition, variables map[string]any) (int, int) {
+	m := measurer{schema: schema, fragments: make(map[string]*ast.FragmentDefinition), variables: variables}
+
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var root fielder = schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	return m.selectionSet(root, operation.SelectionSet)
+}
+
+type measurer struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// selectionSet returns the depth and the complexity of the selections made on
+// parent.
+func (m measurer) selectionSet(parent fielder, set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = m.field(parent, selection)
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(m.typeCondition(parent, selection.TypeCondition), selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[selection.Name.Value]; ok {
+				d, c = m.selectionSet(m.typeCondition(parent, fragment.TypeCondition), fragment.SelectionSet)
+			}
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+func (m measurer) field(parent fielder, field *ast.Field) (depth, complexity int) {
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	next, _ := graphql.GetNamed(def.Type).(fielder)
+	depth, complexity = m.selectionSet(next, field.SelectionSet)
+
+	return depth + 1, 1 + m.items(def, field)*complexity
+}
+
+// items is the number of items a list field asks for, one for other fields.
+func (m measurer) items(def *graphql.FieldDefinition, field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value == pageArgument {
+			if n, ok := m.intValue(arg.Value); ok {
+				return max(n, 1)
+			}
+		}
+	}
+
+	for _, arg := range def.Args {
+		if arg.Name() == pageArgument {
+			if n, ok := arg.DefaultValue.(int); ok {
+				return max(n, 1)
+			}
+		}
+	}
+
+	return 1
+}
+
+func (m measurer) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := m.variables[value.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// typeCondition is the type a fragment selects on, parent when it has none.
+func (m measurer) typeCondition(parent fielder, condition *ast.Named) fielder {
+	if condition == nil {
+		return parent
+	}
+
+	if t, ok := m.schema.Type(condition.Name.Value).(fielder); ok {
+		return t
+	}
+	return parent
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// Loader batches the loads of a request. Keys asked for while a level of the
+// query is resolved are fetched with one call once the first of their values
+// is needed, values are kept for the rest of the request.
+type Loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		queued: make(map[K]bool),
+		values: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load queues the key and returns a function that waits for its value. The
+// value is missing when fetch did not return one for the key.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, bool, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+
+			values, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+					continue
+				}
+				if v, ok := values[k]; ok {
+					l.values[k] = v
+				}
+			}
+		}
+
+		if err := l.errs[key]; err != nil {
+			var zero V
+			return zero, false, err
+		}
+
+		v, ok := l.values[key]
+		return v, ok, nil
+	}
+}
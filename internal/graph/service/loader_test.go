@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLoader_Load(t *testing.T) {
+	var batches [][]int
+
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		batches = append(batches, keys)
+		if keys[0] == 4 {
+			return nil, errors.New("fetch failed")
+		}
+		return map[int]string{1: "one", 2: "two"}, nil
+	})
+
+	ctx := context.Background()
+
+	one, two, three := loader.Load(ctx, 1), loader.Load(ctx, 2), loader.Load(ctx, 3)
+	again := loader.Load(ctx, 1)
+
+	v, ok, err := one()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "one", v)
+
+	v, _, _ = two()
+	require.Equal(t, "two", v)
+
+	_, ok, err = three()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	v, _, _ = again()
+	require.Equal(t, "one", v)
+
+	_, _, err = loader.Load(ctx, 4)()
+	require.Error(t, err)
+
+	require.Equal(t, [][]int{{1, 2, 3}, {4}}, batches)
+}
@@ -0,0 +1,283 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/post/model"
+	model0 "github.com/ananaslegend/news-crud/internal/user/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCreatePostService is a mock of CreatePostService interface.
+type MockCreatePostService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreatePostServiceMockRecorder
+}
+
+// MockCreatePostServiceMockRecorder is the mock recorder for MockCreatePostService.
+type MockCreatePostServiceMockRecorder struct {
+	mock *MockCreatePostService
+}
+
+// NewMockCreatePostService creates a new mock instance.
+func NewMockCreatePostService(ctrl *gomock.Controller) *MockCreatePostService {
+	mock := &MockCreatePostService{ctrl: ctrl}
+	mock.recorder = &MockCreatePostServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreatePostService) EXPECT() *MockCreatePostServiceMockRecorder {
+	return m.recorder
+}
+
+// CreatePost mocks base method.
+func (m *MockCreatePostService) CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePost", ctx, title, content, contentFormat, excerpt, tags, authorID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePost indicates an expected call of CreatePost.
+func (mr *MockCreatePostServiceMockRecorder) CreatePost(ctx, title, content, contentFormat, excerpt, tags, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockCreatePostService)(nil).CreatePost), ctx, title, content, contentFormat, excerpt, tags, authorID)
+}
+
+// MockGetPostByFilterService is a mock of GetPostByFilterService interface.
+type MockGetPostByFilterService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPostByFilterServiceMockRecorder
+}
+
+// MockGetPostByFilterServiceMockRecorder is the mock recorder for MockGetPostByFilterService.
+type MockGetPostByFilterServiceMockRecorder struct {
+	mock *MockGetPostByFilterService
+}
+
+// NewMockGetPostByFilterService creates a new mock instance.
+func NewMockGetPostByFilterService(ctrl *gomock.Controller) *MockGetPostByFilterService {
+	mock := &MockGetPostByFilterService{ctrl: ctrl}
+	mock.recorder = &MockGetPostByFilterServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPostByFilterService) EXPECT() *MockGetPostByFilterServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPostByFilter mocks base method.
+func (m *MockGetPostByFilterService) GetPostByFilter(ctx context.Context, filter model.Filter) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostByFilter indicates an expected call of GetPostByFilter.
+func (mr *MockGetPostByFilterServiceMockRecorder) GetPostByFilter(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByFilter", reflect.TypeOf((*MockGetPostByFilterService)(nil).GetPostByFilter), ctx, filter)
+}
+
+// MockGetPostByIDService is a mock of GetPostByIDService interface.
+type MockGetPostByIDService struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPostByIDServiceMockRecorder
+}
+
+// MockGetPostByIDServiceMockRecorder is the mock recorder for MockGetPostByIDService.
+type MockGetPostByIDServiceMockRecorder struct {
+	mock *MockGetPostByIDService
+}
+
+// NewMockGetPostByIDService creates a new mock instance.
+func NewMockGetPostByIDService(ctrl *gomock.Controller) *MockGetPostByIDService {
+	mock := &MockGetPostByIDService{ctrl: ctrl}
+	mock.recorder = &MockGetPostByIDServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPostByIDService) EXPECT() *MockGetPostByIDServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPostByID mocks base method.
+func (m *MockGetPostByIDService) GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostByID", ctx, id, fields)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostByID indicates an expected call of GetPostByID.
+func (mr *MockGetPostByIDServiceMockRecorder) GetPostByID(ctx, id, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByID", reflect.TypeOf((*MockGetPostByIDService)(nil).GetPostByID), ctx, id, fields)
+}
+
+// MockUpdatePostService is a mock of UpdatePostService interface.
+type MockUpdatePostService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdatePostServiceMockRecorder
+}
+
+// MockUpdatePostServiceMockRecorder is the mock recorder for MockUpdatePostService.
+type MockUpdatePostServiceMockRecorder struct {
+	mock *MockUpdatePostService
+}
+
+// NewMockUpdatePostService creates a new mock instance.
+func NewMockUpdatePostService(ctrl *gomock.Controller) *MockUpdatePostService {
+	mock := &MockUpdatePostService{ctrl: ctrl}
+	mock.recorder = &MockUpdatePostServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdatePostService) EXPECT() *MockUpdatePostServiceMockRecorder {
+	return m.recorder
+}
+
+// UpdatePost mocks base method.
+func (m *MockUpdatePostService) UpdatePost(ctx context.Context, userID int, post model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePost", ctx, userID, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePost indicates an expected call of UpdatePost.
+func (mr *MockUpdatePostServiceMockRecorder) UpdatePost(ctx, userID, post any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockUpdatePostService)(nil).UpdatePost), ctx, userID, post)
+}
+
+// MockDeletePostService is a mock of DeletePostService interface.
+type MockDeletePostService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletePostServiceMockRecorder
+}
+
+// MockDeletePostServiceMockRecorder is the mock recorder for MockDeletePostService.
+type MockDeletePostServiceMockRecorder struct {
+	mock *MockDeletePostService
+}
+
+// NewMockDeletePostService creates a new mock instance.
+func NewMockDeletePostService(ctrl *gomock.Controller) *MockDeletePostService {
+	mock := &MockDeletePostService{ctrl: ctrl}
+	mock.recorder = &MockDeletePostServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeletePostService) EXPECT() *MockDeletePostServiceMockRecorder {
+	return m.recorder
+}
+
+// DeletePost mocks base method.
+func (m *MockDeletePostService) DeletePost(ctx context.Context, userID, postID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePost", ctx, userID, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePost indicates an expected call of DeletePost.
+func (mr *MockDeletePostServiceMockRecorder) DeletePost(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockDeletePostService)(nil).DeletePost), ctx, userID, postID)
+}
+
+// MockGetUsersByIDsRepository is a mock of GetUsersByIDsRepository interface.
+type MockGetUsersByIDsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetUsersByIDsRepositoryMockRecorder
+}
+
+// MockGetUsersByIDsRepositoryMockRecorder is the mock recorder for MockGetUsersByIDsRepository.
+type MockGetUsersByIDsRepositoryMockRecorder struct {
+	mock *MockGetUsersByIDsRepository
+}
+
+// NewMockGetUsersByIDsRepository creates a new mock instance.
+func NewMockGetUsersByIDsRepository(ctrl *gomock.Controller) *MockGetUsersByIDsRepository {
+	mock := &MockGetUsersByIDsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetUsersByIDsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetUsersByIDsRepository) EXPECT() *MockGetUsersByIDsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUsersByIDs mocks base method.
+func (m *MockGetUsersByIDsRepository) GetUsersByIDs(ctx context.Context, ids []int) (map[int]model0.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[int]model0.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByIDs indicates an expected call of GetUsersByIDs.
+func (mr *MockGetUsersByIDsRepositoryMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockGetUsersByIDsRepository)(nil).GetUsersByIDs), ctx, ids)
+}
+
+// MockGetPostsByAuthorsRepository is a mock of GetPostsByAuthorsRepository interface.
+type MockGetPostsByAuthorsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPostsByAuthorsRepositoryMockRecorder
+}
+
+// MockGetPostsByAuthorsRepositoryMockRecorder is the mock recorder for MockGetPostsByAuthorsRepository.
+type MockGetPostsByAuthorsRepositoryMockRecorder struct {
+	mock *MockGetPostsByAuthorsRepository
+}
+
+// NewMockGetPostsByAuthorsRepository creates a new mock instance.
+func NewMockGetPostsByAuthorsRepository(ctrl *gomock.Controller) *MockGetPostsByAuthorsRepository {
+	mock := &MockGetPostsByAuthorsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetPostsByAuthorsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPostsByAuthorsRepository) EXPECT() *MockGetPostsByAuthorsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPostsByAuthors mocks base method.
+func (m *MockGetPostsByAuthorsRepository) GetPostsByAuthors(ctx context.Context, authorIDs []int, limit int, fields []string) (map[int][]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostsByAuthors", ctx, authorIDs, limit, fields)
+	ret0, _ := ret[0].(map[int][]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostsByAuthors indicates an expected call of GetPostsByAuthors.
+func (mr *MockGetPostsByAuthorsRepositoryMockRecorder) GetPostsByAuthors(ctx, authorIDs, limit, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByAuthors", reflect.TypeOf((*MockGetPostsByAuthorsRepository)(nil).GetPostsByAuthors), ctx, authorIDs, limit, fields)
+}
@@ -0,0 +1,498 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	apiKeyModel "github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	postService "github.com/ananaslegend/news-crud/internal/post/service"
+	userModel "github.com/ananaslegend/news-crud/internal/user/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/markup"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultPostsPage       = 20
+	DefaultAuthorPostsPage = 10
+	MaxPage                = 100
+)
+
+var ErrPageTooLarge = fmt.Errorf("first should be between 1 and %d", MaxPage)
+
+// postFields maps the fields of the Post type to the post fields they are
+// resolved from.
+var postFields = map[string]string{
+	"id":            "id",
+	"title":         "title",
+	"content":       "content",
+	"contentFormat": "content_format",
+	"contentHtml":   "content_html",
+	"excerpt":       "excerpt",
+	"wordCount":     "word_count",
+	"readingTime":   "reading_time",
+	"tags":          "tags",
+	"media":         "media",
+	"authors":       "authors",
+	"leadAuthor":    "author_id",
+	"createdAt":     "created_at",
+	"updatedAt":     "updated_at",
+}
+
+func (s GraphService) newSchema() (graphql.Schema, error) {
+	var postType, userType *graphql.Object
+
+	mediaVariantType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MediaVariant",
+		Fields: graphql.Fields{
+			"name":        field(graphql.NewNonNull(graphql.String), func(v model.MediaVariant) any { return v.Name }),
+			"url":         field(graphql.NewNonNull(graphql.String), func(v model.MediaVariant) any { return v.URL }),
+			"contentType": field(graphql.NewNonNull(graphql.String), func(v model.MediaVariant) any { return v.ContentType }),
+			"width":       field(graphql.NewNonNull(graphql.Int), func(v model.MediaVariant) any { return v.Width }),
+			"height":      field(graphql.NewNonNull(graphql.Int), func(v model.MediaVariant) any { return v.Height }),
+		},
+	})
+
+	mediaType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Media",
+		Fields: graphql.Fields{
+			"id":          field(graphql.NewNonNull(graphql.Int), func(m model.Media) any { return m.ID }),
+			"url":         field(graphql.NewNonNull(graphql.String), func(m model.Media) any { return m.URL }),
+			"contentType": field(graphql.NewNonNull(graphql.String), func(m model.Media) any { return m.ContentType }),
+			"width":       field(graphql.NewNonNull(graphql.Int), func(m model.Media) any { return m.Width }),
+			"height":      field(graphql.NewNonNull(graphql.Int), func(m model.Media) any { return m.Height }),
+			"blurhash":    field(graphql.NewNonNull(graphql.String), func(m model.Media) any { return m.Blurhash }),
+			"variants": field(list(mediaVariantType), func(m model.Media) any {
+				return nonNil(m.Variants)
+			}),
+		},
+	})
+
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Author",
+		Description: "A byline entry of a post.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"userId":   field(graphql.NewNonNull(graphql.Int), func(a model.Author) any { return a.UserID }),
+				"role":     field(graphql.NewNonNull(graphql.String), func(a model.Author) any { return a.Role }),
+				"position": field(graphql.NewNonNull(graphql.Int), func(a model.Author) any { return a.Position }),
+				"user": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return s.user(p, p.Source.(model.Author).UserID)
+					},
+				},
+			}
+		}),
+	})
+
+	postType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Post",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":            field(graphql.NewNonNull(graphql.Int), func(p model.Post) any { return p.ID }),
+				"title":         field(graphql.NewNonNull(graphql.String), func(p model.Post) any { return p.Title }),
+				"content":       field(graphql.NewNonNull(graphql.String), func(p model.Post) any { return p.Content }),
+				"contentFormat": field(graphql.NewNonNull(graphql.String), func(p model.Post) any { return p.ContentFormat }),
+				"contentHtml":   field(graphql.NewNonNull(graphql.String), func(p model.Post) any { return p.ContentHTML }),
+				"excerpt":       field(graphql.NewNonNull(graphql.String), func(p model.Post) any { return p.Excerpt }),
+				"wordCount":     field(graphql.NewNonNull(graphql.Int), func(p model.Post) any { return p.WordCount }),
+				"readingTime": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Int),
+					Description: "Minutes it takes to read the post.",
+					Resolve:     resolve(func(p model.Post) any { return p.ReadingTime }),
+				},
+				"tags":      field(list(graphql.String), func(p model.Post) any { return nonNil(p.Tags) }),
+				"media":     field(list(mediaType), func(p model.Post) any { return nonNil(p.Media) }),
+				"authors":   field(list(authorType), func(p model.Post) any { return nonNil(p.Authors) }),
+				"createdAt": field(graphql.NewNonNull(graphql.DateTime), func(p model.Post) any { return p.CreatedAt }),
+				"updatedAt": field(graphql.NewNonNull(graphql.DateTime), func(p model.Post) any { return p.UpdatedAt }),
+				"leadAuthor": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return s.user(p, p.Source.(model.Post).AuthorID)
+					},
+				},
+			}
+		}),
+	})
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        field(graphql.NewNonNull(graphql.Int), func(u userModel.User) any { return u.ID }),
+				"name":      field(graphql.NewNonNull(graphql.String), func(u userModel.User) any { return u.Name }),
+				"createdAt": field(graphql.NewNonNull(graphql.DateTime), func(u userModel.User) any { return u.CreatedAt }),
+				"posts": &graphql.Field{
+					Type:        list(postType),
+					Description: "The newest posts the user is an author of.",
+					Args: graphql.FieldConfigArgument{
+						pageArgument: {Type: graphql.Int, DefaultValue: DefaultAuthorPostsPage},
+					},
+					Resolve: s.userPosts,
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"post": &graphql.Field{
+				Type: postType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: s.post,
+			},
+			"posts": &graphql.Field{
+				Type:        list(postType),
+				Description: "Posts created between dateFrom and dateTo, sort is created_at or reactions.",
+				Args: graphql.FieldConfigArgument{
+					"dateFrom":   {Type: graphql.NewNonNull(graphql.DateTime)},
+					"dateTo":     {Type: graphql.NewNonNull(graphql.DateTime)},
+					"sort":       {Type: graphql.String, DefaultValue: model.SortByCreatedAt},
+					pageArgument: {Type: graphql.Int, DefaultValue: DefaultPostsPage},
+					"offset":     {Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: s.posts,
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return s.user(p, p.Args["id"].(int))
+				},
+			},
+		},
+	})
+
+	createPostInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreatePostInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":         {Type: graphql.NewNonNull(graphql.String)},
+			"content":       {Type: graphql.NewNonNull(graphql.String)},
+			"contentFormat": {Type: graphql.String},
+			"excerpt":       {Type: graphql.String},
+			"tags":          {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	updatePostInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "UpdatePostInput",
+		Description: "The content format is kept when not set, so are the tags.",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":         {Type: graphql.NewNonNull(graphql.String)},
+			"content":       {Type: graphql.NewNonNull(graphql.String)},
+			"contentFormat": {Type: graphql.String},
+			"excerpt":       {Type: graphql.String},
+			"tags":          {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPost": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Creates a post and returns its id.",
+				Args: graphql.FieldConfigArgument{
+					"input": {Type: graphql.NewNonNull(createPostInput)},
+				},
+				Resolve: s.createPost,
+			},
+			"updatePost": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.Int)},
+					"input": {Type: graphql.NewNonNull(updatePostInput)},
+				},
+				Resolve: s.updatePost,
+			},
+			"deletePost": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: s.deletePost,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	})
+}
+
+func (s GraphService) post(p graphql.ResolveParams) (any, error) {
+	const op = "news-crud.internal.graph.post.service.post"
+
+	if !contexts.HasScope(p.Context, apiKeyModel.ScopePostsRead) {
+		return nil, ErrReadScopeRequired
+	}
+
+	post, err := s.getPostByIDService.GetPostByID(p.Context, p.Args["id"].(int), selectedPostFields(p.Info))
+	if err != nil {
+		if errors.Is(err, postService.ErrNoPostWasFound) {
+			return nil, nil
+		}
+		return nil, s.resolveError(op, err)
+	}
+
+	return post, nil
+}
+
+func (s GraphService) posts(p graphql.ResolveParams) (any, error) {
+	const op = "news-crud.internal.graph.posts.service.posts"
+
+	if !contexts.HasScope(p.Context, apiKeyModel.ScopePostsRead) {
+		return nil, ErrReadScopeRequired
+	}
+
+	first, _ := p.Args[pageArgument].(int)
+	if first < 1 || first > MaxPage {
+		return nil, ErrPageTooLarge
+	}
+
+	filter := model.Filter{
+		Fields: selectedPostFields(p.Info),
+		Limit:  first,
+	}
+	filter.SortBy, _ = p.Args["sort"].(string)
+	filter.Offset, _ = p.Args["offset"].(int)
+	filter.DateFrom, _ = p.Args["dateFrom"].(time.Time)
+	filter.DateTo, _ = p.Args["dateTo"].(time.Time)
+
+	if err := filter.Validation(); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.getPostByFilterService.GetPostByFilter(p.Context, filter)
+	if err != nil {
+		if errors.Is(err, postService.ErrNoPostWasFound) {
+			return []model.Post{}, nil
+		}
+		return nil, s.resolveError(op, err)
+	}
+
+	return posts, nil
+}
+
+// user loads the user with the users of the same level of the query, the
+// user is null when there is none with the ID.
+func (s GraphService) user(p graphql.ResolveParams, id int) (any, error) {
+	const op = "news-crud.internal.graph.user.service.user"
+
+	load := getLoaders(p.Context).users.Load(p.Context, id)
+
+	return func() (any, error) {
+		user, ok, err := load()
+		if err != nil {
+			return nil, s.resolveError(op, err)
+		}
+		if !ok {
+			return nil, nil
+		}
+		return user, nil
+	}, nil
+}
+
+// userPosts loads the posts of the user with the posts of the other users of
+// the same level of the query.
+func (s GraphService) userPosts(p graphql.ResolveParams) (any, error) {
+	const op = "news-crud.internal.graph.user_posts.service.userPosts"
+
+	if !contexts.HasScope(p.Context, apiKeyModel.ScopePostsRead) {
+		return nil, ErrReadScopeRequired
+	}
+
+	first, _ := p.Args[pageArgument].(int)
+	if first < 1 || first > MaxPage {
+		return nil, ErrPageTooLarge
+	}
+
+	loader := getLoaders(p.Context).postsOfAuthors(first, selectedPostFields(p.Info))
+	load := loader.Load(p.Context, p.Source.(userModel.User).ID)
+
+	return func() (any, error) {
+		posts, _, err := load()
+		if err != nil {
+			return nil, s.resolveError(op, err)
+		}
+		return nonNil(posts), nil
+	}, nil
+}
+
+func (s GraphService) createPost(p graphql.ResolveParams) (any, error) {
+	const op = "news-crud.internal.graph.create_post.service.createPost"
+
+	userID, err := writer(p)
+	if err != nil {
+		return nil, err
+	}
+
+	input := p.Args["input"].(map[string]any)
+	title, _ := input["title"].(string)
+	content, _ := input["content"].(string)
+	contentFormat, _ := input["contentFormat"].(string)
+	excerpt, _ := input["excerpt"].(string)
+
+	postID, err := s.createPostService.CreatePost(p.Context, title, content, contentFormat, excerpt,
+		stringList(input["tags"]), userID)
+	if err != nil {
+		return nil, s.resolveError(op, err)
+	}
+
+	return postID, nil
+}
+
+func (s GraphService) updatePost(p graphql.ResolveParams) (any, error) {
+	const op = "news-crud.internal.graph.update_post.service.updatePost"
+
+	userID, err := writer(p)
+	if err != nil {
+		return nil, err
+	}
+
+	input := p.Args["input"].(map[string]any)
+
+	post := model.Post{ID: p.Args["id"].(int)}
+	post.Title, _ = input["title"].(string)
+	post.Content, _ = input["content"].(string)
+	post.ContentFormat, _ = input["contentFormat"].(string)
+	post.Excerpt, _ = input["excerpt"].(string)
+	post.Tags = stringList(input["tags"])
+
+	if err = s.updatePostService.UpdatePost(p.Context, userID, post); err != nil {
+		return nil, s.resolveError(op, err)
+	}
+
+	return true, nil
+}
+
+func (s GraphService) deletePost(p graphql.ResolveParams) (any, error) {
+	const op = "news-crud.internal.graph.delete_post.service.deletePost"
+
+	userID, err := writer(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.deletePostService.DeletePost(p.Context, userID, p.Args["id"].(int)); err != nil {
+		return nil, s.resolveError(op, err)
+	}
+
+	return true, nil
+}
+
+// resolveError passes errors clients can act on and hides the others.
+func (s GraphService) resolveError(op string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrTooManyTags), errors.Is(err, model.ErrInvalidTagLen),
+		errors.Is(err, markup.ErrUnknownFormat), errors.Is(err, model.ErrExcerptTooLong),
+		errors.Is(err, postService.ErrNoPostWasFound), errors.Is(err, postService.ErrUserHasNoPermission):
+		return err
+	default:
+		s.logger.Error("cant resolve field", slog.String("op", op), logs.Err(err))
+		return ErrInternal
+	}
+}
+
+// writer returns the user of a mutation, api keys need the posts:write scope
+// for it.
+func writer(p graphql.ResolveParams) (int, error) {
+	userID, ok := contexts.GetUserID(p.Context)
+	if !ok {
+		return 0, ErrCredentialsRequired
+	}
+
+	if !contexts.HasScope(p.Context, apiKeyModel.ScopePostsWrite) {
+		return 0, ErrScopeRequired
+	}
+
+	return userID, nil
+}
+
+// selectedPostFields lists the post fields the selections of a Post field
+// need, fragments included.
+func selectedPostFields(info graphql.ResolveInfo) []string {
+	fields := make([]string, 0)
+	seen := make(map[string]bool)
+
+	var collect func(set *ast.SelectionSet)
+	collect = func(set *ast.SelectionSet) {
+		if set == nil {
+			return
+		}
+
+		for _, selection := range set.Selections {
+			switch selection := selection.(type) {
+			case *ast.Field:
+				if field, ok := postFields[selection.Name.Value]; ok && !seen[field] {
+					seen[field] = true
+					fields = append(fields, field)
+				}
+			case *ast.InlineFragment:
+				collect(selection.SelectionSet)
+			case *ast.FragmentSpread:
+				if fragment, ok := info.Fragments[selection.Name.Value].(*ast.FragmentDefinition); ok {
+					collect(fragment.SelectionSet)
+				}
+			}
+		}
+	}
+
+	for _, field := range info.FieldASTs {
+		collect(field.SelectionSet)
+	}
+
+	return fields
+}
+
+// field is a field resolved from the source of type T.
+func field[T any](t graphql.Output, fn func(source T) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: resolve(fn)}
+}
+
+func resolve[T any](fn func(source T) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return fn(p.Source.(T)), nil
+	}
+}
+
+// list is a non null list of non null items.
+func list(t graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+// nonNil keeps lists that were not loaded from being null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// stringList converts a list argument, nil stays nil.
+func stringList(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
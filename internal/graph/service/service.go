@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	graphModel "github.com/ananaslegend/news-crud/internal/graph/model"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	userModel "github.com/ananaslegend/news-crud/internal/user/model"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+var (
+	ErrQueryTooDeep        = errors.New("query is too deep")
+	ErrQueryTooComplex     = errors.New("query is too complex")
+	ErrMutationNotAllowed  = errors.New("mutations should be sent with POST")
+	ErrUnknownOperation    = errors.New("unknown operation")
+	ErrCredentialsRequired = errors.New("credentials are required")
+	ErrScopeRequired       = errors.New("api key has no posts:write scope")
+	ErrReadScopeRequired   = errors.New("api key has no posts:read scope")
+	ErrInternal            = errors.New("internal error")
+)
+
+type CreatePostService interface {
+	CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error)
+}
+
+type GetPostByFilterService interface {
+	GetPostByFilter(ctx context.Context, filter model.Filter) ([]model.Post, error)
+}
+
+type GetPostByIDService interface {
+	GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error)
+}
+
+type UpdatePostService interface {
+	UpdatePost(ctx context.Context, userID int, post model.Post) error
+}
+
+type DeletePostService interface {
+	DeletePost(ctx context.Context, userID, postID int) error
+}
+
+type GetUsersByIDsRepository interface {
+	GetUsersByIDs(ctx context.Context, ids []int) (map[int]userModel.User, error)
+}
+
+type GetPostsByAuthorsRepository interface {
+	GetPostsByAuthors(ctx context.Context, authorIDs []int, limit int, fields []string) (map[int][]model.Post, error)
+}
+
+// GraphService runs GraphQL queries over posts and their authors. Mutations go
+// through the post service and its permission checks.
+type GraphService struct {
+	logger *slog.Logger
+	limits graphModel.Limits
+	schema graphql.Schema
+
+	createPostService      CreatePostService
+	getPostByFilterService GetPostByFilterService
+	getPostByIDService     GetPostByIDService
+	updatePostService      UpdatePostService
+	deletePostService      DeletePostService
+
+	getUsersByIDsRepository     GetUsersByIDsRepository
+	getPostsByAuthorsRepository GetPostsByAuthorsRepository
+}
+
+func NewGraphService(
+	logger *slog.Logger,
+	limits graphModel.Limits,
+	createPostService CreatePostService,
+	getPostByFilterService GetPostByFilterService,
+	getPostByIDService GetPostByIDService,
+	updatePostService UpdatePostService,
+	deletePostService DeletePostService,
+	getUsersByIDsRepository GetUsersByIDsRepository,
+	getPostsByAuthorsRepository GetPostsByAuthorsRepository,
+) (*GraphService, error) {
+	s := &GraphService{
+		logger:                      logger,
+		limits:                      limits,
+		createPostService:           createPostService,
+		getPostByFilterService:      getPostByFilterService,
+		getPostByIDService:          getPostByIDService,
+		updatePostService:           updatePostService,
+		deletePostService:           deletePostService,
+		getUsersByIDsRepository:     getUsersByIDsRepository,
+		getPostsByAuthorsRepository: getPostsByAuthorsRepository,
+	}
+
+	var err error
+	if s.schema, err = s.newSchema(); err != nil {
+		return nil, fmt.Errorf("cant build graphql schema: %w", err)
+	}
+
+	return s, nil
+}
+
+// Execute parses and validates the request, checks it against the limits and
+// runs it. Errors are reported in the result the way GraphQL clients expect.
+func (s GraphService) Execute(ctx context.Context, req graphModel.Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if res := graphql.ValidateDocument(&s.schema, doc, nil); !res.IsValid {
+		return &graphql.Result{Errors: res.Errors}
+	}
+
+	operation, err := findOperation(doc, req.OperationName)
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if req.ReadOnly && operation.Operation != ast.OperationTypeQuery {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(ErrMutationNotAllowed)}
+	}
+
+	depth, complexity := measure(s.schema, doc, operation, req.Variables)
+	if depth > s.limits.MaxDepth {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(
+			fmt.Errorf("%w: depth %d, at most %d", ErrQueryTooDeep, depth, s.limits.MaxDepth))}
+	}
+	if complexity > s.limits.MaxComplexity {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(
+			fmt.Errorf("%w: complexity %d, at most %d", ErrQueryTooComplex, complexity, s.limits.MaxComplexity))}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, loadersKey{}, s.newLoaders()),
+	})
+}
+
+// findOperation picks the operation to run, the only one of the document when
+// no name is given.
+func findOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if found != nil {
+				return nil, fmt.Errorf("%w: operation name is required for a document with many operations", ErrUnknownOperation)
+			}
+			found = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			found = operation
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOperation, name)
+	}
+
+	return found, nil
+}
+
+type loadersKey struct{}
+
+// loaders are the loaders of a request. Posts of authors are loaded with one
+// loader for every number of posts and set of fields asked for.
+type loaders struct {
+	users *Loader[int, userModel.User]
+
+	mu             sync.Mutex
+	postsByAuthors map[string]*Loader[int, []model.Post]
+	fetchPosts     func(limit int, fields []string) func(ctx context.Context, authorIDs []int) (map[int][]model.Post, error)
+}
+
+func (s GraphService) newLoaders() *loaders {
+	return &loaders{
+		users:          NewLoader(s.getUsersByIDsRepository.GetUsersByIDs),
+		postsByAuthors: make(map[string]*Loader[int, []model.Post]),
+		fetchPosts: func(limit int, fields []string) func(ctx context.Context, authorIDs []int) (map[int][]model.Post, error) {
+			return func(ctx context.Context, authorIDs []int) (map[int][]model.Post, error) {
+				return s.getPostsByAuthorsRepository.GetPostsByAuthors(ctx, authorIDs, limit, fields)
+			}
+		},
+	}
+}
+
+func getLoaders(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func (l *loaders) postsOfAuthors(limit int, fields []string) *Loader[int, []model.Post] {
+	key := strconv.Itoa(limit) + ":" + strings.Join(fields, ",")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	loader, ok := l.postsByAuthors[key]
+	if !ok {
+		loader = NewLoader(l.fetchPosts(limit, fields))
+		l.postsByAuthors[key] = loader
+	}
+
+	return loader
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	graphModel "github.com/ananaslegend/news-crud/internal/graph/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/graph/service/mocks"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	postService "github.com/ananaslegend/news-crud/internal/post/service"
+	userModel "github.com/ananaslegend/news-crud/internal/user/model"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type mocks struct {
+	create        *mock_service.MockCreatePostService
+	byFilter      *mock_service.MockGetPostByFilterService
+	byID          *mock_service.MockGetPostByIDService
+	update        *mock_service.MockUpdatePostService
+	delete        *mock_service.MockDeletePostService
+	users         *mock_service.MockGetUsersByIDsRepository
+	postsByAuthor *mock_service.MockGetPostsByAuthorsRepository
+}
+
+func newGraphService(t *testing.T, limits graphModel.Limits) (*GraphService, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		create:        mock_service.NewMockCreatePostService(ctrl),
+		byFilter:      mock_service.NewMockGetPostByFilterService(ctrl),
+		byID:          mock_service.NewMockGetPostByIDService(ctrl),
+		update:        mock_service.NewMockUpdatePostService(ctrl),
+		delete:        mock_service.NewMockDeletePostService(ctrl),
+		users:         mock_service.NewMockGetUsersByIDsRepository(ctrl),
+		postsByAuthor: mock_service.NewMockGetPostsByAuthorsRepository(ctrl),
+	}
+
+	s, err := NewGraphService(slogdiscard.NewDiscardLogger(), limits, m.create, m.byFilter, m.byID, m.update, m.delete,
+		m.users, m.postsByAuthor)
+	require.NoError(t, err)
+
+	return s, m
+}
+
+func toJSON(t *testing.T, res *graphql.Result) string {
+	b, err := json.Marshal(res)
+	require.NoError(t, err)
+	return string(b)
+}
+
+var limits = graphModel.Limits{MaxDepth: 8, MaxComplexity: 2000}
+
+func TestGraphService_Execute(t *testing.T) {
+	t.Run("Authors of a list are loaded in one batch", func(t *testing.T) {
+		s, m := newGraphService(t, limits)
+
+		m.byFilter.EXPECT().GetPostByFilter(gomock.Any(), model.Filter{
+			DateFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			DateTo:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			SortBy:   model.SortByCreatedAt,
+			Fields:   []string{"id", "author_id", "authors"},
+			Limit:    2,
+		}).Return([]model.Post{
+			{ID: 1, AuthorID: 10, Authors: []model.Author{{UserID: 10, Role: model.AuthorRoleLead}}},
+			{ID: 2, AuthorID: 11, Authors: []model.Author{
+				{UserID: 11, Role: model.AuthorRoleLead},
+				{UserID: 10, Role: model.AuthorRoleContributor, Position: 1},
+			}},
+		}, nil)
+
+		m.users.EXPECT().GetUsersByIDs(gomock.Any(), []int{10, 11}).Return(map[int]userModel.User{
+			10: {ID: 10, Name: "Ann"},
+			11: {ID: 11, Name: "Bob"},
+		}, nil)
+
+		res := s.Execute(context.Background(), graphModel.Request{Query: `{
+			posts(dateFrom: "2024-01-01T00:00:00Z", dateTo: "2024-02-01T00:00:00Z", first: 2) {
+				id
+				leadAuthor { name }
+				...byline
+			}
+		}
+		fragment byline on Post { authors { role user { name } } }`})
+
+		require.JSONEq(t, `{"data": {"posts": [
+			{"id": 1, "leadAuthor": {"name": "Ann"}, "authors": [{"role": "lead", "user": {"name": "Ann"}}]},
+			{"id": 2, "leadAuthor": {"name": "Bob"}, "authors": [
+				{"role": "lead", "user": {"name": "Bob"}},
+				{"role": "contributor", "user": {"name": "Ann"}}
+			]}
+		]}}`, toJSON(t, res))
+	})
+
+	t.Run("Posts of users are loaded in one batch", func(t *testing.T) {
+		s, m := newGraphService(t, limits)
+
+		m.byID.EXPECT().GetPostByID(gomock.Any(), 1, []string{"authors"}).Return(model.Post{
+			ID: 1,
+			Authors: []model.Author{
+				{UserID: 10, Role: model.AuthorRoleLead},
+				{UserID: 11, Role: model.AuthorRoleContributor, Position: 1},
+			},
+		}, nil)
+
+		m.users.EXPECT().GetUsersByIDs(gomock.Any(), []int{10, 11}).Return(map[int]userModel.User{
+			10: {ID: 10},
+			11: {ID: 11},
+		}, nil)
+
+		m.postsByAuthor.EXPECT().GetPostsByAuthors(gomock.Any(), []int{10, 11}, 3, []string{"title"}).
+			Return(map[int][]model.Post{10: {{ID: 1, Title: "first"}}}, nil)
+
+		res := s.Execute(context.Background(), graphModel.Request{
+			Query:     `query($n: Int) { post(id: 1) { authors { user { posts(first: $n) { title } } } } }`,
+			Variables: map[string]any{"n": float64(3)},
+		})
+
+		require.JSONEq(t, `{"data": {"post": {"authors": [
+			{"user": {"posts": [{"title": "first"}]}},
+			{"user": {"posts": []}}
+		]}}}`, toJSON(t, res))
+	})
+
+	t.Run("Missing post is null", func(t *testing.T) {
+		s, m := newGraphService(t, limits)
+
+		m.byID.EXPECT().GetPostByID(gomock.Any(), 1, []string{"title"}).
+			Return(model.Post{}, postService.ErrNoPostWasFound)
+
+		res := s.Execute(context.Background(), graphModel.Request{Query: `{ post(id: 1) { title } }`})
+		require.JSONEq(t, `{"data": {"post": null}}`, toJSON(t, res))
+	})
+
+	t.Run("Too deep queries are rejected", func(t *testing.T) {
+		s, _ := newGraphService(t, graphModel.Limits{MaxDepth: 3, MaxComplexity: 2000})
+
+		res := s.Execute(context.Background(), graphModel.Request{
+			Query: `{ post(id: 1) { authors { user { name } } } }`,
+		})
+		require.Len(t, res.Errors, 1)
+		require.Contains(t, res.Errors[0].Message, ErrQueryTooDeep.Error())
+	})
+
+	t.Run("Too complex queries are rejected", func(t *testing.T) {
+		s, _ := newGraphService(t, graphModel.Limits{MaxDepth: 8, MaxComplexity: 100})
+
+		res := s.Execute(context.Background(), graphModel.Request{
+			Query: `{ posts(dateFrom: "2024-01-01T00:00:00Z", dateTo: "2024-02-01T00:00:00Z", first: 50) {
+				id
+				leadAuthor { posts(first: 10) { id } }
+			} }`,
+		})
+		require.Len(t, res.Errors, 1)
+		require.Contains(t, res.Errors[0].Message, ErrQueryTooComplex.Error())
+	})
+
+	t.Run("Api keys need the posts:read scope", func(t *testing.T) {
+		s, _ := newGraphService(t, limits)
+
+		ctx := contexts.SetAPIKey(contexts.SetUserID(context.Background(), 7), 1, []string{"posts:write"})
+		res := s.Execute(ctx, graphModel.Request{Query: `{ post(id: 1) { title } }`})
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, ErrReadScopeRequired.Error(), res.Errors[0].Message)
+	})
+
+	t.Run("Mutations need credentials", func(t *testing.T) {
+		s, _ := newGraphService(t, limits)
+
+		res := s.Execute(context.Background(), graphModel.Request{Query: `mutation { deletePost(id: 1) }`})
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, ErrCredentialsRequired.Error(), res.Errors[0].Message)
+	})
+
+	t.Run("Read only requests can not run mutations", func(t *testing.T) {
+		s, _ := newGraphService(t, limits)
+
+		ctx := contexts.SetUserID(context.Background(), 7)
+		res := s.Execute(ctx, graphModel.Request{Query: `mutation { deletePost(id: 1) }`, ReadOnly: true})
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, ErrMutationNotAllowed.Error(), res.Errors[0].Message)
+	})
+
+	t.Run("Mutations go through the post service", func(t *testing.T) {
+		s, m := newGraphService(t, limits)
+
+		ctx := contexts.SetUserID(context.Background(), 7)
+
+		m.create.EXPECT().CreatePost(gomock.Any(), "title", "content", "markdown", "", []string{"go"}, 7).
+			Return(3, nil)
+		m.update.EXPECT().UpdatePost(gomock.Any(), 7, model.Post{ID: 3, Title: "new", Content: "content"}).
+			Return(postService.ErrUserHasNoPermission)
+
+		res := s.Execute(ctx, graphModel.Request{Query: `mutation {
+			createPost(input: {title: "title", content: "content", contentFormat: "markdown", tags: ["go"]})
+		}`})
+		require.JSONEq(t, `{"data": {"createPost": 3}}`, toJSON(t, res))
+
+		res = s.Execute(ctx, graphModel.Request{Query: `mutation {
+			updatePost(id: 3, input: {title: "new", content: "content"})
+		}`})
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, postService.ErrUserHasNoPermission.Error(), res.Errors[0].Message)
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/importer/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+type ImportService interface {
+	Import(ctx context.Context, r io.Reader, opts model.Options) (model.Report, error)
+}
+
+type ImportHandler struct {
+	logger    *slog.Logger
+	maxSize   int64
+	batchSize int
+
+	importService ImportService
+}
+
+func NewImportHandler(logger *slog.Logger, maxSize int64, batchSize int, importService ImportService) *ImportHandler {
+	return &ImportHandler{
+		logger:        logger,
+		maxSize:       maxSize,
+		batchSize:     batchSize,
+		importService: importService,
+	}
+}
+
+// Import reads the export from the body. The format is taken from the format
+// parameter or else the content type, authors are mapped with repeated
+// author=name=userID parameters.
+func (h ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.importer.import.handler.Import"
+	logger := h.logger.With(slog.String("op", op))
+
+	opts, err := parseOptions(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	opts.ActorID = contexts.MustGetUserID(r.Context())
+	opts.BatchSize = h.batchSize
+
+	body := http.MaxBytesReader(w, r.Body, h.maxSize)
+	defer body.Close()
+
+	report, err := h.importService.Import(r.Context(), body, opts)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		case errors.Is(err, model.ErrUnknownFormat),
+			errors.Is(err, model.ErrEmptySource),
+			errors.Is(err, model.ErrMalformed):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		default:
+			logger.Error("cant import posts", logs.Err(err), slog.Int("records", report.Total))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(report); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func parseOptions(r *http.Request) (model.Options, error) {
+	q := r.URL.Query()
+
+	opts := model.Options{
+		Format: q.Get("format"),
+		Source: q.Get("source"),
+	}
+
+	if opts.Format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		opts.Format = formatOf(mediaType)
+	}
+	if opts.Source == "" {
+		opts.Source = model.DefaultSource(opts.Format)
+	}
+
+	var err error
+	if opts.Authors, err = model.ParseAuthors(q["author"]); err != nil {
+		return model.Options{}, err
+	}
+
+	if v := q.Get("default_author_id"); v != "" {
+		if opts.DefaultAuthorID, err = strconv.Atoi(v); err != nil {
+			return model.Options{}, err
+		}
+	}
+
+	return opts, opts.Validation()
+}
+
+func formatOf(mediaType string) string {
+	switch mediaType {
+	case "application/x-ndjson", "application/jsonl":
+		return model.FormatNDJSON
+	case "text/csv":
+		return model.FormatCSV
+	case "application/xml", "text/xml", "application/rss+xml":
+		return model.FormatWXR
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatWXR    = "wxr"
+)
+
+// SourceWordPress is the source of wxr imports unless another one is given.
+const SourceWordPress = "wordpress"
+
+const (
+	DefaultBatchSize = 100
+
+	// MaxReportErrors is how many record errors a report lists, the failed
+	// records are still counted past it.
+	MaxReportErrors = 1000
+)
+
+var (
+	ErrUnknownFormat = errors.New("format should be one of ndjson, csv, wxr")
+	ErrEmptySource   = errors.New("source should not be empty")
+	ErrEmptyTitle    = errors.New("title should not be empty")
+	ErrEmptyContent  = errors.New("content should not be empty")
+	ErrUnknownAuthor = errors.New("author is not known")
+
+	// ErrMalformed is an export that can not be read past some point, the
+	// records before it were imported.
+	ErrMalformed = errors.New("export is malformed")
+)
+
+// Record is a post read from an export. Position is where it was found, the
+// line for ndjson and csv and the item number for wxr. Author is the name the
+// export knows the author by, AuthorEmail the email when the export has one.
+type Record struct {
+	Position      int
+	SourceID      string
+	Title         string
+	Content       string
+	ContentFormat string
+	Excerpt       string
+	Tags          []string
+	Author        string
+	AuthorEmail   string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Options of an import. Posts are imported once per Source and source ID, so
+// an import that stopped half way can be run again. Authors maps the names or
+// emails of the export to users, authors found in neither Authors nor the
+// users by email get DefaultAuthorID, their posts fail when it is zero.
+type Options struct {
+	Format          string
+	Source          string
+	Authors         map[string]int
+	DefaultAuthorID int
+	ActorID         int
+	BatchSize       int
+}
+
+// DefaultSource is the source of an import in the format when none is given,
+// empty when the format has none.
+func DefaultSource(format string) string {
+	if format == FormatWXR {
+		return SourceWordPress
+	}
+	return ""
+}
+
+// ParseAuthors reads author mappings written as "name=userID".
+func ParseAuthors(mappings []string) (map[string]int, error) {
+	authors := make(map[string]int, len(mappings))
+	for _, m := range mappings {
+		i := strings.LastIndexByte(m, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("author %q should be name=userID", m)
+		}
+
+		id, err := strconv.Atoi(m[i+1:])
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("author %q should map to a user id", m)
+		}
+		authors[m[:i]] = id
+	}
+	return authors, nil
+}
+
+func (o Options) Validation() error {
+	switch o.Format {
+	case FormatNDJSON, FormatCSV, FormatWXR:
+	default:
+		return ErrUnknownFormat
+	}
+
+	if o.Source == "" {
+		return ErrEmptySource
+	}
+
+	return nil
+}
+
+// Report tells what became of the records of an import. Every record is
+// counted once as created, duplicate, skipped or failed.
+type Report struct {
+	Source     string        `json:"source"`
+	Total      int           `json:"total"`
+	Created    int           `json:"created"`
+	Duplicates int           `json:"duplicates"`
+	Skipped    int           `json:"skipped"`
+	Failed     int           `json:"failed"`
+	Errors     []RecordError `json:"errors"`
+}
+
+// RecordError is why a record failed.
+type RecordError struct {
+	Position int    `json:"position"`
+	SourceID string `json:"source_id,omitempty"`
+	Error    string `json:"error"`
+}
+
+func NewReport(source string) Report {
+	return Report{Source: source, Errors: []RecordError{}}
+}
+
+func (r *Report) Fail(rec Record, err error) {
+	r.Failed++
+	if len(r.Errors) < MaxReportErrors {
+		r.Errors = append(r.Errors, RecordError{Position: rec.Position, SourceID: rec.SourceID, Error: err.Error()})
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/importer/model"
+	"github.com/ananaslegend/news-crud/pkg/markup"
+	"github.com/ananaslegend/news-crud/pkg/wxr"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	// errSkipped is a record left out on purpose, like a WordPress page.
+	errSkipped = errors.New("skipped")
+
+	errNoHeader = errors.New("csv header should name an id column")
+)
+
+// recordError is a record that can not be read, the decoder goes on with the
+// next one.
+type recordError struct {
+	err error
+}
+
+func (e *recordError) Error() string {
+	return e.err.Error()
+}
+
+func (e *recordError) Unwrap() error {
+	return e.err
+}
+
+// decoder reads the records of an export one at a time, io.EOF after the
+// last one. Errors wrapping errSkipped or a *recordError come with the record
+// they are about, any other error ends the import.
+type decoder interface {
+	next() (model.Record, error)
+}
+
+func newDecoder(format string, r io.Reader) (decoder, error) {
+	switch format {
+	case model.FormatNDJSON:
+		return &ndjsonDecoder{r: bufio.NewReader(r)}, nil
+	case model.FormatCSV:
+		return &csvDecoder{r: csv.NewReader(r)}, nil
+	case model.FormatWXR:
+		return &wxrDecoder{r: wxr.NewReader(r)}, nil
+	default:
+		return nil, model.ErrUnknownFormat
+	}
+}
+
+// sourceID is the id of a record, exports write it as a string or a number.
+type sourceID string
+
+func (id *sourceID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = sourceID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("id should be a string or a number")
+	}
+	*id = sourceID(n)
+	return nil
+}
+
+type jsonRecord struct {
+	ID            sourceID  `json:"id"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	Excerpt       string    `json:"excerpt"`
+	Tags          []string  `json:"tags"`
+	Author        string    `json:"author"`
+	AuthorEmail   string    `json:"author_email"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ndjsonDecoder reads one JSON object per line, blank lines are ignored.
+type ndjsonDecoder struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (d *ndjsonDecoder) next() (model.Record, error) {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+			return model.Record{}, err
+		}
+		d.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		rec := model.Record{Position: d.line}
+
+		var jr jsonRecord
+		if err = json.Unmarshal(line, &jr); err != nil {
+			return rec, &recordError{err: err}
+		}
+
+		rec.SourceID = string(jr.ID)
+		rec.Title = jr.Title
+		rec.Content = jr.Content
+		rec.ContentFormat = jr.ContentFormat
+		rec.Excerpt = jr.Excerpt
+		rec.Tags = jr.Tags
+		rec.Author = jr.Author
+		rec.AuthorEmail = jr.AuthorEmail
+		rec.CreatedAt = jr.CreatedAt
+		rec.UpdatedAt = jr.UpdatedAt
+
+		return rec, nil
+	}
+}
+
+// csvDecoder reads the columns named in the header row, the same as the
+// ndjson fields. Tags are separated by commas, times are RFC 3339.
+type csvDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func (d *csvDecoder) next() (model.Record, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return model.Record{}, err
+		}
+	}
+
+	fields, err := d.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return model.Record{Position: parseErr.StartLine}, &recordError{err: parseErr.Err}
+		}
+		return model.Record{}, err
+	}
+
+	line, _ := d.r.FieldPos(0)
+	rec := model.Record{
+		Position:      line,
+		SourceID:      d.field(fields, "id"),
+		Title:         d.field(fields, "title"),
+		Content:       d.field(fields, "content"),
+		ContentFormat: d.field(fields, "content_format"),
+		Excerpt:       d.field(fields, "excerpt"),
+		Author:        d.field(fields, "author"),
+		AuthorEmail:   d.field(fields, "author_email"),
+	}
+
+	if tags := d.field(fields, "tags"); tags != "" {
+		rec.Tags = strings.Split(tags, ",")
+	}
+
+	times := map[string]*time.Time{
+		"created_at": &rec.CreatedAt,
+		"updated_at": &rec.UpdatedAt,
+	}
+	for name, dst := range times {
+		v := d.field(fields, name)
+		if v == "" {
+			continue
+		}
+		if *dst, err = time.Parse(time.RFC3339, v); err != nil {
+			return rec, &recordError{err: fmt.Errorf("%s: %w", name, err)}
+		}
+	}
+
+	return rec, nil
+}
+
+func (d *csvDecoder) readHeader() error {
+	header, err := d.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errNoHeader
+		}
+		return err
+	}
+
+	d.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		d.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := d.columns["id"]; !ok {
+		return errNoHeader
+	}
+
+	return nil
+}
+
+func (d *csvDecoder) field(fields []string, name string) string {
+	i, ok := d.columns[name]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(fields[i])
+}
+
+// wxrDecoder reads the published posts of a WordPress export. The content is
+// HTML, tags and categories both become tags.
+type wxrDecoder struct {
+	r     *wxr.Reader
+	items int
+}
+
+func (d *wxrDecoder) next() (model.Record, error) {
+	item, err := d.r.Next()
+	if err != nil {
+		var itemErr *wxr.ItemError
+		if !errors.As(err, &itemErr) {
+			return model.Record{}, err
+		}
+	}
+	d.items++
+
+	rec := model.Record{
+		Position:      d.items,
+		SourceID:      item.ID,
+		Title:         item.Title,
+		Content:       item.Content,
+		ContentFormat: markup.FormatHTML,
+		Excerpt:       item.Excerpt,
+		Tags:          append(item.Terms("post_tag"), item.Terms("category")...),
+		Author:        item.Creator,
+		AuthorEmail:   d.r.Authors()[item.Creator].Email,
+		CreatedAt:     item.Date,
+		UpdatedAt:     item.Modified,
+	}
+
+	switch {
+	case err != nil:
+		return rec, &recordError{err: err}
+	case item.Type != "post":
+		return rec, fmt.Errorf("%w: post type %s", errSkipped, item.Type)
+	case item.Status != "publish":
+		return rec, fmt.Errorf("%w: status %s", errSkipped, item.Status)
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/importer/model"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeAll reads the records and what went wrong with each, nil for the
+// good ones.
+func decodeAll(t *testing.T, format, input string) ([]model.Record, []error) {
+	dec, err := newDecoder(format, strings.NewReader(input))
+	require.NoError(t, err)
+
+	var (
+		records []model.Record
+		errs    []error
+	)
+	for {
+		rec, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			return records, errs
+		}
+
+		var recErr *recordError
+		if err != nil && !errors.Is(err, errSkipped) && !errors.As(err, &recErr) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records = append(records, rec)
+		errs = append(errs, err)
+	}
+}
+
+func TestCSVDecoder(t *testing.T) {
+	input := "\ufeffID,title,content,tags,created_at,extra\n" +
+		"a1,First,\"one,\nstill one\",\"city, sport\",2020-01-02T03:04:05Z,x\n" +
+		"a2,Short row\n" +
+		"a3,Third,three,,yesterday,x\n" +
+		"a4,Fourth,four,,,x\n"
+
+	records, errs := decodeAll(t, model.FormatCSV, input)
+	require.Len(t, records, 4)
+
+	require.NoError(t, errs[0])
+	require.Equal(t, model.Record{
+		Position:  2,
+		SourceID:  "a1",
+		Title:     "First",
+		Content:   "one,\nstill one",
+		Tags:      []string{"city", " sport"},
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, records[0])
+
+	require.Error(t, errs[1], "wrong number of fields")
+	require.Equal(t, 4, records[1].Position)
+
+	require.ErrorContains(t, errs[2], "created_at")
+	require.Equal(t, "a3", records[2].SourceID)
+
+	require.NoError(t, errs[3])
+	require.Equal(t, 6, records[3].Position)
+}
+
+func TestCSVDecoder_NoIDColumn(t *testing.T) {
+	dec, err := newDecoder(model.FormatCSV, strings.NewReader("title,content\nA,B\n"))
+	require.NoError(t, err)
+
+	_, err = dec.next()
+	require.ErrorIs(t, err, errNoHeader)
+}
+
+const wxrExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<wp:author>
+		<wp:author_login><![CDATA[jane]]></wp:author_login>
+		<wp:author_email><![CDATA[jane@example.com]]></wp:author_email>
+	</wp:author>
+	<item>
+		<title>Hello</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[<p>Hi</p>]]></content:encoded>
+		<wp:post_id>12</wp:post_id>
+		<wp:post_date_gmt><![CDATA[2024-05-01 10:00:00]]></wp:post_date_gmt>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="city"><![CDATA[City]]></category>
+		<category domain="post_tag" nicename="rain"><![CDATA[Rain]]></category>
+	</item>
+	<item>
+		<title>About</title>
+		<wp:post_id>13</wp:post_id>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[page]]></wp:post_type>
+	</item>
+	<item>
+		<title>Later</title>
+		<wp:post_id>14</wp:post_id>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestWXRDecoder(t *testing.T) {
+	records, errs := decodeAll(t, model.FormatWXR, wxrExport)
+	require.Len(t, records, 3)
+
+	require.NoError(t, errs[0])
+	require.Equal(t, model.Record{
+		Position:      1,
+		SourceID:      "12",
+		Title:         "Hello",
+		Content:       "<p>Hi</p>",
+		ContentFormat: "html",
+		Tags:          []string{"Rain", "City"},
+		Author:        "jane",
+		AuthorEmail:   "jane@example.com",
+		CreatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}, records[0])
+
+	require.ErrorIs(t, errs[1], errSkipped, "pages are not posts")
+	require.ErrorIs(t, errs[2], errSkipped, "drafts are not published")
+}
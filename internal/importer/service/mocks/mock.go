@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/post/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImportPostsService is a mock of ImportPostsService interface.
+type MockImportPostsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportPostsServiceMockRecorder
+}
+
+// MockImportPostsServiceMockRecorder is the mock recorder for MockImportPostsService.
+type MockImportPostsServiceMockRecorder struct {
+	mock *MockImportPostsService
+}
+
+// NewMockImportPostsService creates a new mock instance.
+func NewMockImportPostsService(ctrl *gomock.Controller) *MockImportPostsService {
+	mock := &MockImportPostsService{ctrl: ctrl}
+	mock.recorder = &MockImportPostsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportPostsService) EXPECT() *MockImportPostsServiceMockRecorder {
+	return m.recorder
+}
+
+// ImportPosts mocks base method.
+func (m *MockImportPostsService) ImportPosts(ctx context.Context, actorID int, source string, posts []model.ImportedPost) ([]model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPosts", ctx, actorID, source, posts)
+	ret0, _ := ret[0].([]model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportPosts indicates an expected call of ImportPosts.
+func (mr *MockImportPostsServiceMockRecorder) ImportPosts(ctx, actorID, source, posts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPosts", reflect.TypeOf((*MockImportPostsService)(nil).ImportPosts), ctx, actorID, source, posts)
+}
+
+// MockGetUserIDsByEmailsRepository is a mock of GetUserIDsByEmailsRepository interface.
+type MockGetUserIDsByEmailsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetUserIDsByEmailsRepositoryMockRecorder
+}
+
+// MockGetUserIDsByEmailsRepositoryMockRecorder is the mock recorder for MockGetUserIDsByEmailsRepository.
+type MockGetUserIDsByEmailsRepositoryMockRecorder struct {
+	mock *MockGetUserIDsByEmailsRepository
+}
+
+// NewMockGetUserIDsByEmailsRepository creates a new mock instance.
+func NewMockGetUserIDsByEmailsRepository(ctrl *gomock.Controller) *MockGetUserIDsByEmailsRepository {
+	mock := &MockGetUserIDsByEmailsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetUserIDsByEmailsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetUserIDsByEmailsRepository) EXPECT() *MockGetUserIDsByEmailsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUserIDsByEmails mocks base method.
+func (m *MockGetUserIDsByEmailsRepository) GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDsByEmails", ctx, emails)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDsByEmails indicates an expected call of GetUserIDsByEmails.
+func (mr *MockGetUserIDsByEmailsRepositoryMockRecorder) GetUserIDsByEmails(ctx, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDsByEmails", reflect.TypeOf((*MockGetUserIDsByEmailsRepository)(nil).GetUserIDsByEmails), ctx, emails)
+}
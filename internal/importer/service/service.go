@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/importer/model"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/wxr"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type ImportPostsService interface {
+	ImportPosts(ctx context.Context, actorID int, source string, posts []postModel.ImportedPost) ([]postModel.ImportResult, error)
+}
+
+type GetUserIDsByEmailsRepository interface {
+	GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]int, error)
+}
+
+type ImportService struct {
+	logger *slog.Logger
+
+	importPostsService           ImportPostsService
+	getUserIDsByEmailsRepository GetUserIDsByEmailsRepository
+}
+
+func NewImportService(
+	logger *slog.Logger,
+	importPostsService ImportPostsService,
+	getUserIDsByEmailsRepository GetUserIDsByEmailsRepository,
+) *ImportService {
+	return &ImportService{
+		logger:                       logger,
+		importPostsService:           importPostsService,
+		getUserIDsByEmailsRepository: getUserIDsByEmailsRepository,
+	}
+}
+
+// Import streams the export and stores its posts in batches, each in its own
+// transaction. Records that can not be imported are reported and the import
+// goes on. On an error the report covers the batches stored so far, those
+// stay stored and are found to be duplicates when the import is run again.
+func (s ImportService) Import(ctx context.Context, r io.Reader, opts model.Options) (model.Report, error) {
+	const op = "news-crud.internal.importer.import.service.Import"
+	logger := s.logger.With(slog.String("op", op), slog.String("source", opts.Source))
+
+	report := model.NewReport(opts.Source)
+
+	if err := opts.Validation(); err != nil {
+		return report, fmt.Errorf("%s: %w", op, err)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = model.DefaultBatchSize
+	}
+
+	dec, err := newDecoder(opts.Format, r)
+	if err != nil {
+		return report, fmt.Errorf("%s: %w", op, err)
+	}
+
+	batch := make([]model.Record, 0, opts.BatchSize)
+	for {
+		rec, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var recErr *recordError
+		switch {
+		case errors.Is(err, errSkipped):
+			report.Total++
+			report.Skipped++
+			continue
+		case errors.As(err, &recErr):
+			report.Total++
+			report.Fail(rec, err)
+			continue
+		case isMalformed(err):
+			return report, fmt.Errorf("%s: record %d: %w: %w", op, report.Total+1, model.ErrMalformed, err)
+		case err != nil:
+			return report, fmt.Errorf("%s: record %d: %w", op, report.Total+1, err)
+		}
+
+		report.Total++
+		if err = validateRecord(rec); err != nil {
+			report.Fail(rec, err)
+			continue
+		}
+
+		batch = append(batch, rec)
+		if len(batch) < opts.BatchSize {
+			continue
+		}
+
+		if err = s.importBatch(ctx, opts, batch, &report); err != nil {
+			return report, fmt.Errorf("%s: %w", op, err)
+		}
+		batch = batch[:0]
+
+		logger.Debug("batch imported", slog.Int("total", report.Total))
+	}
+
+	if err = s.importBatch(ctx, opts, batch, &report); err != nil {
+		return report, fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.Info("import done",
+		slog.Int("total", report.Total),
+		slog.Int("created", report.Created),
+		slog.Int("duplicates", report.Duplicates),
+		slog.Int("skipped", report.Skipped),
+		slog.Int("failed", report.Failed),
+	)
+
+	return report, nil
+}
+
+// isMalformed tells the errors of the export itself from those reading it.
+func isMalformed(err error) bool {
+	var syntaxErr *xml.SyntaxError
+	return errors.Is(err, wxr.ErrNotWXR) || errors.Is(err, errNoHeader) || errors.As(err, &syntaxErr)
+}
+
+func validateRecord(rec model.Record) error {
+	switch {
+	case rec.SourceID == "":
+		return postModel.ErrEmptySourceID
+	case strings.TrimSpace(rec.Title) == "":
+		return model.ErrEmptyTitle
+	case strings.TrimSpace(rec.Content) == "":
+		return model.ErrEmptyContent
+	}
+	return nil
+}
+
+// importBatch maps the authors of the records and stores them as posts.
+func (s ImportService) importBatch(ctx context.Context, opts model.Options, batch []model.Record, report *model.Report) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	emails := make([]string, 0, len(batch))
+	for _, rec := range batch {
+		if rec.AuthorEmail != "" {
+			emails = append(emails, rec.AuthorEmail)
+		}
+	}
+
+	usersByEmail, err := s.getUserIDsByEmailsRepository.GetUserIDsByEmails(ctx, emails)
+	if err != nil {
+		return err
+	}
+
+	records := make([]model.Record, 0, len(batch))
+	posts := make([]postModel.ImportedPost, 0, len(batch))
+	for _, rec := range batch {
+		authorID, err := authorID(rec, opts, usersByEmail)
+		if err != nil {
+			report.Fail(rec, err)
+			continue
+		}
+
+		records = append(records, rec)
+		posts = append(posts, postModel.ImportedPost{
+			SourceID: rec.SourceID,
+			Post: postModel.Post{
+				Title:         strings.TrimSpace(rec.Title),
+				Content:       rec.Content,
+				ContentFormat: rec.ContentFormat,
+				Excerpt:       strings.TrimSpace(rec.Excerpt),
+				AuthorID:      authorID,
+				Tags:          rec.Tags,
+				CreatedAt:     rec.CreatedAt,
+				UpdatedAt:     rec.UpdatedAt,
+			},
+		})
+	}
+
+	if len(posts) == 0 {
+		return nil
+	}
+
+	results, err := s.importPostsService.ImportPosts(ctx, opts.ActorID, opts.Source, posts)
+	if err != nil {
+		return err
+	}
+
+	for i, res := range results {
+		switch {
+		case res.Err != nil:
+			report.Fail(records[i], res.Err)
+		case res.Duplicate:
+			report.Duplicates++
+		default:
+			report.Created++
+		}
+	}
+
+	return nil
+}
+
+// authorID maps the author of the record through the options first, then to
+// the user with the same email, then to the default author.
+func authorID(rec model.Record, opts model.Options, usersByEmail map[string]int) (int, error) {
+	for _, name := range []string{rec.Author, rec.AuthorEmail} {
+		if id, ok := opts.Authors[name]; ok && name != "" {
+			return id, nil
+		}
+	}
+
+	if id, ok := usersByEmail[strings.ToLower(rec.AuthorEmail)]; ok {
+		return id, nil
+	}
+
+	if opts.DefaultAuthorID > 0 {
+		return opts.DefaultAuthorID, nil
+	}
+
+	return 0, fmt.Errorf("%w: %q", model.ErrUnknownAuthor, rec.Author)
+}
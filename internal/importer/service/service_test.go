@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/importer/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/importer/service/mocks"
+	postModel "github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+const export = `{"id": 1, "title": "First", "content": "one", "author": "jane", "created_at": "2020-01-02T03:04:05Z"}
+{"id": "2", "title": "Second", "content": "two", "author_email": "Bob@Example.com"}
+
+not json
+{"id": 4, "title": "", "content": "four", "author": "jane"}
+{"id": 5, "title": "Fifth", "content": "five", "author": "nobody"}
+{"id": 6, "title": "Sixth", "content": "six", "author": "jane"}
+`
+
+func TestImportService_Import(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	users := mock_service.NewMockGetUserIDsByEmailsRepository(c)
+	users.EXPECT().GetUserIDsByEmails(gomock.Any(), []string{"Bob@Example.com"}).
+		Return(map[string]int{"bob@example.com": 8}, nil)
+	users.EXPECT().GetUserIDsByEmails(gomock.Any(), []string{}).Return(map[string]int{}, nil)
+
+	posts := mock_service.NewMockImportPostsService(c)
+	gomock.InOrder(
+		posts.EXPECT().ImportPosts(gomock.Any(), 1, "archive", []postModel.ImportedPost{
+			{SourceID: "1", Post: postModel.Post{
+				Title:     "First",
+				Content:   "one",
+				AuthorID:  7,
+				CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			}},
+			{SourceID: "2", Post: postModel.Post{Title: "Second", Content: "two", AuthorID: 8}},
+		}).Return([]postModel.ImportResult{{PostID: 10}, {PostID: 3, Duplicate: true}}, nil),
+		posts.EXPECT().ImportPosts(gomock.Any(), 1, "archive", []postModel.ImportedPost{
+			{SourceID: "6", Post: postModel.Post{Title: "Sixth", Content: "six", AuthorID: 7}},
+		}).Return([]postModel.ImportResult{{Err: postModel.ErrTooManyTags}}, nil),
+	)
+
+	s := NewImportService(slogdiscard.NewDiscardLogger(), posts, users)
+
+	report, err := s.Import(context.Background(), strings.NewReader(export), model.Options{
+		Format:    model.FormatNDJSON,
+		Source:    "archive",
+		Authors:   map[string]int{"jane": 7},
+		ActorID:   1,
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 6, report.Total)
+	require.Equal(t, 1, report.Created)
+	require.Equal(t, 1, report.Duplicates)
+	require.Equal(t, 4, report.Failed)
+
+	positions := make([]int, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		positions = append(positions, e.Position)
+	}
+	require.Equal(t, []int{4, 5, 6, 7}, positions)
+	require.Contains(t, report.Errors[2].Error, "author is not known")
+	require.Equal(t, "6", report.Errors[3].SourceID)
+}
+
+func TestImportService_Import_BatchFails(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	users := mock_service.NewMockGetUserIDsByEmailsRepository(c)
+	users.EXPECT().GetUserIDsByEmails(gomock.Any(), gomock.Any()).Return(map[string]int{}, nil).Times(2)
+
+	dbErr := errors.New("db is down")
+	posts := mock_service.NewMockImportPostsService(c)
+	gomock.InOrder(
+		posts.EXPECT().ImportPosts(gomock.Any(), 0, "archive", gomock.Len(1)).
+			Return([]postModel.ImportResult{{PostID: 10}}, nil),
+		posts.EXPECT().ImportPosts(gomock.Any(), 0, "archive", gomock.Len(1)).Return(nil, dbErr),
+	)
+
+	s := NewImportService(slogdiscard.NewDiscardLogger(), posts, users)
+
+	report, err := s.Import(context.Background(), strings.NewReader(export), model.Options{
+		Format:          model.FormatNDJSON,
+		Source:          "archive",
+		DefaultAuthorID: 7,
+		BatchSize:       1,
+	})
+	require.ErrorIs(t, err, dbErr)
+	require.Equal(t, 1, report.Created, "the stored batches are reported")
+}
+
+func TestImportService_Import_BadOptions(t *testing.T) {
+	s := NewImportService(slogdiscard.NewDiscardLogger(), nil, nil)
+
+	_, err := s.Import(context.Background(), strings.NewReader(""), model.Options{Format: "xls", Source: "a"})
+	require.ErrorIs(t, err, model.ErrUnknownFormat)
+
+	_, err = s.Import(context.Background(), strings.NewReader(""), model.Options{Format: model.FormatCSV})
+	require.ErrorIs(t, err, model.ErrEmptySource)
+}
+
+func TestImportService_Import_Malformed(t *testing.T) {
+	s := NewImportService(slogdiscard.NewDiscardLogger(), nil, nil)
+
+	_, err := s.Import(context.Background(), strings.NewReader(`{"id": 1}`), model.Options{
+		Format: model.FormatWXR,
+		Source: model.SourceWordPress,
+	})
+	require.ErrorIs(t, err, model.ErrMalformed)
+}
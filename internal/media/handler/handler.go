@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	"github.com/ananaslegend/news-crud/internal/media/service"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// multipartMemory is the part of an upload kept in memory, the rest is
+// spooled to a temporary file.
+const multipartMemory = 8 << 20
+
+// multipartOverhead allows for the multipart framing around the file.
+const multipartOverhead = 64 << 10
+
+type UploadMediaService interface {
+	Upload(ctx context.Context, ownerID int, filename string, file io.ReadSeeker, size int64) (model.Media, error)
+}
+
+type AttachMediaService interface {
+	AttachMedia(ctx context.Context, userID, postID int, mediaIDs []int) error
+}
+
+type OpenMediaService interface {
+	Open(ctx context.Context, key string) (model.Media, io.ReadCloser, error)
+}
+
+type MediaHandler struct {
+	logger  *slog.Logger
+	maxSize int64
+
+	uploadMediaService UploadMediaService
+	attachMediaService AttachMediaService
+	openMediaService   OpenMediaService
+}
+
+func NewMediaHandler(
+	logger *slog.Logger,
+	maxSize int64,
+	uploadMediaService UploadMediaService,
+	attachMediaService AttachMediaService,
+	openMediaService OpenMediaService,
+) *MediaHandler {
+	return &MediaHandler{
+		logger:             logger,
+		maxSize:            maxSize,
+		uploadMediaService: uploadMediaService,
+		attachMediaService: attachMediaService,
+		openMediaService:   openMediaService,
+	}
+}
+
+// Upload takes the file from the "file" field of a multipart form.
+func (h MediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.media.upload.handler.Upload"
+	logger := h.logger.With(slog.String("op", op))
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+multipartOverhead)
+
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	media, err := h.uploadMediaService.Upload(r.Context(), userID, header.Filename, file, header.Size)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTooLarge):
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		case errors.Is(err, service.ErrUnsupportedType):
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		default:
+			logger.Error("cant upload media", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", media.URL)
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(media); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+type AttachMediaRequest struct {
+	MediaIDs []int `json:"media_ids" validate:"max=20,dive,gt=0"`
+}
+
+func (h MediaHandler) AttachMedia(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.media.attach.handler.AttachMedia"
+	logger := h.logger.With(slog.String("op", op))
+
+	postID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || postID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var req AttachMediaRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("cant decode request", logs.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err = validator.New().Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	err = h.attachMediaService.AttachMedia(r.Context(), userID, postID, req.MediaIDs)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, service.ErrTooManyAttachments):
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+	case errors.Is(err, service.ErrNoPostWasFound), errors.Is(err, service.ErrNoMediaWasFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrUserHasNoPermission):
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		logger.Error("cant attach media", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// Serve streams the media. Keys are random and never reused, so the content
+// can be cached forever.
+func (h MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.media.serve.handler.Serve"
+	logger := h.logger.With(slog.String("op", op))
+
+	key := r.PathValue("key")
+
+	media, content, err := h.openMediaService.Open(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, service.ErrNoMediaWasFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		logger.Error("cant open media", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	// the media is looked up first, a deleted one is not answered 304
+	etag := `"` + key + `"`
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", media.CreatedAt.UTC().Format(http.TimeFormat))
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", media.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(media.Size, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if _, err = io.Copy(w, content); err != nil {
+		logger.Error("cant write media", logs.Err(err))
+	}
+}
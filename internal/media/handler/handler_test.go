@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	"github.com/ananaslegend/news-crud/internal/media/service"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// media serves a single stored image.
+type media struct{}
+
+func (media) Open(_ context.Context, key string) (model.Media, io.ReadCloser, error) {
+	if key != "ab/abc.png" {
+		return model.Media{}, nil, service.ErrNoMediaWasFound
+	}
+
+	return model.Media{
+		Key:         key,
+		ContentType: "image/png",
+		Size:        5,
+		CreatedAt:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}, io.NopCloser(strings.NewReader("image")), nil
+}
+
+func TestMediaHandler_Serve(t *testing.T) {
+	h := NewMediaHandler(slogdiscard.NewDiscardLogger(), 1<<20, nil, nil, media{})
+
+	tests := []struct {
+		name        string
+		key         string
+		ifNoneMatch string
+		wantCode    int
+		wantBody    string
+	}{
+		{name: "Media", key: "ab/abc.png", wantCode: http.StatusOK, wantBody: "image"},
+		{name: "Matching ETag", key: "ab/abc.png", ifNoneMatch: `"ab/abc.png"`, wantCode: http.StatusNotModified},
+		{name: "Stale ETag", key: "ab/abc.png", ifNoneMatch: `"ab/old.png"`, wantCode: http.StatusOK, wantBody: "image"},
+		{name: "Missing media", key: "ab/gone.png", wantCode: http.StatusNotFound},
+		{name: "Missing media with its ETag", key: "ab/gone.png", ifNoneMatch: `"ab/gone.png"`, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/media/"+tt.key, nil)
+			r.SetPathValue("key", tt.key)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			w := httptest.NewRecorder()
+			h.Serve(w, r)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			require.Equal(t, tt.wantBody, w.Body.String())
+			if tt.wantCode != http.StatusNotFound {
+				require.Equal(t, `"ab/abc.png"`, w.Header().Get("ETag"))
+				require.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// PathPrefix is where media is served from.
+const PathPrefix = "/media/"
+
+// MaxAttachments is the number of media a post can have.
+const MaxAttachments = 20
+
+// Images go through processing after the upload, other media is ready right away.
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+)
+
+// Media is an uploaded file, Key addresses it in the storage. Images have
+// their dimensions, a blurhash placeholder and variants once they are processed.
+type Media struct {
+	ID          int       `json:"id"`
+	OwnerID     int       `json:"owner_id"`
+	Key         string    `json:"key"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Filename    string    `json:"filename"`
+	CreatedAt   time.Time `json:"created_at"`
+	Width       int       `json:"width,omitempty"`
+	Height      int       `json:"height,omitempty"`
+	Blurhash    string    `json:"blurhash,omitempty"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"-"`
+	Variants    []Variant `json:"variants,omitempty"`
+}
+
+// Variant is a rendition of an image in one format.
+type Variant struct {
+	Name        string `json:"name"`
+	Key         string `json:"-"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+}
+
+// ProcessingConfig sets the renditions generated for uploaded images, as
+// "name:WIDTHxHEIGHT[:crop]", and the formats each of them is encoded in.
+// JPEGQuality applies to JPEG renditions, WebP ones are lossless.
+type ProcessingConfig struct {
+	Renditions   []string
+	Formats      []string
+	JPEGQuality  int
+	MaxPixels    int
+	Workers      int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxAttempts  int
+}
+
+func URL(key string) string {
+	return PathPrefix + key
+}
+
+// VariantKey is the key of a variant, next to the original image.
+func VariantKey(key, name, extension string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "/" + name + extension
+}
+
+// Extension is the file extension used for keys of the content type.
+func Extension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "video/mp4":
+		return ".mp4"
+	case "application/pdf":
+		return ".pdf"
+	}
+
+	if _, sub, ok := strings.Cut(contentType, "/"); ok && sub != "" {
+		return "." + sub
+	}
+	return ""
+}
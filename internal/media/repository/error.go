@@ -0,0 +1,8 @@
+package repository
+
+import "errors"
+
+var (
+	ErrNoMediaWasFound = errors.New("media not found")
+	ErrNoPostWasFound  = errors.New("post not found")
+)
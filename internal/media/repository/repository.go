@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	"github.com/lib/pq"
+	"time"
+)
+
+const foreignKeyViolation = "23503"
+
+const mediaColumns = `
+		id, owner_id, storage_key, content_type, size, filename, created_at, width, height, blurhash, status, attempts`
+
+const selectMedia = `
+		select` + mediaColumns + `
+		from media`
+
+type MediaRepository struct {
+	db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+func (mr MediaRepository) CreateMedia(ctx context.Context, media model.Media) (int, error) {
+	const op = "news-crud.internal.media.create.repository.CreateMedia"
+
+	var mediaID int
+	err := mr.db.QueryRowContext(ctx, `
+		insert into
+		    media (owner_id, storage_key, content_type, size, filename, created_at, width, height, status)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		returning id
+	`, media.OwnerID, media.Key, media.ContentType, media.Size, media.Filename, media.CreatedAt,
+		media.Width, media.Height, media.Status,
+	).Scan(&mediaID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mediaID, nil
+}
+
+// GetMediaByKey returns the media stored under the key. For the key of a
+// variant it is the media the variant belongs to, with the content type, size
+// and dimensions of the variant.
+func (mr MediaRepository) GetMediaByKey(ctx context.Context, key string) (model.Media, error) {
+	const op = "news-crud.internal.media.get_by_key.repository.GetMediaByKey"
+
+	media, err := scanMedia(mr.db.QueryRowContext(ctx, selectMedia+`
+		where storage_key = $1
+		union all
+		select m.id, m.owner_id, v.storage_key, v.content_type, v.size, m.filename, m.created_at, v.width, v.height,
+			m.blurhash, m.status, m.attempts
+		from media_variants v
+		join media m on m.id = v.media_id
+		where v.storage_key = $1
+		limit 1
+	`, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Media{}, ErrNoMediaWasFound
+		}
+
+		return model.Media{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return media, nil
+}
+
+func (mr MediaRepository) GetMediaByIDs(ctx context.Context, ids []int) ([]model.Media, error) {
+	const op = "news-crud.internal.media.get_by_ids.repository.GetMediaByIDs"
+
+	rows, err := mr.db.QueryContext(ctx, selectMedia+`
+		where id = any($1)
+		order by id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	media := make([]model.Media, 0, len(ids))
+
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		media = append(media, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return media, nil
+}
+
+// AttachMedia replaces the media of the post, in the given order.
+func (mr MediaRepository) AttachMedia(ctx context.Context, postID int, mediaIDs []int) error {
+	const op = "news-crud.internal.media.attach.repository.AttachMedia"
+
+	tx, err := mr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `
+		delete from post_media
+		where post_id = $1
+	`, postID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, mediaID := range mediaIDs {
+		if _, err = tx.ExecContext(ctx, `
+			insert into
+			    post_media (post_id, media_id, position)
+			values ($1, $2, $3)
+		`, postID, mediaID, i); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+				return ErrNoPostWasFound
+			}
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ClaimMedia takes up to limit images for processing, those waiting and those
+// whose claim is older than the lease, as a worker processing them died.
+// Claimed media is skipped by other instances.
+func (mr MediaRepository) ClaimMedia(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.Media, error) {
+	const op = "news-crud.internal.media.process.repository.ClaimMedia"
+
+	rows, err := mr.db.QueryContext(ctx, `
+		update media
+		set status = $1, claimed_at = $2, attempts = attempts + 1
+		where id in (
+			select id
+			from media
+			where status = $3 or (status = $1 and claimed_at < $4)
+			order by id
+			limit $5
+			for update skip locked
+		)
+		returning`+mediaColumns,
+		model.StatusProcessing, now, model.StatusPending, now.Add(-lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	media := make([]model.Media, 0, limit)
+
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		media = append(media, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return media, nil
+}
+
+// CompleteMedia stores the result of processing, the variants replace any
+// left by an earlier attempt.
+func (mr MediaRepository) CompleteMedia(ctx context.Context, media model.Media) error {
+	const op = "news-crud.internal.media.process.repository.CompleteMedia"
+
+	tx, err := mr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `
+		delete from media_variants
+		where media_id = $1
+	`, media.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, v := range media.Variants {
+		if _, err = tx.ExecContext(ctx, `
+			insert into
+			    media_variants (media_id, name, storage_key, content_type, width, height, size)
+			values ($1, $2, $3, $4, $5, $6, $7)
+		`, media.ID, v.Name, v.Key, v.ContentType, v.Width, v.Height, v.Size); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		update media
+		set width = $2, height = $3, blurhash = $4, status = $5, claimed_at = null
+		where id = $1
+	`, media.ID, media.Width, media.Height, media.Blurhash, model.StatusReady); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FailMedia releases the claim, the status is pending to try again or failed.
+func (mr MediaRepository) FailMedia(ctx context.Context, mediaID int, status string) error {
+	const op = "news-crud.internal.media.process.repository.FailMedia"
+
+	if _, err := mr.db.ExecContext(ctx, `
+		update media
+		set status = $2, claimed_at = null
+		where id = $1
+	`, mediaID, status); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMedia(row rowScanner) (model.Media, error) {
+	var media model.Media
+
+	err := row.Scan(&media.ID, &media.OwnerID, &media.Key, &media.ContentType, &media.Size, &media.Filename,
+		&media.CreatedAt, &media.Width, &media.Height, &media.Blurhash, &media.Status, &media.Attempts)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	media.URL = model.URL(media.Key)
+
+	return media, nil
+}
@@ -0,0 +1,12 @@
+package service
+
+import "errors"
+
+var (
+	ErrTooLarge            = errors.New("file is too large")
+	ErrUnsupportedType     = errors.New("file type is not allowed")
+	ErrNoMediaWasFound     = errors.New("media not found")
+	ErrNoPostWasFound      = errors.New("post not found")
+	ErrUserHasNoPermission = errors.New("user has no permission")
+	ErrTooManyAttachments  = errors.New("post can have at most 20 media")
+)
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	"github.com/ananaslegend/news-crud/pkg/imaging"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/storage"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=image.go -destination=mocks/image_mock.go
+
+// Blurhash components, 4x3 suits the landscape images articles mostly have.
+const (
+	blurhashX = 4
+	blurhashY = 3
+)
+
+type ClaimMediaRepository interface {
+	ClaimMedia(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.Media, error)
+}
+
+type CompleteMediaRepository interface {
+	CompleteMedia(ctx context.Context, media model.Media) error
+}
+
+type FailMediaRepository interface {
+	FailMedia(ctx context.Context, mediaID int, status string) error
+}
+
+// ImageService generates the renditions of uploaded images in the background.
+// Images waiting for processing are kept in the database, so the work survives
+// restarts and is shared by all instances.
+type ImageService struct {
+	logger     *slog.Logger
+	config     model.ProcessingConfig
+	renditions []imaging.Rendition
+	formats    []string
+	storage    storage.Storage
+
+	claimMediaRepository    ClaimMediaRepository
+	completeMediaRepository CompleteMediaRepository
+	failMediaRepository     FailMediaRepository
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+func NewImageService(
+	logger *slog.Logger,
+	config model.ProcessingConfig,
+	storage storage.Storage,
+	claimMediaRepository ClaimMediaRepository,
+	completeMediaRepository CompleteMediaRepository,
+	failMediaRepository FailMediaRepository,
+) (*ImageService, error) {
+	const op = "news-crud.internal.media.process.service.NewImageService"
+
+	renditions := make([]imaging.Rendition, 0, len(config.Renditions))
+	for _, s := range config.Renditions {
+		r, err := imaging.ParseRendition(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		renditions = append(renditions, r)
+	}
+
+	formats := make([]string, 0, len(config.Formats))
+	for _, s := range config.Formats {
+		format, err := imaging.ParseFormat(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		formats = append(formats, format)
+	}
+
+	if slices.Contains(formats, imaging.FormatJPEG) && (config.JPEGQuality < 1 || config.JPEGQuality > 100) {
+		return nil, fmt.Errorf("%s: %w: %d", op, imaging.ErrInvalidQuality, config.JPEGQuality)
+	}
+
+	return &ImageService{
+		logger:                  logger,
+		config:                  config,
+		renditions:              renditions,
+		formats:                 formats,
+		storage:                 storage,
+		claimMediaRepository:    claimMediaRepository,
+		completeMediaRepository: completeMediaRepository,
+		failMediaRepository:     failMediaRepository,
+		wake:                    make(chan struct{}, 1),
+		now:                     time.Now,
+	}, nil
+}
+
+// Notify wakes a worker up, there is a new image to process.
+func (is *ImageService) Notify() {
+	select {
+	case is.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes images with the configured number of workers until the
+// context is done. Workers look for images when notified and every poll
+// interval, to pick up images of other instances and failed attempts.
+func (is *ImageService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < max(is.config.Workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			is.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (is *ImageService) work(ctx context.Context) {
+	ticker := time.NewTicker(is.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && is.processNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-is.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext processes one waiting image, it reports whether there was one.
+func (is *ImageService) processNext(ctx context.Context) bool {
+	const op = "news-crud.internal.media.process.service.processNext"
+	logger := is.logger.With(slog.String("op", op))
+
+	claimed, err := is.claimMediaRepository.ClaimMedia(ctx, is.now(), is.config.Lease, 1)
+	if err != nil {
+		logger.Error("cant claim media", logs.Err(err))
+		return false
+	}
+	if len(claimed) == 0 {
+		return false
+	}
+
+	media := claimed[0]
+	logger = logger.With(slog.Int("media_id", media.ID))
+
+	err = is.Process(ctx, &media)
+	if err == nil {
+		if err = is.completeMediaRepository.CompleteMedia(ctx, media); err == nil {
+			return true
+		}
+	}
+
+	status := model.StatusPending
+	if errors.Is(err, imaging.ErrMalformed) || media.Attempts >= is.config.MaxAttempts {
+		status = model.StatusFailed
+	}
+	logger.Error("cant process image", slog.String("status", status), logs.Err(err))
+
+	if err = is.failMediaRepository.FailMedia(context.WithoutCancel(ctx), media.ID, status); err != nil {
+		logger.Error("cant release media", logs.Err(err))
+	}
+
+	return true
+}
+
+// Process decodes the image, stores its variants and records them together
+// with the dimensions and the blurhash of the image.
+func (is *ImageService) Process(ctx context.Context, media *model.Media) error {
+	const op = "news-crud.internal.media.process.service.Process"
+
+	content, err := is.storage.Get(ctx, media.Key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	data, err := io.ReadAll(content)
+	content.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	config, err := imaging.DecodeConfig(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if config.Width*config.Height > is.config.MaxPixels {
+		return fmt.Errorf("%s: %w: %dx%d pixels", op, imaging.ErrMalformed, config.Width, config.Height)
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	media.Width, media.Height = img.Rect.Dx(), img.Rect.Dy()
+	media.Blurhash = imaging.Blurhash(img, blurhashX, blurhashY)
+	media.Variants = make([]model.Variant, 0, len(is.renditions)*len(is.formats))
+
+	var buf bytes.Buffer
+	for _, r := range is.renditions {
+		resized := imaging.Resize(img, r)
+
+		for _, format := range is.formats {
+			buf.Reset()
+			if err = imaging.Encode(&buf, resized, format, is.config.JPEGQuality); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			variant := model.Variant{
+				Name:        r.Name,
+				Key:         model.VariantKey(media.Key, r.Name, imaging.Extension(format)),
+				ContentType: imaging.ContentType(format),
+				Width:       resized.Rect.Dx(),
+				Height:      resized.Rect.Dy(),
+				Size:        int64(buf.Len()),
+			}
+			variant.URL = model.URL(variant.Key)
+
+			// keys are derived from the original, another attempt overwrites them
+			if err = is.storage.Put(ctx, variant.Key, &buf, variant.Size, variant.ContentType); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			media.Variants = append(media.Variants, variant)
+		}
+	}
+
+	return nil
+}
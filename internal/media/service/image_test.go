@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/media/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/imaging"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/storage"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"image"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+var testProcessingConfig = model.ProcessingConfig{
+	Renditions:   []string{"thumb:100x100:crop", "small:200x0"},
+	Formats:      []string{"webp", "jpeg"},
+	JPEGQuality:  80,
+	MaxPixels:    1_000_000,
+	Workers:      1,
+	PollInterval: time.Minute,
+	Lease:        time.Minute,
+	MaxAttempts:  3,
+}
+
+func putJPEG(t *testing.T, s storage.Storage, key string, w, h int) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)), nil))
+	require.NoError(t, s.Put(context.Background(), key, &buf, int64(buf.Len()), "image/jpeg"))
+}
+
+func TestNewImageService_InvalidConfig(t *testing.T) {
+	config := testProcessingConfig
+	config.Renditions = []string{"thumb:100"}
+
+	_, err := NewImageService(slogdiscard.NewDiscardLogger(), config, nil, nil, nil, nil)
+	require.ErrorIs(t, err, imaging.ErrInvalidRendition)
+
+	config = testProcessingConfig
+	config.Formats = []string{"avif"}
+
+	_, err = NewImageService(slogdiscard.NewDiscardLogger(), config, nil, nil, nil, nil)
+	require.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+
+	config = testProcessingConfig
+	config.JPEGQuality = 0
+
+	_, err = NewImageService(slogdiscard.NewDiscardLogger(), config, nil, nil, nil, nil)
+	require.ErrorIs(t, err, imaging.ErrInvalidQuality)
+
+	config.Formats = []string{"webp"}
+
+	_, err = NewImageService(slogdiscard.NewDiscardLogger(), config, nil, nil, nil, nil)
+	require.NoError(t, err, "WebP is lossless, the quality is not used")
+}
+
+func TestImageService_Process(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	putJPEG(t, local, "ab/abc.jpg", 400, 300)
+
+	s, err := NewImageService(slogdiscard.NewDiscardLogger(), testProcessingConfig, local, nil, nil, nil)
+	require.NoError(t, err)
+
+	media := model.Media{ID: 1, Key: "ab/abc.jpg", ContentType: "image/jpeg"}
+	require.NoError(t, s.Process(context.Background(), &media))
+
+	require.Equal(t, 400, media.Width)
+	require.Equal(t, 300, media.Height)
+	require.NotEmpty(t, media.Blurhash)
+	require.Len(t, media.Variants, 4)
+
+	want := []struct {
+		key           string
+		contentType   string
+		width, height int
+	}{
+		{"ab/abc/thumb.webp", "image/webp", 100, 100},
+		{"ab/abc/thumb.jpg", "image/jpeg", 100, 100},
+		{"ab/abc/small.webp", "image/webp", 200, 150},
+		{"ab/abc/small.jpg", "image/jpeg", 200, 150},
+	}
+
+	for i, w := range want {
+		v := media.Variants[i]
+		require.Equal(t, w.key, v.Key)
+		require.Equal(t, model.URL(w.key), v.URL)
+		require.Equal(t, w.contentType, v.ContentType)
+		require.Equal(t, w.width, v.Width)
+		require.Equal(t, w.height, v.Height)
+
+		r, err := local.Get(context.Background(), v.Key)
+		require.NoError(t, err)
+		config, _, err := image.DecodeConfig(r)
+		r.Close()
+		require.NoError(t, err)
+		require.Equal(t, w.width, config.Width)
+	}
+}
+
+func TestImageService_processNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int
+		original   bool
+		corrupt    bool
+		wantStatus string
+	}{
+		{name: "completes", attempts: 1, original: true},
+		{name: "retries", attempts: 1, wantStatus: model.StatusPending},
+		{name: "gives up", attempts: 3, wantStatus: model.StatusFailed},
+		{name: "not an image", attempts: 1, corrupt: true, wantStatus: model.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			local, err := storage.NewLocal(t.TempDir())
+			require.NoError(t, err)
+
+			if tt.original {
+				putJPEG(t, local, "ab/abc.jpg", 40, 30)
+			}
+			if tt.corrupt {
+				junk := []byte("not an image")
+				require.NoError(t, local.Put(context.Background(), "ab/abc.jpg", bytes.NewReader(junk), int64(len(junk)), "image/jpeg"))
+			}
+
+			claim := mock_service.NewMockClaimMediaRepository(ctrl)
+			complete := mock_service.NewMockCompleteMediaRepository(ctrl)
+			fail := mock_service.NewMockFailMediaRepository(ctrl)
+
+			s, err := NewImageService(slogdiscard.NewDiscardLogger(), testProcessingConfig, local, claim, complete, fail)
+			require.NoError(t, err)
+
+			now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+			s.now = func() time.Time { return now }
+
+			claim.EXPECT().ClaimMedia(gomock.Any(), now, time.Minute, 1).
+				Return([]model.Media{{ID: 5, Key: "ab/abc.jpg", Attempts: tt.attempts}}, nil)
+
+			if tt.wantStatus == "" {
+				complete.EXPECT().CompleteMedia(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, media model.Media) error {
+						require.Equal(t, 5, media.ID)
+						require.Equal(t, 40, media.Width)
+						require.Len(t, media.Variants, 4)
+						return nil
+					})
+			} else {
+				fail.EXPECT().FailMedia(gomock.Any(), 5, tt.wantStatus).Return(nil)
+			}
+
+			require.True(t, s.processNext(context.Background()))
+		})
+	}
+}
+
+func TestImageService_processNext_Nothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	claim := mock_service.NewMockClaimMediaRepository(ctrl)
+
+	s, err := NewImageService(slogdiscard.NewDiscardLogger(), testProcessingConfig, nil, claim, nil, nil)
+	require.NoError(t, err)
+
+	claim.EXPECT().ClaimMedia(gomock.Any(), gomock.Any(), gomock.Any(), 1).Return(nil, nil)
+
+	require.False(t, s.processNext(context.Background()))
+}
+
+func TestImageService_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	claim := mock_service.NewMockClaimMediaRepository(ctrl)
+
+	s, err := NewImageService(slogdiscard.NewDiscardLogger(), testProcessingConfig, nil, claim, nil, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// one look on start, one when notified
+	calls := make(chan struct{}, 2)
+	claim.EXPECT().ClaimMedia(gomock.Any(), gomock.Any(), gomock.Any(), 1).
+		DoAndReturn(func(context.Context, time.Time, time.Duration, int) ([]model.Media, error) {
+			calls <- struct{}{}
+			return nil, nil
+		}).Times(2)
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	<-calls
+	s.Notify()
+	<-calls
+
+	cancel()
+	<-done
+}
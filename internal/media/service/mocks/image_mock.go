@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: image.go
+//
+// Generated by this command:
+//
+//	mockgen -source=image.go -destination=mocks/image_mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ananaslegend/news-crud/internal/media/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClaimMediaRepository is a mock of ClaimMediaRepository interface.
+type MockClaimMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClaimMediaRepositoryMockRecorder
+}
+
+// MockClaimMediaRepositoryMockRecorder is the mock recorder for MockClaimMediaRepository.
+type MockClaimMediaRepositoryMockRecorder struct {
+	mock *MockClaimMediaRepository
+}
+
+// NewMockClaimMediaRepository creates a new mock instance.
+func NewMockClaimMediaRepository(ctrl *gomock.Controller) *MockClaimMediaRepository {
+	mock := &MockClaimMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockClaimMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClaimMediaRepository) EXPECT() *MockClaimMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimMedia mocks base method.
+func (m *MockClaimMediaRepository) ClaimMedia(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMedia", ctx, now, lease, limit)
+	ret0, _ := ret[0].([]model.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMedia indicates an expected call of ClaimMedia.
+func (mr *MockClaimMediaRepositoryMockRecorder) ClaimMedia(ctx, now, lease, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMedia", reflect.TypeOf((*MockClaimMediaRepository)(nil).ClaimMedia), ctx, now, lease, limit)
+}
+
+// MockCompleteMediaRepository is a mock of CompleteMediaRepository interface.
+type MockCompleteMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompleteMediaRepositoryMockRecorder
+}
+
+// MockCompleteMediaRepositoryMockRecorder is the mock recorder for MockCompleteMediaRepository.
+type MockCompleteMediaRepositoryMockRecorder struct {
+	mock *MockCompleteMediaRepository
+}
+
+// NewMockCompleteMediaRepository creates a new mock instance.
+func NewMockCompleteMediaRepository(ctrl *gomock.Controller) *MockCompleteMediaRepository {
+	mock := &MockCompleteMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockCompleteMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompleteMediaRepository) EXPECT() *MockCompleteMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteMedia mocks base method.
+func (m *MockCompleteMediaRepository) CompleteMedia(ctx context.Context, media model.Media) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMedia", ctx, media)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteMedia indicates an expected call of CompleteMedia.
+func (mr *MockCompleteMediaRepositoryMockRecorder) CompleteMedia(ctx, media any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMedia", reflect.TypeOf((*MockCompleteMediaRepository)(nil).CompleteMedia), ctx, media)
+}
+
+// MockFailMediaRepository is a mock of FailMediaRepository interface.
+type MockFailMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFailMediaRepositoryMockRecorder
+}
+
+// MockFailMediaRepositoryMockRecorder is the mock recorder for MockFailMediaRepository.
+type MockFailMediaRepositoryMockRecorder struct {
+	mock *MockFailMediaRepository
+}
+
+// NewMockFailMediaRepository creates a new mock instance.
+func NewMockFailMediaRepository(ctrl *gomock.Controller) *MockFailMediaRepository {
+	mock := &MockFailMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockFailMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFailMediaRepository) EXPECT() *MockFailMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FailMedia mocks base method.
+func (m *MockFailMediaRepository) FailMedia(ctx context.Context, mediaID int, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailMedia", ctx, mediaID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailMedia indicates an expected call of FailMedia.
+func (mr *MockFailMediaRepositoryMockRecorder) FailMedia(ctx, mediaID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailMedia", reflect.TypeOf((*MockFailMediaRepository)(nil).FailMedia), ctx, mediaID, status)
+}
@@ -0,0 +1,241 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/media/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCreateMediaRepository is a mock of CreateMediaRepository interface.
+type MockCreateMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateMediaRepositoryMockRecorder
+}
+
+// MockCreateMediaRepositoryMockRecorder is the mock recorder for MockCreateMediaRepository.
+type MockCreateMediaRepositoryMockRecorder struct {
+	mock *MockCreateMediaRepository
+}
+
+// NewMockCreateMediaRepository creates a new mock instance.
+func NewMockCreateMediaRepository(ctrl *gomock.Controller) *MockCreateMediaRepository {
+	mock := &MockCreateMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockCreateMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateMediaRepository) EXPECT() *MockCreateMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateMedia mocks base method.
+func (m *MockCreateMediaRepository) CreateMedia(ctx context.Context, media model.Media) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMedia", ctx, media)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMedia indicates an expected call of CreateMedia.
+func (mr *MockCreateMediaRepositoryMockRecorder) CreateMedia(ctx, media any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMedia", reflect.TypeOf((*MockCreateMediaRepository)(nil).CreateMedia), ctx, media)
+}
+
+// MockGetMediaByKeyRepository is a mock of GetMediaByKeyRepository interface.
+type MockGetMediaByKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetMediaByKeyRepositoryMockRecorder
+}
+
+// MockGetMediaByKeyRepositoryMockRecorder is the mock recorder for MockGetMediaByKeyRepository.
+type MockGetMediaByKeyRepositoryMockRecorder struct {
+	mock *MockGetMediaByKeyRepository
+}
+
+// NewMockGetMediaByKeyRepository creates a new mock instance.
+func NewMockGetMediaByKeyRepository(ctrl *gomock.Controller) *MockGetMediaByKeyRepository {
+	mock := &MockGetMediaByKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockGetMediaByKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetMediaByKeyRepository) EXPECT() *MockGetMediaByKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetMediaByKey mocks base method.
+func (m *MockGetMediaByKeyRepository) GetMediaByKey(ctx context.Context, key string) (model.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaByKey", ctx, key)
+	ret0, _ := ret[0].(model.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaByKey indicates an expected call of GetMediaByKey.
+func (mr *MockGetMediaByKeyRepositoryMockRecorder) GetMediaByKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaByKey", reflect.TypeOf((*MockGetMediaByKeyRepository)(nil).GetMediaByKey), ctx, key)
+}
+
+// MockGetMediaByIDsRepository is a mock of GetMediaByIDsRepository interface.
+type MockGetMediaByIDsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetMediaByIDsRepositoryMockRecorder
+}
+
+// MockGetMediaByIDsRepositoryMockRecorder is the mock recorder for MockGetMediaByIDsRepository.
+type MockGetMediaByIDsRepositoryMockRecorder struct {
+	mock *MockGetMediaByIDsRepository
+}
+
+// NewMockGetMediaByIDsRepository creates a new mock instance.
+func NewMockGetMediaByIDsRepository(ctrl *gomock.Controller) *MockGetMediaByIDsRepository {
+	mock := &MockGetMediaByIDsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetMediaByIDsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetMediaByIDsRepository) EXPECT() *MockGetMediaByIDsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetMediaByIDs mocks base method.
+func (m *MockGetMediaByIDsRepository) GetMediaByIDs(ctx context.Context, ids []int) ([]model.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaByIDs indicates an expected call of GetMediaByIDs.
+func (mr *MockGetMediaByIDsRepositoryMockRecorder) GetMediaByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaByIDs", reflect.TypeOf((*MockGetMediaByIDsRepository)(nil).GetMediaByIDs), ctx, ids)
+}
+
+// MockAttachMediaRepository is a mock of AttachMediaRepository interface.
+type MockAttachMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachMediaRepositoryMockRecorder
+}
+
+// MockAttachMediaRepositoryMockRecorder is the mock recorder for MockAttachMediaRepository.
+type MockAttachMediaRepositoryMockRecorder struct {
+	mock *MockAttachMediaRepository
+}
+
+// NewMockAttachMediaRepository creates a new mock instance.
+func NewMockAttachMediaRepository(ctrl *gomock.Controller) *MockAttachMediaRepository {
+	mock := &MockAttachMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockAttachMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachMediaRepository) EXPECT() *MockAttachMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AttachMedia mocks base method.
+func (m *MockAttachMediaRepository) AttachMedia(ctx context.Context, postID int, mediaIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachMedia", ctx, postID, mediaIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachMedia indicates an expected call of AttachMedia.
+func (mr *MockAttachMediaRepositoryMockRecorder) AttachMedia(ctx, postID, mediaIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachMedia", reflect.TypeOf((*MockAttachMediaRepository)(nil).AttachMedia), ctx, postID, mediaIDs)
+}
+
+// MockUserPostUpdatePermissionService is a mock of UserPostUpdatePermissionService interface.
+type MockUserPostUpdatePermissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPostUpdatePermissionServiceMockRecorder
+}
+
+// MockUserPostUpdatePermissionServiceMockRecorder is the mock recorder for MockUserPostUpdatePermissionService.
+type MockUserPostUpdatePermissionServiceMockRecorder struct {
+	mock *MockUserPostUpdatePermissionService
+}
+
+// NewMockUserPostUpdatePermissionService creates a new mock instance.
+func NewMockUserPostUpdatePermissionService(ctrl *gomock.Controller) *MockUserPostUpdatePermissionService {
+	mock := &MockUserPostUpdatePermissionService{ctrl: ctrl}
+	mock.recorder = &MockUserPostUpdatePermissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPostUpdatePermissionService) EXPECT() *MockUserPostUpdatePermissionServiceMockRecorder {
+	return m.recorder
+}
+
+// UserCanUpdatePost mocks base method.
+func (m *MockUserPostUpdatePermissionService) UserCanUpdatePost(ctx context.Context, userID, postID int) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserCanUpdatePost", ctx, userID, postID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UserCanUpdatePost indicates an expected call of UserCanUpdatePost.
+func (mr *MockUserPostUpdatePermissionServiceMockRecorder) UserCanUpdatePost(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCanUpdatePost", reflect.TypeOf((*MockUserPostUpdatePermissionService)(nil).UserCanUpdatePost), ctx, userID, postID)
+}
+
+// MockImageProcessorService is a mock of ImageProcessorService interface.
+type MockImageProcessorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageProcessorServiceMockRecorder
+}
+
+// MockImageProcessorServiceMockRecorder is the mock recorder for MockImageProcessorService.
+type MockImageProcessorServiceMockRecorder struct {
+	mock *MockImageProcessorService
+}
+
+// NewMockImageProcessorService creates a new mock instance.
+func NewMockImageProcessorService(ctrl *gomock.Controller) *MockImageProcessorService {
+	mock := &MockImageProcessorService{ctrl: ctrl}
+	mock.recorder = &MockImageProcessorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageProcessorService) EXPECT() *MockImageProcessorServiceMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockImageProcessorService) Notify() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Notify")
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockImageProcessorServiceMockRecorder) Notify() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockImageProcessorService)(nil).Notify))
+}
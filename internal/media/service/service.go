@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	"github.com/ananaslegend/news-crud/internal/media/repository"
+	"github.com/ananaslegend/news-crud/pkg/imaging"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/storage"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+// sniffLen is the number of bytes http.DetectContentType looks at.
+const sniffLen = 512
+
+type CreateMediaRepository interface {
+	CreateMedia(ctx context.Context, media model.Media) (int, error)
+}
+
+type GetMediaByKeyRepository interface {
+	GetMediaByKey(ctx context.Context, key string) (model.Media, error)
+}
+
+type GetMediaByIDsRepository interface {
+	GetMediaByIDs(ctx context.Context, ids []int) ([]model.Media, error)
+}
+
+type AttachMediaRepository interface {
+	AttachMedia(ctx context.Context, postID int, mediaIDs []int) error
+}
+
+type UserPostUpdatePermissionService interface {
+	UserCanUpdatePost(ctx context.Context, userID, postID int) bool
+}
+
+type ImageProcessorService interface {
+	Notify()
+}
+
+// Config limits uploads. The type of a file is sniffed from its content, the
+// name and the type sent by the client are not trusted. MaxPixels guards the
+// image processing against images that decompress to huge bitmaps.
+type Config struct {
+	MaxSize      int64
+	MaxPixels    int
+	AllowedTypes []string
+}
+
+type MediaService struct {
+	logger  *slog.Logger
+	config  Config
+	storage storage.Storage
+
+	createMediaRepository   CreateMediaRepository
+	mediaByKeyRepository    GetMediaByKeyRepository
+	mediaByIDsRepository    GetMediaByIDsRepository
+	attachMediaRepository   AttachMediaRepository
+	updatePermissionService UserPostUpdatePermissionService
+	imageProcessorService   ImageProcessorService
+}
+
+func NewMediaService(
+	logger *slog.Logger,
+	config Config,
+	storage storage.Storage,
+	createMediaRepository CreateMediaRepository,
+	mediaByKeyRepository GetMediaByKeyRepository,
+	mediaByIDsRepository GetMediaByIDsRepository,
+	attachMediaRepository AttachMediaRepository,
+	updatePermissionService UserPostUpdatePermissionService,
+	imageProcessorService ImageProcessorService,
+) *MediaService {
+	return &MediaService{
+		logger:                  logger,
+		config:                  config,
+		storage:                 storage,
+		createMediaRepository:   createMediaRepository,
+		mediaByKeyRepository:    mediaByKeyRepository,
+		mediaByIDsRepository:    mediaByIDsRepository,
+		attachMediaRepository:   attachMediaRepository,
+		updatePermissionService: updatePermissionService,
+		imageProcessorService:   imageProcessorService,
+	}
+}
+
+// Upload stores the file under a new random key and records it as media of the
+// owner. Images are stored with their metadata stripped and are processed
+// after the upload.
+func (s MediaService) Upload(ctx context.Context, ownerID int, filename string, file io.ReadSeeker, size int64) (model.Media, error) {
+	const op = "news-crud.internal.media.upload.service.Upload"
+	logger := s.logger.With(slog.String("op", op))
+
+	if size > s.config.MaxSize {
+		return model.Media{}, ErrTooLarge
+	}
+
+	contentType, err := sniff(file)
+	if err != nil {
+		return model.Media{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !slices.Contains(s.config.AllowedTypes, contentType) {
+		return model.Media{}, ErrUnsupportedType
+	}
+
+	media := model.Media{
+		OwnerID:     ownerID,
+		ContentType: contentType,
+		Size:        size,
+		Filename:    filename,
+		Status:      model.StatusReady,
+	}
+
+	var content io.Reader = file
+	if imaging.Supported(contentType) {
+		data, err := s.prepareImage(file, &media)
+		if err != nil {
+			return model.Media{}, err
+		}
+		content = bytes.NewReader(data)
+	}
+
+	key, err := newKey(contentType)
+	if err != nil {
+		return model.Media{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.storage.Put(ctx, key, content, media.Size, contentType); err != nil {
+		return model.Media{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	media.Key = key
+	media.URL = model.URL(key)
+	media.CreatedAt = time.Now()
+
+	if media.ID, err = s.createMediaRepository.CreateMedia(ctx, media); err != nil {
+		if delErr := s.storage.Delete(context.WithoutCancel(ctx), key); delErr != nil {
+			logger.Error("cant delete orphaned media", slog.String("key", key), logs.Err(delErr))
+		}
+
+		return model.Media{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if media.Status == model.StatusPending {
+		s.imageProcessorService.Notify()
+	}
+
+	return media, nil
+}
+
+// prepareImage checks the file is an image that can be processed, strips its
+// metadata and records its dimensions, the image waits for processing.
+func (s MediaService) prepareImage(file io.Reader, media *model.Media) ([]byte, error) {
+	const op = "news-crud.internal.media.upload.service.prepareImage"
+
+	data, err := io.ReadAll(io.LimitReader(file, s.config.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if int64(len(data)) > s.config.MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	config, err := imaging.DecodeConfig(data)
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width*config.Height > s.config.MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	if data, err = imaging.StripMetadata(media.ContentType, data); err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	media.Size = int64(len(data))
+	media.Width, media.Height = config.Width, config.Height
+	media.Status = model.StatusPending
+
+	return data, nil
+}
+
+// AttachMedia replaces the media of the post. Users that can edit the post
+// may attach media they uploaded themselves.
+func (s MediaService) AttachMedia(ctx context.Context, userID, postID int, mediaIDs []int) error {
+	const op = "news-crud.internal.media.attach.service.AttachMedia"
+
+	ids := make([]int, 0, len(mediaIDs))
+	for _, id := range mediaIDs {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > model.MaxAttachments {
+		return ErrTooManyAttachments
+	}
+
+	if ok := s.updatePermissionService.UserCanUpdatePost(ctx, userID, postID); !ok {
+		return ErrUserHasNoPermission
+	}
+
+	if len(ids) > 0 {
+		media, err := s.mediaByIDsRepository.GetMediaByIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(media) != len(ids) {
+			return ErrNoMediaWasFound
+		}
+
+		for _, m := range media {
+			if m.OwnerID != userID {
+				return ErrUserHasNoPermission
+			}
+		}
+	}
+
+	if err := s.attachMediaRepository.AttachMedia(ctx, postID, ids); err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return ErrNoPostWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Open returns the media stored under the key and its content, the caller
+// closes the content.
+func (s MediaService) Open(ctx context.Context, key string) (model.Media, io.ReadCloser, error) {
+	const op = "news-crud.internal.media.open.service.Open"
+
+	media, err := s.mediaByKeyRepository.GetMediaByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoMediaWasFound) {
+			return model.Media{}, nil, ErrNoMediaWasFound
+		}
+
+		return model.Media{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	content, err := s.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return model.Media{}, nil, ErrNoMediaWasFound
+		}
+
+		return model.Media{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return media, content, nil
+}
+
+// sniff detects the media type of the file and rewinds it.
+func sniff(file io.ReadSeeker) (string, error) {
+	head := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "", err
+	}
+
+	return mediaType, nil
+}
+
+func newKey(contentType string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	name := hex.EncodeToString(b)
+
+	// spread the files over directories to keep them small
+	return name[:2] + "/" + name + model.Extension(contentType), nil
+}
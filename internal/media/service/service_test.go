@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/media/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/media/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/storage"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+// pngHeader is enough of a PNG file for content sniffing, but not an image.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var testConfig = Config{MaxSize: 4096, MaxPixels: 10000, AllowedTypes: []string{"image/png", "image/jpeg", "application/pdf"}}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func TestMediaService_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	create := mock_service.NewMockCreateMediaRepository(ctrl)
+	processor := mock_service.NewMockImageProcessorService(ctrl)
+	s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, local, create, nil, nil, nil, nil, processor)
+
+	file := testPNG(t, 40, 20)
+
+	create.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media model.Media) (int, error) {
+			require.Equal(t, 7, media.OwnerID)
+			require.Equal(t, "image/png", media.ContentType)
+			require.Equal(t, "cat.txt", media.Filename)
+			require.Equal(t, model.StatusPending, media.Status)
+			require.Equal(t, 40, media.Width)
+			require.Equal(t, 20, media.Height)
+			require.True(t, strings.HasSuffix(media.Key, ".png"))
+			return 3, nil
+		})
+	processor.EXPECT().Notify()
+
+	media, err := s.Upload(context.Background(), 7, "cat.txt", bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, 3, media.ID)
+	require.Equal(t, model.URL(media.Key), media.URL)
+
+	r, err := local.Get(context.Background(), media.Key)
+	require.NoError(t, err)
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, file, content)
+}
+
+func TestMediaService_Upload_StripsMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	create := mock_service.NewMockCreateMediaRepository(ctrl)
+	processor := mock_service.NewMockImageProcessorService(ctrl)
+	s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, local, create, nil, nil, nil, nil, processor)
+
+	encoded := testPNG(t, 4, 4)
+
+	// a text chunk with a location right after the header chunk
+	text := []byte("Location\x0050.4501,30.5234")
+	var file []byte
+	file = append(file, encoded[:33]...)
+	file = binary.BigEndian.AppendUint32(file, uint32(len(text)))
+	file = append(file, "tEXt"...)
+	file = append(file, text...)
+	file = binary.BigEndian.AppendUint32(file, crc32.ChecksumIEEE(append([]byte("tEXt"), text...)))
+	file = append(file, encoded[33:]...)
+
+	create.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).Return(1, nil)
+	processor.EXPECT().Notify()
+
+	media, err := s.Upload(context.Background(), 7, "cat.png", bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(encoded)), media.Size)
+
+	r, err := local.Get(context.Background(), media.Key)
+	require.NoError(t, err)
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, encoded, content)
+}
+
+func TestMediaService_Upload_NotAnImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	create := mock_service.NewMockCreateMediaRepository(ctrl)
+	s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, local, create, nil, nil, nil, nil, nil)
+
+	file := []byte("%PDF-1.4\n%%EOF\n")
+
+	create.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media model.Media) (int, error) {
+			require.Equal(t, "application/pdf", media.ContentType)
+			require.Equal(t, model.StatusReady, media.Status)
+			return 2, nil
+		})
+
+	media, err := s.Upload(context.Background(), 7, "report.pdf", bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, 2, media.ID)
+}
+
+func TestMediaService_Upload_Rejected(t *testing.T) {
+	s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, nil, nil, nil, nil, nil, nil, nil)
+
+	html := []byte("<html><script>alert(1)</script></html>")
+	_, err := s.Upload(context.Background(), 7, "cat.png", bytes.NewReader(html), int64(len(html)))
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = s.Upload(context.Background(), 7, "cat.png", bytes.NewReader(pngHeader), testConfig.MaxSize+1)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	// sniffed as PNG, but it does not decode
+	_, err = s.Upload(context.Background(), 7, "cat.png", bytes.NewReader(pngHeader), int64(len(pngHeader)))
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	// small file, too many pixels
+	huge := testPNG(t, 200, 200)
+	_, err = s.Upload(context.Background(), 7, "cat.png", bytes.NewReader(huge), int64(len(huge)))
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestMediaService_Upload_CleansUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	root := t.TempDir()
+	local, err := storage.NewLocal(root)
+	require.NoError(t, err)
+
+	create := mock_service.NewMockCreateMediaRepository(ctrl)
+	s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, local, create, nil, nil, nil, nil, nil)
+
+	file := testPNG(t, 4, 4)
+
+	var key string
+	create.EXPECT().CreateMedia(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, media model.Media) (int, error) {
+			key = media.Key
+			return 0, errors.New("db is down")
+		})
+
+	_, err = s.Upload(context.Background(), 7, "cat.png", bytes.NewReader(file), int64(len(file)))
+	require.Error(t, err)
+
+	_, err = local.Get(context.Background(), key)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestMediaService_AttachMedia(t *testing.T) {
+	tests := []struct {
+		name       string
+		mediaIDs   []int
+		canUpdate  bool
+		media      []model.Media
+		attachWith []int
+		wantErr    error
+	}{
+		{
+			name:       "attaches own media once",
+			mediaIDs:   []int{2, 1, 2},
+			canUpdate:  true,
+			media:      []model.Media{{ID: 1, OwnerID: 7}, {ID: 2, OwnerID: 7}},
+			attachWith: []int{2, 1},
+		},
+		{
+			name:       "detaches everything",
+			mediaIDs:   []int{},
+			canUpdate:  true,
+			attachWith: []int{},
+		},
+		{
+			name:      "cant edit post",
+			mediaIDs:  []int{1},
+			canUpdate: false,
+			wantErr:   ErrUserHasNoPermission,
+		},
+		{
+			name:      "media of another user",
+			mediaIDs:  []int{1, 2},
+			canUpdate: true,
+			media:     []model.Media{{ID: 1, OwnerID: 7}, {ID: 2, OwnerID: 8}},
+			wantErr:   ErrUserHasNoPermission,
+		},
+		{
+			name:      "unknown media",
+			mediaIDs:  []int{1, 2},
+			canUpdate: true,
+			media:     []model.Media{{ID: 1, OwnerID: 7}},
+			wantErr:   ErrNoMediaWasFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			byIDs := mock_service.NewMockGetMediaByIDsRepository(ctrl)
+			attach := mock_service.NewMockAttachMediaRepository(ctrl)
+			permission := mock_service.NewMockUserPostUpdatePermissionService(ctrl)
+
+			s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, nil, nil, nil, byIDs, attach, permission, nil)
+
+			permission.EXPECT().UserCanUpdatePost(gomock.Any(), 7, 5).Return(tt.canUpdate)
+			if tt.canUpdate && len(tt.mediaIDs) > 0 {
+				byIDs.EXPECT().GetMediaByIDs(gomock.Any(), gomock.Any()).Return(tt.media, nil)
+			}
+			if tt.attachWith != nil {
+				attach.EXPECT().AttachMedia(gomock.Any(), 5, tt.attachWith).Return(nil)
+			}
+
+			err := s.AttachMedia(context.Background(), 7, 5, tt.mediaIDs)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMediaService_AttachMedia_TooMany(t *testing.T) {
+	s := NewMediaService(slogdiscard.NewDiscardLogger(), testConfig, nil, nil, nil, nil, nil, nil, nil)
+
+	ids := make([]int, model.MaxAttachments+1)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	require.ErrorIs(t, s.AttachMedia(context.Background(), 7, 5, ids), ErrTooManyAttachments)
+}
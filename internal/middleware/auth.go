@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
+	"github.com/ananaslegend/news-crud/internal/apikey/model"
+	apiKeyService "github.com/ananaslegend/news-crud/internal/apikey/service"
 	"github.com/ananaslegend/news-crud/internal/contexts"
 	"github.com/ananaslegend/news-crud/pkg/jwt"
 	"net/http"
@@ -10,34 +13,125 @@ import (
 
 const (
 	AuthorizationHeader = "Authorization"
+	APIKeyHeader        = "X-API-Key"
 )
 
 var (
 	ErrInvalidAccessToken = fmt.Errorf("invalid access token")
 )
 
-func Auth(secret string, next http.HandlerFunc) http.HandlerFunc {
+type APIKeyAuthService interface {
+	Authenticate(ctx context.Context, plain string) (model.APIKey, error)
+}
+
+// Auth accepts a `Bearer` JWT or, when apiKeys is not nil, an api key passed as
+// `ApiKey <key>`, `Bearer <key>` or in the X-API-Key header.
+func Auth(secret string, apiKeys APIKeyAuthService, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get(AuthorizationHeader)
-		if authHeader == "" {
+		ctx, ok := authenticate(r.Context(), secret, apiKeys, r.Header.Get(AuthorizationHeader), r.Header.Get(APIKeyHeader))
+		if !ok {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		headerParts := strings.Split(authHeader, " ")
-		if len(headerParts) != 2 || headerParts[0] != "Bearer" {
-			w.WriteHeader(http.StatusUnauthorized)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// OptionalAuth is Auth for routes anonymous clients may use too. Requests
+// without credentials pass through, invalid credentials are still rejected.
+func OptionalAuth(secret string, apiKeys APIKeyAuthService, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authorization, key := r.Header.Get(AuthorizationHeader), r.Header.Get(APIKeyHeader)
+		if authorization == "" && key == "" {
+			next(w, r)
 			return
 		}
 
-		claims, err := jwt.ParseToken(headerParts[1], []byte(secret))
-		if err != nil {
+		ctx, ok := authenticate(r.Context(), secret, apiKeys, authorization, key)
+		if !ok {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		r = r.WithContext(contexts.SetUserID(r.Context(), claims.UserID))
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// authenticate checks the credentials passed as the Authorization and the
+// X-API-Key headers and stores who made the request in the context.
+func authenticate(ctx context.Context, secret string, apiKeys APIKeyAuthService, authorization, key string) (context.Context, bool) {
+	if key != "" && apiKeys != nil {
+		return authAPIKey(ctx, apiKeys, key)
+	}
+
+	if authorization == "" {
+		return ctx, false
+	}
+
+	parts := strings.Split(authorization, " ")
+	if len(parts) != 2 {
+		return ctx, false
+	}
+
+	switch {
+	case parts[0] == "ApiKey" && apiKeys != nil,
+		parts[0] == "Bearer" && apiKeys != nil && apiKeyService.IsAPIKey(parts[1]):
+		return authAPIKey(ctx, apiKeys, parts[1])
+	case parts[0] != "Bearer":
+		return ctx, false
+	}
+
+	claims, err := jwt.ParseToken(parts[1], []byte(secret))
+	if err != nil {
+		return ctx, false
+	}
+
+	ctx = contexts.SetUserID(ctx, claims.UserID)
+	ctx = contexts.SetRoles(ctx, claims.Roles)
+
+	return ctx, true
+}
+
+func authAPIKey(ctx context.Context, apiKeys APIKeyAuthService, plain string) (context.Context, bool) {
+	key, err := apiKeys.Authenticate(ctx, plain)
+	if err != nil {
+		return ctx, false
+	}
+
+	ctx = contexts.SetUserID(ctx, key.UserID)
+	ctx = contexts.SetAPIKey(ctx, key.ID, key.Scopes)
+
+	return ctx, true
+}
+
+// Scope rejects requests made with an api key that was not granted scope.
+func Scope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !contexts.HasScope(r.Context(), scope) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
 
 		next(w, r)
 	}
 }
+
+// Role rejects requests of users that were not granted role.
+func Role(role string, next http.HandlerFunc) http.HandlerFunc {
+	return AnyRole([]string{role}, next)
+}
+
+// AnyRole rejects requests of users that were granted none of roles.
+func AnyRole(roles []string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, role := range roles {
+			if contexts.HasRole(r.Context(), role) {
+				next(w, r)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusForbidden)
+	}
+}
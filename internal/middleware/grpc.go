@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+)
+
+// AuthorizationMetadata and APIKeyMetadata carry the credentials of gRPC
+// calls, the same way as the Authorization and X-API-Key headers.
+// RequestIDMetadata is the X-Request-ID header of gRPC calls.
+var (
+	AuthorizationMetadata = strings.ToLower(AuthorizationHeader)
+	APIKeyMetadata        = strings.ToLower(APIKeyHeader)
+	RequestIDMetadata     = strings.ToLower(RequestIDHeader)
+)
+
+// UnaryRequestInfo stores the request ID and the client IP of gRPC calls in
+// the context like RequestInfo does for HTTP requests. The client IP is the
+// address of the peer, the request ID is echoed back in the header metadata.
+func UnaryRequestInfo() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, md := requestInfo(ctx)
+		_ = grpc.SetHeader(ctx, md)
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestInfo is UnaryRequestInfo for streaming calls.
+func StreamRequestInfo() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, md := requestInfo(ss.Context())
+		_ = ss.SetHeader(md)
+
+		return handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func requestInfo(ctx context.Context) (context.Context, metadata.MD) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md.Get(RequestIDMetadata))
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		requestID = newRequestID()
+	}
+
+	ctx = contexts.SetRequestID(ctx, requestID)
+	if ip := peerIP(ctx); ip != "" {
+		ctx = contexts.SetClientIP(ctx, ip)
+	}
+
+	return ctx, metadata.Pairs(RequestIDMetadata, requestID)
+}
+
+// UnaryAuth authenticates gRPC calls like Auth does HTTP requests. Calls
+// without credentials are anonymous, the server tells which calls need a user.
+func UnaryAuth(secret string, apiKeys APIKeyAuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticateMetadata(ctx, secret, apiKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth is UnaryAuth for streaming calls.
+func StreamAuth(secret string, apiKeys APIKeyAuthService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateMetadata(ss.Context(), secret, apiKeys)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticateMetadata(ctx context.Context, secret string, apiKeys APIKeyAuthService) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	authorization, key := first(md.Get(AuthorizationMetadata)), first(md.Get(APIKeyMetadata))
+	if authorization == "" && key == "" {
+		return ctx, nil
+	}
+
+	ctx, ok := authenticate(ctx, secret, apiKeys, authorization, key)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, ErrInvalidAccessToken.Error())
+	}
+
+	return ctx, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// contextStream passes the context of the interceptors to the stream handler.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// peerIP is the IP of the client connected to a gRPC call.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
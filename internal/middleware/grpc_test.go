@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/jwt"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUnaryAuth(t *testing.T) {
+	const secret = "secret"
+
+	interceptor := UnaryAuth(secret, nil)
+	handler := func(ctx context.Context, _ any) (any, error) {
+		userID, _ := contexts.GetUserID(ctx)
+		return userID, nil
+	}
+
+	call := func(md metadata.MD) (any, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	}
+
+	t.Run("Calls without credentials are anonymous", func(t *testing.T) {
+		userID, err := call(metadata.MD{})
+		require.NoError(t, err)
+		require.Equal(t, 0, userID)
+	})
+
+	t.Run("Token sets the user", func(t *testing.T) {
+		token, err := jwt.NewToken(7, nil, time.Minute, []byte(secret))
+		require.NoError(t, err)
+
+		userID, err := call(metadata.Pairs(AuthorizationMetadata, "Bearer "+token))
+		require.NoError(t, err)
+		require.Equal(t, 7, userID)
+	})
+
+	t.Run("Invalid token is rejected", func(t *testing.T) {
+		_, err := call(metadata.Pairs(AuthorizationMetadata, "Bearer invalid"))
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestUnaryRequestInfo(t *testing.T) {
+	interceptor := UnaryRequestInfo()
+	handler := func(ctx context.Context, _ any) (any, error) {
+		ip, _ := contexts.GetClientIP(ctx)
+		return []string{ip, contexts.GetRequestID(ctx)}, nil
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIDMetadata, "abc"))
+
+	got, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1", "abc"}, got)
+}
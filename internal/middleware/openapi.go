@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"github.com/ananaslegend/news-crud/pkg/openapi"
+	"net/http"
+)
+
+// ValidateRequests answers 400 to requests that do not match the OpenAPI
+// document, the body says what is wrong. Routes the document does not
+// describe are left to the mux.
+func ValidateRequests(doc *openapi.Document, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, ok := doc.Find(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := m.ValidateRequest(r); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ValidateResponses calls report for responses that do not match the OpenAPI
+// document or that are sent for routes it does not describe. The body is
+// copied while it is written, it is meant for tests.
+func ValidateResponses(doc *openapi.Document, report func(r *http.Request, err error), next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, ok := doc.Find(r.Method, r.URL.Path)
+		if !ok {
+			report(r, errors.New("route is not documented"))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		if err := m.ValidateResponse(rec.statusCode(), rec.header(), rec.body.Bytes()); err != nil {
+			report(r, err)
+		}
+	})
+}
+
+// recordingWriter keeps what the handler wrote. The content type is taken the
+// way net/http sends it, sniffed from the body when the handler did not set
+// one.
+type recordingWriter struct {
+	http.ResponseWriter
+
+	status      int
+	contentType string
+	body        bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.body.Len() == 0 && w.contentType == "" {
+		w.contentType = w.Header().Get("Content-Type")
+		if w.contentType == "" {
+			w.contentType = http.DetectContentType(b)
+		}
+	}
+
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *recordingWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *recordingWriter) header() http.Header {
+	h := w.Header().Clone()
+	if w.contentType != "" {
+		h.Set("Content-Type", w.contentType)
+	}
+	return h
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"github.com/ananaslegend/news-crud/pkg/openapi"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testDocument = `{
+	"openapi": "3.1.0",
+	"paths": {
+		"/posts/{id}": {
+			"get": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+				"responses": {
+					"200": {"content": {"application/json": {"schema": {
+						"type": "object",
+						"required": ["ID"],
+						"properties": {"ID": {"type": "integer"}}
+					}}}},
+					"404": {}
+				}
+			}
+		}
+	}
+}`
+
+func TestValidateRequests(t *testing.T) {
+	doc, err := openapi.Load([]byte(testDocument))
+	require.NoError(t, err)
+
+	handler := ValidateRequests(doc, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	do := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	require.Equal(t, http.StatusTeapot, do(http.MethodGet, "/posts/1").Code)
+
+	w := do(http.MethodGet, "/posts/abc")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, `path parameter "id": should be integer`, w.Body.String())
+
+	// the mux answers routes and methods the document does not describe
+	require.Equal(t, http.StatusTeapot, do(http.MethodGet, "/unknown").Code)
+	require.Equal(t, http.StatusTeapot, do(http.MethodDelete, "/posts/1").Code)
+}
+
+func TestValidateResponses(t *testing.T) {
+	doc, err := openapi.Load([]byte(testDocument))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr string
+	}{
+		{
+			name: "Valid response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"ID": 1}`))
+			},
+		},
+		{
+			name: "Invalid body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"ID": "1"}`))
+			},
+			wantErr: "ID should be integer",
+		},
+		{
+			name: "Sniffed content type",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"ID": 1}`))
+			},
+			wantErr: `"text/plain"`,
+		},
+		{
+			name: "Undocumented status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantErr: "500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported error
+			handler := ValidateResponses(doc, func(r *http.Request, err error) {
+				reported = err
+			}, tt.handler)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+
+			if tt.wantErr == "" {
+				require.NoError(t, reported)
+				return
+			}
+			require.ErrorContains(t, reported, tt.wantErr)
+		})
+	}
+}
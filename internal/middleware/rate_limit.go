@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/config"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+)
+
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error)
+}
+
+type RateLimiter struct {
+	logger *slog.Logger
+	store  RateLimitStore
+}
+
+func NewRateLimiter(logger *slog.Logger, store RateLimitStore) *RateLimiter {
+	return &RateLimiter{
+		logger: logger,
+		store:  store,
+	}
+}
+
+// Limit throttles the route with a bucket per api key, per user or per client IP,
+// whichever identifies the request best. Place it after Auth on protected routes,
+// so the identity is known.
+func (rl RateLimiter) Limit(route string, rule config.RateLimitRule, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "news-crud.internal.middleware.RateLimiter.Limit"
+
+		key, limit := rl.bucket(r.Context(), clientIP(r, false), route, rule)
+		if !limit.Enabled() {
+			next(w, r)
+			return
+		}
+
+		res, err := rl.store.Take(r.Context(), key, limit)
+		if err != nil {
+			// an unavailable store must not take the whole api down
+			rl.logger.Error("cant take rate limit token", slog.String("op", op), logs.Err(err))
+			next(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set(RateLimitLimitHeader, strconv.Itoa(limit.Count))
+		h.Set(RateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+		h.Set(RateLimitResetHeader, ceilSeconds(res.Reset))
+		h.Set(RateLimitPolicyHeader, strconv.Itoa(limit.Count)+";w="+ceilSeconds(limit.Period))
+
+		if !res.Allowed {
+			h.Set(RetryAfterHeader, ceilSeconds(res.RetryAfter))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// MethodLimit is the route and the rule a gRPC method is limited by. Methods
+// sharing a route with HTTP routes share their buckets too.
+type MethodLimit struct {
+	Route string
+	Rule  config.RateLimitRule
+}
+
+// UnaryLimit throttles gRPC calls like Limit does HTTP requests, methods
+// missing from methods are not limited. Place it after UnaryAuth, so the
+// identity is known.
+func (rl RateLimiter) UnaryLimit(methods map[string]MethodLimit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, err := rl.takeCall(ctx, methods[info.FullMethod])
+		if md != nil {
+			_ = grpc.SetHeader(ctx, md)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamLimit is UnaryLimit for streaming calls.
+func (rl RateLimiter) StreamLimit(methods map[string]MethodLimit) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, err := rl.takeCall(ss.Context(), methods[info.FullMethod])
+		if md != nil {
+			_ = ss.SetHeader(md)
+		}
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// takeCall takes a token for a gRPC call. It returns the rate limit headers,
+// nil when the call is not limited, and ResourceExhausted when the bucket is
+// empty.
+func (rl RateLimiter) takeCall(ctx context.Context, method MethodLimit) (metadata.MD, error) {
+	const op = "news-crud.internal.middleware.RateLimiter.takeCall"
+
+	if method.Route == "" {
+		return nil, nil
+	}
+
+	key, limit := rl.bucket(ctx, peerIP(ctx), method.Route, method.Rule)
+	if !limit.Enabled() {
+		return nil, nil
+	}
+
+	res, err := rl.store.Take(ctx, key, limit)
+	if err != nil {
+		// an unavailable store must not take the whole api down
+		rl.logger.Error("cant take rate limit token", slog.String("op", op), logs.Err(err))
+		return nil, nil
+	}
+
+	md := metadata.Pairs(
+		strings.ToLower(RateLimitLimitHeader), strconv.Itoa(limit.Count),
+		strings.ToLower(RateLimitRemainingHeader), strconv.Itoa(res.Remaining),
+		strings.ToLower(RateLimitResetHeader), ceilSeconds(res.Reset),
+		strings.ToLower(RateLimitPolicyHeader), strconv.Itoa(limit.Count)+";w="+ceilSeconds(limit.Period),
+	)
+
+	if !res.Allowed {
+		md.Set(strings.ToLower(RetryAfterHeader), ceilSeconds(res.RetryAfter))
+		return md, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	return md, nil
+}
+
+// bucket picks the bucket of the api key, the user or the client IP of ctx.
+// remoteIP is used when the client IP is not in ctx.
+func (rl RateLimiter) bucket(ctx context.Context, remoteIP, route string, rule config.RateLimitRule) (string, ratelimit.Limit) {
+	if keyID, ok := contexts.GetAPIKeyID(ctx); ok {
+		return route + ":api_key:" + strconv.Itoa(keyID), rule.APIKey
+	}
+
+	if userID, ok := contexts.GetUserID(ctx); ok {
+		return route + ":user:" + strconv.Itoa(userID), rule.User
+	}
+
+	ip, ok := contexts.GetClientIP(ctx)
+	if !ok {
+		ip = remoteIP
+	}
+
+	return route + ":ip:" + ip, rule.IP
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/config"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/ratelimit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Limit(t *testing.T) {
+	rule := config.RateLimitRule{
+		User: ratelimit.Limit{Count: 2, Period: time.Minute},
+		IP:   ratelimit.Limit{Count: 1, Period: time.Minute},
+	}
+
+	limiter := NewRateLimiter(slogdiscard.NewDiscardLogger(), ratelimit.NewMemoryStore())
+	handler := limiter.Limit("posts", rule, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	do := func(userID int, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		r.RemoteAddr = remoteAddr
+		if userID != 0 {
+			r = r.WithContext(contexts.SetUserID(r.Context(), userID))
+		}
+
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	t.Run("Anonymous requests are limited per IP", func(t *testing.T) {
+		w := do(0, "10.0.0.1:1234")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "1", w.Header().Get(RateLimitLimitHeader))
+		require.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+		require.Equal(t, "1;w=60", w.Header().Get(RateLimitPolicyHeader))
+
+		w = do(0, "10.0.0.1:4321")
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "60", w.Header().Get(RetryAfterHeader))
+
+		w = do(0, "10.0.0.2:1234")
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Authenticated requests are limited per user", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(1, "10.0.0.1:1234").Code)
+		require.Equal(t, http.StatusOK, do(1, "10.0.0.1:1234").Code)
+		require.Equal(t, http.StatusTooManyRequests, do(1, "10.0.0.3:1234").Code)
+		require.Equal(t, http.StatusOK, do(2, "10.0.0.1:1234").Code)
+	})
+}
+
+func TestRateLimiter_UnaryLimit(t *testing.T) {
+	rule := config.RateLimitRule{
+		IP: ratelimit.Limit{Count: 1, Period: time.Minute},
+	}
+
+	limiter := NewRateLimiter(slogdiscard.NewDiscardLogger(), ratelimit.NewMemoryStore())
+	interceptor := limiter.UnaryLimit(map[string]MethodLimit{
+		"/post.v1.PostService/CreatePost": {Route: "posts-write", Rule: rule},
+	})
+	handler := func(context.Context, any) (any, error) {
+		return nil, nil
+	}
+
+	call := func(method, ip string) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234},
+		})
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	t.Run("Anonymous calls are limited per peer IP", func(t *testing.T) {
+		require.NoError(t, call("/post.v1.PostService/CreatePost", "10.0.0.1"))
+		require.Equal(t, codes.ResourceExhausted, status.Code(call("/post.v1.PostService/CreatePost", "10.0.0.1")))
+		require.NoError(t, call("/post.v1.PostService/CreatePost", "10.0.0.2"))
+	})
+
+	t.Run("Methods without a rule are not limited", func(t *testing.T) {
+		require.NoError(t, call("/post.v1.PostService/GetPost", "10.0.0.1"))
+		require.NoError(t, call("/post.v1.PostService/GetPost", "10.0.0.1"))
+	})
+}
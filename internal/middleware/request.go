@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	ForwardedForHeader = "X-Forwarded-For"
+
+	maxRequestIDLength = 128
+)
+
+// RequestInfo stores the request ID and the client IP in the request context.
+// The request ID is taken from X-Request-ID when present and echoed back.
+// X-Forwarded-For is only trusted behind a proxy that sets it.
+func RequestInfo(trustForwardedFor bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := contexts.SetRequestID(r.Context(), requestID)
+		ctx = contexts.SetClientIP(ctx, clientIP(r, trustForwardedFor))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Get(ForwardedForHeader); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
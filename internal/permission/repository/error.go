@@ -3,5 +3,6 @@ package repository
 import "errors"
 
 var (
-	ErrNoPostWasFound = errors.New("post not found")
+	ErrNoPostWasFound  = errors.New("post not found")
+	ErrUserIsNotAuthor = errors.New("user is not author of post")
 )
@@ -8,7 +8,7 @@ import (
 )
 
 const (
-	DefaultAuthorID = 0
+	NoAuthorRole = ""
 )
 
 type PermissionRepository struct {
@@ -19,25 +19,29 @@ func NewPermissionRepository(db *sql.DB) *PermissionRepository {
 	return &PermissionRepository{db: db}
 }
 
-func (pr PermissionRepository) GetAuthorPostByID(ctx context.Context, id int) (int, error) {
-	const op = "news-crud.internal.post.get_by_id.repository.GetPostByID"
-
-	stmt, err := pr.db.Prepare(`
-		select author_id 
-		from post
-		where id == ?
-	`)
+// GetPostAuthorRole returns the byline role of the user on the post,
+// ErrNoPostWasFound when there is no such post and ErrUserIsNotAuthor when the
+// user is not on its byline.
+func (pr PermissionRepository) GetPostAuthorRole(ctx context.Context, postID, userID int) (string, error) {
+	const op = "news-crud.internal.permission.get_author_role.repository.GetPostAuthorRole"
+
+	var role string
+	err := pr.db.QueryRowContext(ctx, `
+		select coalesce(pa.role, '')
+		from posts p
+		left join post_authors pa on pa.post_id = p.id and pa.user_id = $2
+		where p.id = $1
+	`, postID, userID).Scan(&role)
 	if err != nil {
-		return DefaultAuthorID, err
-	}
-
-	var authorID int
-	if err = stmt.QueryRowContext(ctx, id).Scan(&authorID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return DefaultAuthorID, ErrNoPostWasFound
+			return NoAuthorRole, ErrNoPostWasFound
 		}
-		return DefaultAuthorID, fmt.Errorf("%s: %w", op, err)
+		return NoAuthorRole, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if role == NoAuthorRole {
+		return NoAuthorRole, ErrUserIsNotAuthor
 	}
 
-	return authorID, nil
+	return role, nil
 }
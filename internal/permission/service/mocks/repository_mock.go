@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=service.go -destination=mocks/mock.go
+//	mockgen -source=service.go -destination=mocks/repository_mock.go
 //
 
 // Package mock_service is a generated GoMock package.
@@ -16,40 +16,40 @@ import (
 	gomock "go.uber.org/mock/gomock"
 )
 
-// MockGetAuthorPostByID is a mock of GetAuthorPostByID interface.
-type MockGetAuthorPostByID struct {
+// MockGetPostAuthorRole is a mock of GetPostAuthorRole interface.
+type MockGetPostAuthorRole struct {
 	ctrl     *gomock.Controller
-	recorder *MockGetAuthorPostByIDMockRecorder
+	recorder *MockGetPostAuthorRoleMockRecorder
 }
 
-// MockGetAuthorPostByIDMockRecorder is the mock recorder for MockGetAuthorPostByID.
-type MockGetAuthorPostByIDMockRecorder struct {
-	mock *MockGetAuthorPostByID
+// MockGetPostAuthorRoleMockRecorder is the mock recorder for MockGetPostAuthorRole.
+type MockGetPostAuthorRoleMockRecorder struct {
+	mock *MockGetPostAuthorRole
 }
 
-// NewMockGetAuthorPostByID creates a new mock instance.
-func NewMockGetAuthorPostByID(ctrl *gomock.Controller) *MockGetAuthorPostByID {
-	mock := &MockGetAuthorPostByID{ctrl: ctrl}
-	mock.recorder = &MockGetAuthorPostByIDMockRecorder{mock}
+// NewMockGetPostAuthorRole creates a new mock instance.
+func NewMockGetPostAuthorRole(ctrl *gomock.Controller) *MockGetPostAuthorRole {
+	mock := &MockGetPostAuthorRole{ctrl: ctrl}
+	mock.recorder = &MockGetPostAuthorRoleMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockGetAuthorPostByID) EXPECT() *MockGetAuthorPostByIDMockRecorder {
+func (m *MockGetPostAuthorRole) EXPECT() *MockGetPostAuthorRoleMockRecorder {
 	return m.recorder
 }
 
-// GetAuthorPostByID mocks base method.
-func (m *MockGetAuthorPostByID) GetAuthorPostByID(ctx context.Context, id int) (int, error) {
+// GetPostAuthorRole mocks base method.
+func (m *MockGetPostAuthorRole) GetPostAuthorRole(ctx context.Context, postID, userID int) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAuthorPostByID", ctx, id)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "GetPostAuthorRole", ctx, postID, userID)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAuthorPostByID indicates an expected call of GetAuthorPostByID.
-func (mr *MockGetAuthorPostByIDMockRecorder) GetAuthorPostByID(ctx, id any) *gomock.Call {
+// GetPostAuthorRole indicates an expected call of GetPostAuthorRole.
+func (mr *MockGetPostAuthorRoleMockRecorder) GetPostAuthorRole(ctx, postID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorPostByID", reflect.TypeOf((*MockGetAuthorPostByID)(nil).GetAuthorPostByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostAuthorRole", reflect.TypeOf((*MockGetPostAuthorRole)(nil).GetPostAuthorRole), ctx, postID, userID)
 }
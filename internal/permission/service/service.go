@@ -2,56 +2,50 @@ package service
 
 import (
 	"context"
-	"errors"
-	"github.com/ananaslegend/news-crud/internal/permission/repository"
+	"github.com/ananaslegend/news-crud/internal/post/model"
 )
 
-//go:generate mockgen -source=service.go -destination=mocks/mock.go
+//go:generate mockgen -source=service.go -destination=mocks/repository_mock.go
 
-type GetAuthorPostByID interface {
-	GetAuthorPostByID(ctx context.Context, id int) (int, error)
+type GetPostAuthorRole interface {
+	GetPostAuthorRole(ctx context.Context, postID, userID int) (string, error)
 }
 
 type PermissionService struct {
-	postByID GetAuthorPostByID
+	authorRole GetPostAuthorRole
 }
 
-func NewPermissionService(getPostByID GetAuthorPostByID) *PermissionService {
+func NewPermissionService(getPostAuthorRole GetPostAuthorRole) *PermissionService {
 	return &PermissionService{
-		postByID: getPostByID,
+		authorRole: getPostAuthorRole,
 	}
 }
 
+// UserCanUpdatePost allows every author on the byline to edit the post.
 func (s PermissionService) UserCanUpdatePost(ctx context.Context, userID, postID int) bool {
-	authorID, err := s.postByID.GetAuthorPostByID(ctx, postID)
+	role, err := s.authorRole.GetPostAuthorRole(ctx, postID, userID)
 	if err != nil {
-		if errors.Is(err, repository.ErrNoPostWasFound) {
-			return false
-		}
-
 		return false
 	}
 
-	if authorID != userID {
-		return false
-	}
-
-	return true
+	return role == model.AuthorRoleLead || role == model.AuthorRoleContributor
 }
 
+// UserCanDeletePost allows the lead author only to delete the post.
 func (s PermissionService) UserCanDeletePost(ctx context.Context, userID, postID int) bool {
-	authorID, err := s.postByID.GetAuthorPostByID(ctx, postID)
-	if err != nil {
-		if errors.Is(err, repository.ErrNoPostWasFound) {
-			return false
-		}
+	return s.isLeadAuthor(ctx, userID, postID)
+}
 
-		return false
-	}
+// UserCanManagePostAuthors allows the lead author only to change the byline.
+func (s PermissionService) UserCanManagePostAuthors(ctx context.Context, userID, postID int) bool {
+	return s.isLeadAuthor(ctx, userID, postID)
+}
 
-	if authorID != userID {
+func (s PermissionService) isLeadAuthor(ctx context.Context, userID, postID int) bool {
+	role, err := s.authorRole.GetPostAuthorRole(ctx, postID, userID)
+	if err != nil {
 		return false
 	}
 
-	return true
+	return role == model.AuthorRoleLead
 }
@@ -4,21 +4,18 @@ import (
 	"context"
 	"github.com/ananaslegend/news-crud/internal/permission/repository"
 	mock_service "github.com/ananaslegend/news-crud/internal/permission/service/mocks"
+	"github.com/ananaslegend/news-crud/internal/post/model"
 	"go.uber.org/mock/gomock"
 	"testing"
 )
 
-type GetAuthorPostByIDMock struct {
-	returnValue int
-}
-
 func TestPermissionService_UserCanDeletePost(t *testing.T) {
 	type args struct {
 		ctx    context.Context
 		userID int
 		postID int
 	}
-	type mockBehavior func(s *mock_service.MockGetAuthorPostByID, postID int)
+	type mockBehavior func(s *mock_service.MockGetPostAuthorRole, postID, userID int)
 
 	tests := []struct {
 		name         string
@@ -27,9 +24,9 @@ func TestPermissionService_UserCanDeletePost(t *testing.T) {
 		want         bool
 	}{
 		{
-			name: "User can delete post, because he is author of post",
-			mockBehavior: func(s *mock_service.MockGetAuthorPostByID, postID int) {
-				s.EXPECT().GetAuthorPostByID(gomock.Any(), postID).Return(1, nil)
+			name: "User can delete post, because he is lead author of post",
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).Return(model.AuthorRoleLead, nil)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -38,10 +35,23 @@ func TestPermissionService_UserCanDeletePost(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "User can not delete post, because he is only contributor of post",
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).Return(model.AuthorRoleContributor, nil)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			want: false,
+		},
 		{
 			name: "User can not delete post, because he is not author of post",
-			mockBehavior: func(s *mock_service.MockGetAuthorPostByID, postID int) {
-				s.EXPECT().GetAuthorPostByID(gomock.Any(), postID).Return(2, nil)
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).
+					Return(repository.NoAuthorRole, repository.ErrUserIsNotAuthor)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -52,8 +62,9 @@ func TestPermissionService_UserCanDeletePost(t *testing.T) {
 		},
 		{
 			name: "User can not delete post, post does not exist",
-			mockBehavior: func(s *mock_service.MockGetAuthorPostByID, postID int) {
-				s.EXPECT().GetAuthorPostByID(gomock.Any(), postID).Return(0, repository.ErrNoPostWasFound)
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).
+					Return(repository.NoAuthorRole, repository.ErrNoPostWasFound)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -68,10 +79,10 @@ func TestPermissionService_UserCanDeletePost(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 
-			mockGetAuthorPostByID := mock_service.NewMockGetAuthorPostByID(c)
-			testCase.mockBehavior(mockGetAuthorPostByID, testCase.args.userID)
+			mockGetPostAuthorRole := mock_service.NewMockGetPostAuthorRole(c)
+			testCase.mockBehavior(mockGetPostAuthorRole, testCase.args.postID, testCase.args.userID)
 
-			s := NewPermissionService(mockGetAuthorPostByID)
+			s := NewPermissionService(mockGetPostAuthorRole)
 
 			if got := s.UserCanDeletePost(testCase.args.ctx, testCase.args.userID, testCase.args.postID); got != testCase.want {
 				t.Errorf("UserCanDeletePost() = %v, want %v", got, testCase.want)
@@ -81,7 +92,7 @@ func TestPermissionService_UserCanDeletePost(t *testing.T) {
 }
 
 func TestPermissionService_UserCanUpdatePost(t *testing.T) {
-	type mockBehavior func(s *mock_service.MockGetAuthorPostByID, postID int)
+	type mockBehavior func(s *mock_service.MockGetPostAuthorRole, postID, userID int)
 	type args struct {
 		ctx    context.Context
 		userID int
@@ -94,9 +105,21 @@ func TestPermissionService_UserCanUpdatePost(t *testing.T) {
 		want         bool
 	}{
 		{
-			name: "User can update post, because he is author of post",
-			mockBehavior: func(s *mock_service.MockGetAuthorPostByID, postID int) {
-				s.EXPECT().GetAuthorPostByID(gomock.Any(), postID).Return(1, nil)
+			name: "User can update post, because he is lead author of post",
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).Return(model.AuthorRoleLead, nil)
+			},
+			args: args{
+				ctx:    context.Background(),
+				userID: 1,
+				postID: 1,
+			},
+			want: true,
+		},
+		{
+			name: "User can update post, because he is co-author of post",
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).Return(model.AuthorRoleContributor, nil)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -107,8 +130,9 @@ func TestPermissionService_UserCanUpdatePost(t *testing.T) {
 		},
 		{
 			name: "User can`t update post, because he is not author of post",
-			mockBehavior: func(s *mock_service.MockGetAuthorPostByID, postID int) {
-				s.EXPECT().GetAuthorPostByID(gomock.Any(), postID).Return(2, nil)
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).
+					Return(repository.NoAuthorRole, repository.ErrUserIsNotAuthor)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -119,8 +143,9 @@ func TestPermissionService_UserCanUpdatePost(t *testing.T) {
 		},
 		{
 			name: "User can not delete post, post does not exist",
-			mockBehavior: func(s *mock_service.MockGetAuthorPostByID, postID int) {
-				s.EXPECT().GetAuthorPostByID(gomock.Any(), postID).Return(0, repository.ErrNoPostWasFound)
+			mockBehavior: func(s *mock_service.MockGetPostAuthorRole, postID, userID int) {
+				s.EXPECT().GetPostAuthorRole(gomock.Any(), postID, userID).
+					Return(repository.NoAuthorRole, repository.ErrNoPostWasFound)
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -136,10 +161,10 @@ func TestPermissionService_UserCanUpdatePost(t *testing.T) {
 			c := gomock.NewController(t)
 			defer c.Finish()
 
-			mockGetAuthorPostByID := mock_service.NewMockGetAuthorPostByID(c)
-			testCase.mockBehavior(mockGetAuthorPostByID, testCase.args.userID)
+			mockGetPostAuthorRole := mock_service.NewMockGetPostAuthorRole(c)
+			testCase.mockBehavior(mockGetPostAuthorRole, testCase.args.postID, testCase.args.userID)
 
-			s := NewPermissionService(mockGetAuthorPostByID)
+			s := NewPermissionService(mockGetPostAuthorRole)
 
 			if got := s.UserCanUpdatePost(testCase.args.ctx, testCase.args.userID, testCase.args.postID); got != testCase.want {
 				t.Errorf("UserCanUpdatePost() = %v, want %v", got, testCase.want)
@@ -147,3 +172,32 @@ func TestPermissionService_UserCanUpdatePost(t *testing.T) {
 		})
 	}
 }
+
+func TestPermissionService_UserCanManagePostAuthors(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		err  error
+		want bool
+	}{
+		{name: "Lead author can manage byline", role: model.AuthorRoleLead, want: true},
+		{name: "Contributor can not manage byline", role: model.AuthorRoleContributor, want: false},
+		{name: "Stranger can not manage byline", err: repository.ErrUserIsNotAuthor, want: false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			mockGetPostAuthorRole := mock_service.NewMockGetPostAuthorRole(c)
+			mockGetPostAuthorRole.EXPECT().GetPostAuthorRole(gomock.Any(), 1, 1).Return(testCase.role, testCase.err)
+
+			s := NewPermissionService(mockGetPostAuthorRole)
+
+			if got := s.UserCanManagePostAuthors(context.Background(), 1, 1); got != testCase.want {
+				t.Errorf("UserCanManagePostAuthors() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
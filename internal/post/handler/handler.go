@@ -8,6 +8,7 @@ import (
 	"github.com/ananaslegend/news-crud/internal/post/model"
 	"github.com/ananaslegend/news-crud/internal/post/service"
 	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/markup"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 	"net/http"
@@ -16,7 +17,7 @@ import (
 )
 
 type CreatePostService interface {
-	CreatePost(ctx context.Context, title, content string, authorID int) (int, error)
+	CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error)
 }
 
 type GetPostByFilterService interface {
@@ -24,7 +25,7 @@ type GetPostByFilterService interface {
 }
 
 type GetPostByIDService interface {
-	GetPostByID(ctx context.Context, id int) (model.Post, error)
+	GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error)
 }
 
 type UpdatePostService interface {
@@ -35,6 +36,14 @@ type DeletePostService interface {
 	DeletePost(ctx context.Context, userID, postID int) error
 }
 
+type UpdatePostAuthorsService interface {
+	UpdatePostAuthors(ctx context.Context, userID, postID int, authors []model.Author) error
+}
+
+type ApplyBatchService interface {
+	ApplyBatch(ctx context.Context, userID int, ops []model.BatchOperation, atomic bool) ([]model.BatchResult, error)
+}
+
 type PostHandler struct {
 	logger *slog.Logger
 
@@ -43,6 +52,8 @@ type PostHandler struct {
 	getPostByIDService     GetPostByIDService
 	updatePostService      UpdatePostService
 	deletePostService      DeletePostService
+	updateAuthorsService   UpdatePostAuthorsService
+	applyBatchService      ApplyBatchService
 }
 
 func NewPostHandler(
@@ -51,7 +62,9 @@ func NewPostHandler(
 	getPostByFilterService GetPostByFilterService,
 	getPostByIDService GetPostByIDService,
 	updatePostService UpdatePostService,
-	deletePostService DeletePostService) *PostHandler {
+	deletePostService DeletePostService,
+	updateAuthorsService UpdatePostAuthorsService,
+	applyBatchService ApplyBatchService) *PostHandler {
 	return &PostHandler{
 		logger:                 logger,
 		createPostService:      createPostService,
@@ -59,12 +72,17 @@ func NewPostHandler(
 		getPostByIDService:     getPostByIDService,
 		updatePostService:      updatePostService,
 		deletePostService:      deletePostService,
+		updateAuthorsService:   updateAuthorsService,
+		applyBatchService:      applyBatchService,
 	}
 }
 
 type CreatePostRequest struct { // todo
-	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Title         string   `json:"title" validate:"required"`
+	Content       string   `json:"content" validate:"required"`
+	ContentFormat string   `json:"content_format" validate:"omitempty,oneof=plain markdown html"`
+	Excerpt       string   `json:"excerpt"`
+	Tags          []string `json:"tags"`
 }
 
 func (p PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
@@ -87,13 +105,22 @@ func (p PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 
 	userID := contexts.MustGetUserID(r.Context())
 
-	postID, err := p.createPostService.CreatePost(r.Context(), req.Title, req.Content, userID)
+	postID, err := p.createPostService.CreatePost(r.Context(), req.Title, req.Content, req.ContentFormat, req.Excerpt,
+		req.Tags, userID)
 	if err != nil {
+		if errors.Is(err, model.ErrTooManyTags) || errors.Is(err, model.ErrInvalidTagLen) ||
+			errors.Is(err, markup.ErrUnknownFormat) || errors.Is(err, model.ErrExcerptTooLong) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		logger.Error("cant create post", logs.Err(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(map[string]int{"post_id": postID}); err != nil {
 		logger.Error("cant encode response", logs.Err(err))
@@ -113,7 +140,20 @@ func (p PostHandler) GetPostByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	post, err := p.getPostByIDService.GetPostByID(r.Context(), id)
+	representation, ok := parseRepresentation(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	fields, err := model.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	post, err := p.getPostByIDService.GetPostByID(r.Context(), id, fields)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrNoPostWasFound):
@@ -125,7 +165,16 @@ func (p PostHandler) GetPostByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonPost, err := json.Marshal(post)
+	post.Represent(representation)
+
+	projected, err := project(post, fields)
+	if err != nil {
+		logger.Error("cant project post", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	jsonPost, err := json.Marshal(projected)
 	if err != nil {
 		logger.Error("cant marshal post", logs.Err(err))
 		w.WriteHeader(http.StatusInternalServerError)
@@ -147,11 +196,40 @@ func (p PostHandler) GetPostByFilter(w http.ResponseWriter, r *http.Request) {
 
 	filter.DateFrom, _ = time.Parse(time.RFC3339, r.URL.Query().Get("dateFrom"))
 	filter.DateTo, _ = time.Parse(time.RFC3339, r.URL.Query().Get("dateTo"))
+	filter.SortBy = r.URL.Query().Get("sort")
+
+	var err error
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err = filter.Validation(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	representation, ok := parseRepresentation(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
-	if err := filter.Validation(); err != nil {
+	fields, err := model.ParseListFields(r.URL.Query().Get("view"), r.URL.Query().Get("fields"))
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
 		return
 	}
+	filter.Fields = fields
 
 	posts, err := p.getPostByFilterService.GetPostByFilter(r.Context(), filter)
 	if err != nil {
@@ -164,15 +242,27 @@ func (p PostHandler) GetPostByFilter(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonPost, err := json.Marshal(posts)
+	projected := make([]any, len(posts))
+	for i := range posts {
+		posts[i].Represent(representation)
+
+		if projected[i], err = project(posts[i], fields); err != nil {
+			logger.Error("cant project post", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	jsonPost, err := json.Marshal(projected)
 	if err != nil {
 		logger.Error("cant marshal post", logs.Err(err))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	w.Write(jsonPost)
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	w.Write(jsonPost)
 }
 
 func (p PostHandler) UpdatePostByID(w http.ResponseWriter, r *http.Request) {
@@ -197,6 +287,11 @@ func (p PostHandler) UpdatePostByID(w http.ResponseWriter, r *http.Request) {
 
 	if err := p.updatePostService.UpdatePost(r.Context(), userID, post); err != nil {
 		switch {
+		case errors.Is(err, model.ErrTooManyTags), errors.Is(err, model.ErrInvalidTagLen),
+			errors.Is(err, markup.ErrUnknownFormat), errors.Is(err, model.ErrExcerptTooLong):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
 		case errors.Is(err, service.ErrNoPostWasFound):
 			w.WriteHeader(http.StatusNotFound)
 			return
@@ -239,3 +334,214 @@ func (p PostHandler) DeletePost(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+type UpdatePostAuthorsRequest struct {
+	Authors []struct {
+		UserID int    `json:"user_id" validate:"required,gt=0"`
+		Role   string `json:"role" validate:"required,oneof=lead contributor"`
+	} `json:"authors" validate:"required,min=1,dive"`
+}
+
+func (p PostHandler) UpdatePostAuthors(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.post.handler.update_authors.HandleHTTP"
+	logger := p.logger.With(slog.String("op", op))
+
+	postID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || postID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var req UpdatePostAuthorsRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("cant decode request", logs.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err = validator.New().Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	authors := make([]model.Author, len(req.Authors))
+	for i, author := range req.Authors {
+		authors[i] = model.Author{UserID: author.UserID, Role: author.Role}
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	if err = p.updateAuthorsService.UpdatePostAuthors(r.Context(), userID, postID, authors); err != nil {
+		switch {
+		case errors.Is(err, model.ErrNoLeadAuthor),
+			errors.Is(err, model.ErrDuplicateAuthor),
+			errors.Is(err, model.ErrUnknownAuthorRole),
+			errors.Is(err, model.ErrEmptyAuthorsList),
+			errors.Is(err, model.ErrInvalidAuthorUserID):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		case errors.Is(err, service.ErrNoPostWasFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, service.ErrUserHasNoPermission):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			logger.Error("cant update post authors", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type BatchRequest struct {
+	Atomic     bool `json:"atomic"`
+	Operations []struct {
+		Op   string             `json:"op" validate:"required,oneof=create update delete"`
+		ID   int                `json:"id" validate:"required_unless=Op create,gte=0"`
+		Post *CreatePostRequest `json:"post" validate:"required_unless=Op delete"`
+	} `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+type BatchResponse struct {
+	Results []BatchResultResponse `json:"results"`
+}
+
+type BatchResultResponse struct {
+	Status int    `json:"status"`
+	PostID int    `json:"post_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ApplyBatch creates, updates and deletes posts in one request. Every
+// operation gets the status code it would have got as a request of its own,
+// with atomic the batch is applied in full or not at all.
+func (p PostHandler) ApplyBatch(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.post.handler.batch.HandleHTTP"
+	logger := p.logger.With(slog.String("op", op))
+
+	var req BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("cant decode request", logs.Err(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := validator.New().Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	ops := make([]model.BatchOperation, len(req.Operations))
+	for i, batchOp := range req.Operations {
+		ops[i] = model.BatchOperation{Op: batchOp.Op, Post: model.Post{ID: batchOp.ID}}
+		if batchOp.Post != nil && batchOp.Op != model.BatchDelete {
+			ops[i].Post.Title, ops[i].Post.Content = batchOp.Post.Title, batchOp.Post.Content
+			ops[i].Post.ContentFormat, ops[i].Post.Excerpt = batchOp.Post.ContentFormat, batchOp.Post.Excerpt
+			ops[i].Post.Tags = batchOp.Post.Tags
+		}
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	results, err := p.applyBatchService.ApplyBatch(r.Context(), userID, ops, req.Atomic)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrEmptyBatch), errors.Is(err, model.ErrBatchTooLarge),
+			errors.Is(err, model.ErrUnknownBatchOp):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+		default:
+			logger.Error("cant apply batch", logs.Err(err))
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := BatchResponse{Results: make([]BatchResultResponse, len(results))}
+	for i, res := range results {
+		status := batchStatus(ops[i].Op, res.Err)
+		if status == http.StatusInternalServerError {
+			logger.Error("cant apply batch operation", logs.Err(res.Err), slog.Int("index", i))
+		}
+
+		resp.Results[i] = BatchResultResponse{Status: status, PostID: res.PostID}
+		switch {
+		case res.Err == nil:
+		case status == http.StatusInternalServerError:
+			resp.Results[i].Error = http.StatusText(status)
+		default:
+			resp.Results[i].Error = res.Err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+// batchStatus is the status code of an operation of a batch.
+func batchStatus(batchOp string, err error) int {
+	switch {
+	case err == nil && batchOp == model.BatchCreate:
+		return http.StatusCreated
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, model.ErrTooManyTags), errors.Is(err, model.ErrInvalidTagLen),
+		errors.Is(err, markup.ErrUnknownFormat), errors.Is(err, model.ErrExcerptTooLong):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrUserHasNoPermission):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNoPostWasFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrBatchAborted):
+		return http.StatusFailedDependency
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseRepresentation reads which form of the content the client wants,
+// "source", "html" or both when the content parameter is missing.
+func parseRepresentation(r *http.Request) (string, bool) {
+	switch representation := r.URL.Query().Get("content"); representation {
+	case "", model.RepresentationSource, model.RepresentationHTML:
+		return representation, true
+	default:
+		return "", false
+	}
+}
+
+// project keeps the selected fields of the JSON representation of the post,
+// the repository leaves the others empty.
+func project(post model.Post, fields []string) (any, error) {
+	if fields == nil {
+		return post, nil
+	}
+
+	data, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err = json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		key := model.Fields[field]
+		if value, ok := all[key]; ok {
+			projected[key] = value
+		}
+	}
+
+	return projected, nil
+}
@@ -0,0 +1,77 @@
+package model
+
+import "errors"
+
+const (
+	AuthorRoleLead        = "lead"
+	AuthorRoleContributor = "contributor"
+)
+
+var (
+	ErrNoLeadAuthor        = errors.New("byline should have exactly one lead author")
+	ErrDuplicateAuthor     = errors.New("author is listed more than once")
+	ErrUnknownAuthorRole   = errors.New("unknown author role")
+	ErrEmptyAuthorsList    = errors.New("byline should have at least one author")
+	ErrInvalidAuthorUserID = errors.New("author user id should be positive")
+)
+
+// Author is a byline entry, Position orders the byline starting from 0.
+type Author struct {
+	UserID   int
+	Role     string
+	Position int
+}
+
+// NewByline numbers the authors in the given order.
+func NewByline(authors []Author) []Author {
+	byline := make([]Author, len(authors))
+	for i, author := range authors {
+		author.Position = i
+		byline[i] = author
+	}
+	return byline
+}
+
+func ValidateByline(authors []Author) error {
+	if len(authors) == 0 {
+		return ErrEmptyAuthorsList
+	}
+
+	leads := 0
+	seen := make(map[int]struct{}, len(authors))
+
+	for _, author := range authors {
+		if author.UserID <= 0 {
+			return ErrInvalidAuthorUserID
+		}
+
+		if _, ok := seen[author.UserID]; ok {
+			return ErrDuplicateAuthor
+		}
+		seen[author.UserID] = struct{}{}
+
+		switch author.Role {
+		case AuthorRoleLead:
+			leads++
+		case AuthorRoleContributor:
+		default:
+			return ErrUnknownAuthorRole
+		}
+	}
+
+	if leads != 1 {
+		return ErrNoLeadAuthor
+	}
+
+	return nil
+}
+
+// LeadAuthorID returns the user ID of the lead author of a valid byline.
+func LeadAuthorID(authors []Author) int {
+	for _, author := range authors {
+		if author.Role == AuthorRoleLead {
+			return author.UserID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// MaxBatchSize is the most operations a batch can have.
+const MaxBatchSize = 100
+
+var (
+	ErrEmptyBatch     = errors.New("batch should have operations")
+	ErrBatchTooLarge  = fmt.Errorf("batch should have at most %d operations", MaxBatchSize)
+	ErrUnknownBatchOp = errors.New("operation should be one of create, update, delete")
+	ErrBatchAborted   = errors.New("not applied, another operation of the batch failed")
+)
+
+// BatchOperation creates, updates or deletes Post. The ID of Post selects the
+// post to update or delete, only it is used for deletes. Roles are the byline
+// roles one of which the user needs on the post, any user may apply the
+// operation when there are none.
+type BatchOperation struct {
+	Op    string
+	Post  Post
+	Roles []string
+}
+
+// BatchResult is what became of an operation. PostID is the post it applied
+// to, Err is why it was not applied.
+type BatchResult struct {
+	PostID int
+	Err    error
+}
+
+// BatchError is the error of the operation at Index that stopped a batch.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+func ValidateBatch(ops []BatchOperation) error {
+	if len(ops) == 0 {
+		return ErrEmptyBatch
+	}
+	if len(ops) > MaxBatchSize {
+		return ErrBatchTooLarge
+	}
+
+	for i, op := range ops {
+		switch op.Op {
+		case BatchCreate, BatchUpdate, BatchDelete:
+		default:
+			return &BatchError{Index: i, Err: ErrUnknownBatchOp}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("cursor is not valid")
+
+// Cursor is where a list sorted by SortBy stopped: the post with ID and its
+// sort key, CreatedAt or Reactions. The next list starts after that post.
+type Cursor struct {
+	SortBy    string    `json:"s,omitempty"`
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"c"`
+	Reactions int       `json:"r,omitempty"`
+}
+
+// NewCursor is the cursor right after the post in a list sorted by sortBy.
+func NewCursor(sortBy string, post Post, reactions int) Cursor {
+	c := Cursor{SortBy: sortBy, ID: post.ID}
+
+	switch sortBy {
+	case SortByCreatedAt:
+		c.CreatedAt = post.CreatedAt
+	case SortByReactions:
+		c.Reactions = reactions
+	}
+
+	return c
+}
+
+// String encodes the cursor as an opaque token.
+func (c Cursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCursor decodes a token of a list sorted by sortBy.
+func ParseCursor(token, sortBy string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if c.SortBy != sortBy {
+		return Cursor{}, fmt.Errorf("%w: it is of a list sorted by %q", ErrInvalidCursor, c.SortBy)
+	}
+
+	return c, nil
+}
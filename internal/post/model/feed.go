@@ -0,0 +1,9 @@
+package model
+
+// FeedFilter selects the newest posts for a feed, zero values do not filter.
+// AuthorID matches any author of the byline.
+type FeedFilter struct {
+	AuthorID int
+	Tag      string
+	Limit    int
+}
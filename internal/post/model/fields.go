@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Fields maps the names clients select fields with to the keys of the JSON
+// representation of a post.
+var Fields = map[string]string{
+	"id":             "ID",
+	"title":          "Title",
+	"content":        "Content",
+	"content_format": "ContentFormat",
+	"content_html":   "ContentHTML",
+	"excerpt":        "Excerpt",
+	"word_count":     "WordCount",
+	"reading_time":   "ReadingTime",
+	"author_id":      "AuthorID",
+	"authors":        "Authors",
+	"tags":           "Tags",
+	"media":          "Media",
+	"reactions":      "Reactions",
+	"created_at":     "CreatedAt",
+	"updated_at":     "UpdatedAt",
+}
+
+// CompactFields is what lists show on cards, everything but the content.
+var CompactFields = []string{
+	"id", "title", "excerpt", "word_count", "reading_time", "author_id", "authors", "tags", "media",
+	"created_at", "updated_at",
+}
+
+var (
+	ErrUnknownField = errors.New("unknown field")
+	ErrUnknownView  = errors.New("view should be one of full, compact")
+)
+
+// ParseFields parses a comma separated list of fields, nil means all fields.
+func ParseFields(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	fields := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if _, ok := Fields[field]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// ParseListFields parses the fields the posts of a list should have, the
+// fields or, for the compact view, CompactFields. Nil means all fields.
+func ParseListFields(view, fields string) ([]string, error) {
+	switch view {
+	case "", "full":
+	case "compact":
+		if fields == "" {
+			return CompactFields, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w, got %q", ErrUnknownView, view)
+	}
+
+	return ParseFields(fields)
+}
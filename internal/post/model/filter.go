@@ -5,18 +5,43 @@ import (
 	"time"
 )
 
+const (
+	SortByCreatedAt = "created_at"
+	SortByReactions = "reactions"
+)
+
 var (
 	ErrDateFromAfterDateTo = errors.New("DateFrom should be before DateTo")
+	ErrUnknownSort         = errors.New("unknown sort")
+	ErrInvalidPage         = errors.New("limit and offset should not be negative")
 )
 
+// Filter selects the posts of a list. Fields are the fields to load, nil
+// means all fields. Offset posts are skipped and at most Limit returned, zero
+// Limit returns all of them.
 type Filter struct {
 	DateFrom time.Time
 	DateTo   time.Time
+	SortBy   string
+	Fields   []string
+	Limit    int
+	Offset   int
 }
 
 func (f Filter) Validation() error {
 	if f.DateFrom.After(f.DateTo) {
 		return ErrDateFromAfterDateTo
 	}
+
+	switch f.SortBy {
+	case "", SortByCreatedAt, SortByReactions:
+	default:
+		return ErrUnknownSort
+	}
+
+	if f.Limit < 0 || f.Offset < 0 {
+		return ErrInvalidPage
+	}
+
 	return nil
 }
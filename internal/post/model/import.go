@@ -0,0 +1,21 @@
+package model
+
+import "errors"
+
+var ErrEmptySourceID = errors.New("source id should not be empty")
+
+// ImportedPost is a post brought over from another system. SourceID is its
+// ID there, a post is imported once per source and source ID.
+type ImportedPost struct {
+	SourceID string
+	Post
+}
+
+// ImportResult is what became of an imported post. PostID is the post it
+// was imported as, earlier when Duplicate is set. Err is why it was not
+// imported, the other fields are zero then.
+type ImportResult struct {
+	PostID    int
+	Duplicate bool
+	Err       error
+}
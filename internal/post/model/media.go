@@ -0,0 +1,24 @@
+package model
+
+// Media is a file attached to a post. Images have their dimensions, a
+// blurhash placeholder and variants once they are processed.
+type Media struct {
+	ID          int
+	URL         string
+	ContentType string
+	Size        int64
+	Width       int
+	Height      int
+	Blurhash    string
+	Variants    []MediaVariant
+}
+
+// MediaVariant is a rendition of an image, Name is the rendition it was made for.
+type MediaVariant struct {
+	Name        string
+	URL         string
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+}
@@ -2,21 +2,56 @@ package model
 
 import "time"
 
+const (
+	RepresentationSource = "source"
+	RepresentationHTML   = "html"
+)
+
+// Post is an article. Content is the source written in ContentFormat and
+// ContentHTML its sanitized rendering. WordCount, ReadingTime (in minutes) and,
+// unless the author wrote one, Excerpt are computed from the rendering.
+// AuthorID is the lead author, Authors is the full byline. Tags are normalized
+// with NormalizeTags, Media is attached in display order, Reactions holds the
+// number of readers per reaction.
 type Post struct {
-	ID        int
-	Title     string
-	Content   string
-	AuthorID  int
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            int
+	Title         string
+	Content       string `json:"Content,omitempty"`
+	ContentFormat string
+	ContentHTML   string `json:"ContentHTML,omitempty"`
+	Excerpt       string
+	// ExcerptGenerated is set when the excerpt was not written by the author.
+	ExcerptGenerated bool `json:"-"`
+	WordCount        int
+	ReadingTime      int
+	AuthorID         int
+	Authors          []Author
+	Tags             []string
+	Media            []Media
+	Reactions        map[string]int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
-func NewPost(title, content string, authorID int) Post {
+func NewPost(title, content, contentFormat string, authorID int) Post {
 	return Post{
-		Title:     title,
-		Content:   content,
-		AuthorID:  authorID,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Title:         title,
+		Content:       content,
+		ContentFormat: contentFormat,
+		AuthorID:      authorID,
+		Authors:       []Author{{UserID: authorID, Role: AuthorRoleLead}},
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+}
+
+// Represent keeps only the requested form of the content, both are kept for
+// an empty representation.
+func (p *Post) Represent(representation string) {
+	switch representation {
+	case RepresentationSource:
+		p.ContentHTML = ""
+	case RepresentationHTML:
+		p.Content = ""
 	}
 }
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+const (
+	// ExcerptLength is the length of generated excerpts, MaxExcerptLength
+	// of the ones written by authors.
+	ExcerptLength    = 280
+	MaxExcerptLength = 1000
+
+	// WordsPerMinute is the reading speed reading times are estimated with.
+	WordsPerMinute = 200
+)
+
+var ErrExcerptTooLong = errors.New("excerpt should be at most 1000 characters")
+
+func ValidateExcerpt(excerpt string) error {
+	if utf8.RuneCountInString(excerpt) > MaxExcerptLength {
+		return ErrExcerptTooLong
+	}
+	return nil
+}
+
+// ReadingTime is the number of minutes it takes to read the words, rounded up.
+func ReadingTime(words int) int {
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	MaxTags      = 10
+	MaxTagLength = 50
+)
+
+var (
+	ErrTooManyTags   = errors.New("post can have at most 10 tags")
+	ErrInvalidTagLen = errors.New("tag should be between 1 and 50 characters")
+)
+
+// NormalizeTags lowercases and trims the tags and drops empty and repeated ones.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+func ValidateTags(tags []string) error {
+	if len(tags) > MaxTags {
+		return ErrTooManyTags
+	}
+
+	for _, tag := range tags {
+		if len([]rune(tag)) > MaxTagLength {
+			return ErrInvalidTagLen
+		}
+	}
+
+	return nil
+}
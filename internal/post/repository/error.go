@@ -3,5 +3,6 @@ package repository
 import "errors"
 
 var (
-	ErrNoPostWasFound = errors.New("post not found")
+	ErrNoPostWasFound  = errors.New("post not found")
+	ErrUserIsNotAuthor = errors.New("user has no role on the byline of the post")
 )
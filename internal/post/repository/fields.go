@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"strings"
+)
+
+// postColumns are the columns of the posts table in select order, each with
+// the field it is loaded for and where it is scanned to.
+var postColumns = []struct {
+	field, column string
+	dest          func(post *model.Post) any
+}{
+	{"id", "id", func(p *model.Post) any { return &p.ID }},
+	{"title", "title", func(p *model.Post) any { return &p.Title }},
+	{"content", "content", func(p *model.Post) any { return &p.Content }},
+	{"content_format", "content_format", func(p *model.Post) any { return &p.ContentFormat }},
+	{"content_html", "content_html", func(p *model.Post) any { return &p.ContentHTML }},
+	{"excerpt", "excerpt", func(p *model.Post) any { return &p.Excerpt }},
+	{"excerpt", "excerpt_generated", func(p *model.Post) any { return &p.ExcerptGenerated }},
+	{"word_count", "word_count", func(p *model.Post) any { return &p.WordCount }},
+	{"reading_time", "reading_time", func(p *model.Post) any { return &p.ReadingTime }},
+	{"created_at", "created_at", func(p *model.Post) any { return &p.CreatedAt }},
+	{"updated_at", "updated_at", func(p *model.Post) any { return &p.UpdatedAt }},
+	{"author_id", "author_id", func(p *model.Post) any { return &p.AuthorID }},
+}
+
+// selection is what is loaded of a post for a list of fields. The ID is
+// always selected, the related rows are loaded by it.
+type selection struct {
+	fields map[string]bool
+	// columns is the select list.
+	columns string
+	indexes []int
+}
+
+// selectFields builds the selection of the fields, nil means all fields.
+func selectFields(fields []string) selection {
+	s := selection{}
+
+	if fields != nil {
+		s.fields = make(map[string]bool, len(fields)+1)
+		for _, field := range fields {
+			s.fields[field] = true
+		}
+		s.fields["id"] = true
+	}
+
+	columns := make([]string, 0, len(postColumns))
+	for i, c := range postColumns {
+		if s.has(c.field) {
+			columns = append(columns, c.column)
+			s.indexes = append(s.indexes, i)
+		}
+	}
+	s.columns = strings.Join(columns, ", ")
+
+	return s
+}
+
+// has tells if the field is selected.
+func (s selection) has(field string) bool {
+	return s.fields == nil || s.fields[field]
+}
+
+// dest lists where the selected columns of a row are scanned to.
+func (s selection) dest(post *model.Post) []any {
+	dest := make([]any, len(s.indexes))
+	for i, index := range s.indexes {
+		dest[i] = postColumns[index].dest(post)
+	}
+	return dest
+}
+
+// allFields selects every column, for reads that need the whole post.
+var allFields = selectFields(nil)
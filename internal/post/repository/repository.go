@@ -5,7 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	mediaModel "github.com/ananaslegend/news-crud/internal/media/model"
 	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/lib/pq"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type PostRepository struct {
@@ -19,39 +25,197 @@ func NewPostRepository(db *sql.DB) *PostRepository {
 func (pr PostRepository) CreatePost(ctx context.Context, post model.Post) (int, error) {
 	const op = "news-crud.internal.post.create.repository.CreatePost"
 
-	res := pr.db.QueryRowContext(ctx, `
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	postID, err := insertPost(ctx, tx, post)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return postID, nil
+}
+
+// insertPost stores the post with its byline, the lead author alone when it
+// has none, and its tags.
+func insertPost(ctx context.Context, tx *sql.Tx, post model.Post) (int, error) {
+	res := tx.QueryRowContext(ctx, `
 		insert into 
-		    posts (title, content, created_at, updated_at, author_id)
-		values ($1, $2, $3, $4, $5)
+		    posts (title, content, content_format, content_html, excerpt, excerpt_generated, word_count, reading_time,
+		           created_at, updated_at, author_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		returning id
-`, post.Title, post.Content, post.CreatedAt, post.UpdatedAt, post.AuthorID,
+`, post.Title, post.Content, post.ContentFormat, post.ContentHTML, post.Excerpt, post.ExcerptGenerated,
+		post.WordCount, post.ReadingTime, post.CreatedAt, post.UpdatedAt, post.AuthorID,
 	)
 
 	var postID int
 
-	err := res.Scan(&postID)
-	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+	if err := res.Scan(&postID); err != nil {
+		return 0, err
+	}
+
+	authors := post.Authors
+	if len(authors) == 0 {
+		authors = []model.Author{{UserID: post.AuthorID, Role: model.AuthorRoleLead}}
+	}
+
+	if err := insertAuthors(ctx, tx, postID, authors); err != nil {
+		return 0, err
+	}
+
+	if err := insertTags(ctx, tx, postID, post.Tags); err != nil {
+		return 0, err
 	}
 
 	return postID, nil
 }
 
-func (pr PostRepository) GetPostByID(ctx context.Context, id int) (model.Post, error) {
+// CreatePosts stores a batch of imported posts in one transaction, keeping
+// their timestamps. Posts already imported from the source, before or
+// earlier in the batch, are skipped and reported as duplicates. Imports of
+// the same source wait for each other.
+func (pr PostRepository) CreatePosts(ctx context.Context, source string, posts []model.ImportedPost) ([]model.ImportResult, error) {
+	const op = "news-crud.internal.post.create_batch.repository.CreatePosts"
+
+	results := make([]model.ImportResult, len(posts))
+	if len(posts) == 0 {
+		return results, nil
+	}
+
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `select pg_advisory_xact_lock(hashtext('post_sources:' || $1))`, source); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sourceIDs := make([]string, len(posts))
+	for i, post := range posts {
+		sourceIDs[i] = post.SourceID
+	}
+
+	imported, err := importedPosts(ctx, tx, source, sourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	insertSource, err := tx.PrepareContext(ctx, `
+		insert into
+		    post_sources (source, source_id, post_id)
+		values ($1, $2, $3)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer insertSource.Close()
+
+	for i, post := range posts {
+		if postID, ok := imported[post.SourceID]; ok {
+			results[i] = model.ImportResult{PostID: postID, Duplicate: true}
+			continue
+		}
+
+		postID, err := insertPost(ctx, tx, post.Post)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err = insertSource.ExecContext(ctx, source, post.SourceID, postID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		imported[post.SourceID] = postID
+		results[i] = model.ImportResult{PostID: postID}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// importedPosts returns the posts imported from the source keyed by source ID.
+func importedPosts(ctx context.Context, tx *sql.Tx, source string, sourceIDs []string) (map[string]int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		select source_id, post_id
+		from post_sources
+		where source = $1 and source_id = any($2)
+	`, source, pq.Array(sourceIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	imported := make(map[string]int, len(sourceIDs))
+	for rows.Next() {
+		var (
+			sourceID string
+			postID   int
+		)
+		if err = rows.Scan(&sourceID, &postID); err != nil {
+			return nil, err
+		}
+		imported[sourceID] = postID
+	}
+
+	return imported, rows.Err()
+}
+
+// GetPostByID loads the fields of the post, nil fields means all fields.
+func (pr PostRepository) GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error) {
 	const op = "news-crud.internal.post.get_by_id.repository.GetPostByID"
 
+	post, err := getPostByID(ctx, pr.db, id, selectFields(fields), "")
+	if err != nil {
+		if errors.Is(err, ErrNoPostWasFound) {
+			return model.Post{}, err
+		}
+
+		return model.Post{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return post, nil
+}
+
+// getPostByID loads the selection of the post with its related rows, lock is
+// appended to the select of the post row.
+func getPostByID(ctx context.Context, q querier, id int, sel selection, lock string) (model.Post, error) {
 	var post model.Post
-	err := pr.db.QueryRowContext(ctx, `
-		select id, title, content, created_at, updated_at, author_id
+	err := q.QueryRowContext(ctx, `
+		select `+sel.columns+`
 		from posts
 		where id = $1
-	`, id).Scan(&post.ID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt, &post.AuthorID)
+	`+lock, id).Scan(sel.dest(&post)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Post{}, ErrNoPostWasFound
 		}
 
-		return model.Post{}, fmt.Errorf("%s: %w", op, err)
+		return model.Post{}, err
+	}
+
+	posts := []model.Post{post}
+	if err = loadRelated(ctx, q, sel, posts); err != nil {
+		return model.Post{}, err
+	}
+	post = posts[0]
+
+	if sel.has("reactions") {
+		if post.Reactions, err = getReactions(ctx, q, post.ID); err != nil {
+			return model.Post{}, err
+		}
 	}
 
 	return post, nil
@@ -60,11 +224,15 @@ func (pr PostRepository) GetPostByID(ctx context.Context, id int) (model.Post, e
 func (pr PostRepository) GetPostByFilter(ctx context.Context, filter model.Filter) ([]model.Post, error) {
 	const op = "news-crud.internal.post.get_by_filter.repository.GetPostByFilter"
 
+	sel := selectFields(filter.Fields)
+
 	rows, err := pr.db.QueryContext(ctx, `
-		select id, title, content, created_at, updated_at 
+		select `+sel.columns+`
 		from posts
 		where created_at >= $1 and created_at <= $2
-	`, filter.DateFrom, filter.DateTo)
+	`+orderBy(filter.SortBy)+`
+		limit $3 offset $4
+	`, filter.DateFrom, filter.DateTo, limit(filter.Limit), filter.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -74,8 +242,7 @@ func (pr PostRepository) GetPostByFilter(ctx context.Context, filter model.Filte
 
 	for rows.Next() {
 		var post model.Post
-		err = rows.Scan(&post.ID, &post.Title, &post.Content, &post.CreatedAt, &post.UpdatedAt)
-		if err != nil {
+		if err = rows.Scan(sel.dest(&post)...); err != nil {
 			return nil, err
 		}
 		posts = append(posts, post)
@@ -89,39 +256,865 @@ func (pr PostRepository) GetPostByFilter(ctx context.Context, filter model.Filte
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err = loadRelated(ctx, pr.db, sel, posts); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return posts, nil
 }
 
+// exportBatchSize is how many rows ExportPosts fetches from its cursor at a
+// time.
+const exportBatchSize = 500
+
+// ExportPosts passes the posts of the filter to fn in the order of the sort,
+// ids ascending when there is none, starting after the cursor when it is set.
+// Rows are fetched from a database cursor, and the related rows loaded in the
+// same transaction over one snapshot, so memory does not grow with the
+// export. The offset of the filter skips posts after the cursor, a zero
+// DateTo means no end. An error of fn stops the export and is returned.
+func (pr PostRepository) ExportPosts(
+	ctx context.Context,
+	filter model.Filter,
+	after *model.Cursor,
+	fn func(post model.Post, cursor model.Cursor) error,
+) error {
+	const op = "news-crud.internal.post.export.repository.ExportPosts"
+
+	sel := selectFields(filter.Fields)
+	where, args := exportWhere(filter, after)
+	args = append(args, limit(filter.Limit), filter.Offset)
+
+	tx, err := pr.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `
+		declare export_posts no scroll cursor for
+		select `+sel.columns+`, created_at, reaction_count
+		from posts
+		where `+where+exportOrderBy(filter.SortBy)+`
+		limit $`+strconv.Itoa(len(args)-1)+` offset $`+strconv.Itoa(len(args)), args...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	posts := make([]model.Post, 0, exportBatchSize)
+	cursors := make([]model.Cursor, 0, exportBatchSize)
+	for {
+		posts, cursors = posts[:0], cursors[:0]
+
+		rows, err := tx.QueryContext(ctx, `fetch `+strconv.Itoa(exportBatchSize)+` from export_posts`)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for rows.Next() {
+			var (
+				post      model.Post
+				createdAt time.Time
+				reactions int
+			)
+			if err = rows.Scan(append(sel.dest(&post), &createdAt, &reactions)...); err != nil {
+				rows.Close()
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			key := post
+			key.CreatedAt = createdAt
+			posts = append(posts, post)
+			cursors = append(cursors, model.NewCursor(filter.SortBy, key, reactions))
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = loadRelated(ctx, tx, sel, posts); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for i := range posts {
+			if err = fn(posts[i], cursors[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(posts) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+// exportWhere selects the posts of the filter after the cursor, the args are
+// numbered from $1.
+func exportWhere(filter model.Filter, after *model.Cursor) (string, []any) {
+	conds := []string{"created_at >= $1"}
+	args := []any{filter.DateFrom}
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if !filter.DateTo.IsZero() {
+		conds = append(conds, "created_at <= "+arg(filter.DateTo))
+	}
+
+	if after != nil {
+		switch filter.SortBy {
+		case model.SortByCreatedAt:
+			conds = append(conds, "(created_at, id) < ("+arg(after.CreatedAt)+", "+arg(after.ID)+")")
+		case model.SortByReactions:
+			conds = append(conds, "(reaction_count, id) < ("+arg(after.Reactions)+", "+arg(after.ID)+")")
+		default:
+			conds = append(conds, "id > "+arg(after.ID))
+		}
+	}
+
+	return strings.Join(conds, " and "), args
+}
+
+// exportOrderBy is orderBy with the ids ascending when there is no sort, so
+// every export has an order to resume in.
+func exportOrderBy(sortBy string) string {
+	if sortBy == "" {
+		return " order by id"
+	}
+	return orderBy(sortBy)
+}
+
+// querier runs queries on the database or in a transaction.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// loadRelated loads the selected related rows of the posts.
+func loadRelated(ctx context.Context, q querier, sel selection, posts []model.Post) error {
+	postIDs := make([]int, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	if sel.has("authors") {
+		authors, err := getAuthors(ctx, q, postIDs...)
+		if err != nil {
+			return err
+		}
+		for i := range posts {
+			posts[i].Authors = authors[posts[i].ID]
+		}
+	}
+
+	if sel.has("tags") {
+		tags, err := getTags(ctx, q, postIDs...)
+		if err != nil {
+			return err
+		}
+		for i := range posts {
+			posts[i].Tags = tags[posts[i].ID]
+		}
+	}
+
+	if sel.has("media") {
+		media, err := getMedia(ctx, q, postIDs...)
+		if err != nil {
+			return err
+		}
+		for i := range posts {
+			posts[i].Media = media[posts[i].ID]
+		}
+	}
+
+	return nil
+}
+
+// GetPostsByAuthors loads up to limit newest posts of every author, keyed by
+// the user ID of the author. Authors are matched anywhere in the byline.
+func (pr PostRepository) GetPostsByAuthors(ctx context.Context, authorIDs []int, limit int, fields []string) (map[int][]model.Post, error) {
+	const op = "news-crud.internal.post.get_by_authors.repository.GetPostsByAuthors"
+
+	posts := make(map[int][]model.Post, len(authorIDs))
+	if len(authorIDs) == 0 {
+		return posts, nil
+	}
+
+	sel := selectFields(fields)
+
+	rows, err := pr.db.QueryContext(ctx, `
+		select a.user_id, p.*
+		from unnest($1::integer[]) as a (user_id)
+		cross join lateral (
+			select `+sel.columns+`
+			from posts
+			where exists (select 1 from post_authors pa where pa.post_id = posts.id and pa.user_id = a.user_id)
+			order by created_at desc, id desc
+			limit $2
+		) p
+	`, pq.Array(authorIDs), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	postIDs := make([]int, 0)
+
+	for rows.Next() {
+		var (
+			authorID int
+			post     model.Post
+		)
+		if err = rows.Scan(append([]any{&authorID}, sel.dest(&post)...)...); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		posts[authorID] = append(posts[authorID], post)
+		postIDs = append(postIDs, post.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if sel.has("authors") {
+		authors, err := getAuthors(ctx, pr.db, postIDs...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, p := range posts {
+			for i := range p {
+				p[i].Authors = authors[p[i].ID]
+			}
+		}
+	}
+
+	if sel.has("tags") {
+		tags, err := getTags(ctx, pr.db, postIDs...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, p := range posts {
+			for i := range p {
+				p[i].Tags = tags[p[i].ID]
+			}
+		}
+	}
+
+	if sel.has("media") {
+		media, err := getMedia(ctx, pr.db, postIDs...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, p := range posts {
+			for i := range p {
+				p[i].Media = media[p[i].ID]
+			}
+		}
+	}
+
+	return posts, nil
+}
+
+// GetFeedPosts returns the newest posts matching the filter, newest first.
+func (pr PostRepository) GetFeedPosts(ctx context.Context, filter model.FeedFilter) ([]model.Post, error) {
+	const op = "news-crud.internal.post.get_feed.repository.GetFeedPosts"
+
+	rows, err := pr.db.QueryContext(ctx, `
+		select `+allFields.columns+`
+		from posts p
+		where ($1 = 0 or exists (select 1 from post_authors a where a.post_id = p.id and a.user_id = $1))
+			and ($2 = '' or exists (select 1 from post_tags t where t.post_id = p.id and t.tag = $2))
+		order by created_at desc, id desc
+		limit $3
+	`, filter.AuthorID, filter.Tag, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	posts := make([]model.Post, 0)
+	postIDs := make([]int, 0)
+
+	for rows.Next() {
+		var post model.Post
+		if err = rows.Scan(allFields.dest(&post)...); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		posts = append(posts, post)
+		postIDs = append(postIDs, post.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tags, err := getTags(ctx, pr.db, postIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range posts {
+		posts[i].Tags = tags[posts[i].ID]
+	}
+
+	return posts, nil
+}
+
+// GetSitemapChunks reports, for every chunk of chunkSize consecutive post IDs
+// holding any post, the chunk number starting from 0 and its latest update.
+func (pr PostRepository) GetSitemapChunks(ctx context.Context, chunkSize int, fn func(chunk int, lastMod time.Time) error) error {
+	const op = "news-crud.internal.post.sitemap.repository.GetSitemapChunks"
+
+	rows, err := pr.db.QueryContext(ctx, `
+		select (id - 1) / $1 as chunk, max(updated_at)
+		from posts
+		group by chunk
+		order by chunk
+	`, chunkSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			chunk   int
+			lastMod time.Time
+		)
+		if err = rows.Scan(&chunk, &lastMod); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err = fn(chunk, lastMod); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// StreamPostsByIDRange passes the posts with IDs in [fromID, toID] to fn as
+// they are read. Only ID, Title, CreatedAt and UpdatedAt are loaded.
+func (pr PostRepository) StreamPostsByIDRange(ctx context.Context, fromID, toID int, fn func(post model.Post) error) error {
+	const op = "news-crud.internal.post.sitemap.repository.StreamPostsByIDRange"
+
+	if err := pr.stream(ctx, fn, `
+		select id, title, created_at, updated_at
+		from posts
+		where id between $1 and $2
+		order by id
+	`, fromID, toID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// StreamPostsSince passes up to limit posts created after since to fn, newest
+// first. Only ID, Title, CreatedAt and UpdatedAt are loaded.
+func (pr PostRepository) StreamPostsSince(ctx context.Context, since time.Time, limit int, fn func(post model.Post) error) error {
+	const op = "news-crud.internal.post.sitemap.repository.StreamPostsSince"
+
+	if err := pr.stream(ctx, fn, `
+		select id, title, created_at, updated_at
+		from posts
+		where created_at >= $1
+		order by created_at desc, id desc
+		limit $2
+	`, since, limit); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (pr PostRepository) stream(ctx context.Context, fn func(post model.Post) error, query string, args ...any) error {
+	rows, err := pr.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post model.Post
+		if err = rows.Scan(&post.ID, &post.Title, &post.CreatedAt, &post.UpdatedAt); err != nil {
+			return err
+		}
+
+		if err = fn(post); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// UpdatePost changes the post, its tags are replaced unless post.Tags is nil.
 func (pr PostRepository) UpdatePost(ctx context.Context, post model.Post) error {
 	const op = "news-crud.internal.post.update.repository.postgre.UpdatePost"
 
-	_, err := pr.db.ExecContext(ctx, `
-		update posts
-		set title = $1, content = $2, updated_at = $3
-		where id = $4
-	`, post.Title, post.Content, post.UpdatedAt, post.ID)
+	tx, err := pr.db.BeginTx(ctx, nil)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = updatePost(ctx, tx, post); err != nil {
+		if errors.Is(err, ErrNoPostWasFound) {
 			return ErrNoPostWasFound
 		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// updatePost stores the content of the post, and its tags unless they are nil.
+func updatePost(ctx context.Context, tx *sql.Tx, post model.Post) error {
+	res, err := tx.ExecContext(ctx, `
+		update posts
+		set title = $1, content = $2, content_format = $3, content_html = $4, excerpt = $5, excerpt_generated = $6,
+			word_count = $7, reading_time = $8, updated_at = $9
+		where id = $10
+	`, post.Title, post.Content, post.ContentFormat, post.ContentHTML, post.Excerpt, post.ExcerptGenerated,
+		post.WordCount, post.ReadingTime, post.UpdatedAt, post.ID)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrNoPostWasFound
+	}
+
+	if post.Tags != nil {
+		if _, err = tx.ExecContext(ctx, `
+			delete from post_tags
+			where post_id = $1
+		`, post.ID); err != nil {
+			return err
+		}
+
+		if err = insertTags(ctx, tx, post.ID, post.Tags); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (pr PostRepository) DeletePost(ctx context.Context, id int) error {
 	const op = "news-crud.internal.post.delete.repository.DeletePost"
 
-	if _, err := pr.db.ExecContext(ctx, `
-		delete from posts
-		where id = $1
-	`, id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = deletePost(ctx, tx, id); err != nil {
+		if errors.Is(err, ErrNoPostWasFound) {
 			return ErrNoPostWasFound
 		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ApplyBatch applies the operations of the user in order in one transaction.
+// It returns the IDs of the posts they applied to and, for updates and
+// deletes, the posts as they were before. Posts are stored as they are, the
+// caller renders them. The posts changed are locked and the user has to hold
+// one of the Roles of the operation on their byline at that point, so a
+// byline or a post changed since the caller checked them is not missed. An
+// operation that fails undoes the batch, its index is in the
+// *model.BatchError returned.
+func (pr PostRepository) ApplyBatch(ctx context.Context, userID int, ops []model.BatchOperation) ([]int, []model.Post, error) {
+	const op = "news-crud.internal.post.batch.repository.ApplyBatch"
+
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	postIDs := make([]int, len(ops))
+	befores := make([]model.Post, len(ops))
+	for i, batchOp := range ops {
+		postIDs[i] = batchOp.Post.ID
+
+		switch batchOp.Op {
+		case model.BatchCreate:
+			postIDs[i], err = insertPost(ctx, tx, batchOp.Post)
+		case model.BatchUpdate:
+			if befores[i], err = lockPost(ctx, tx, userID, batchOp); err == nil {
+				err = updatePost(ctx, tx, batchOp.Post)
+			}
+		case model.BatchDelete:
+			if befores[i], err = lockPost(ctx, tx, userID, batchOp); err == nil {
+				err = deletePost(ctx, tx, batchOp.Post.ID)
+			}
+		default:
+			err = model.ErrUnknownBatchOp
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, &model.BatchError{Index: i, Err: err})
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return postIDs, befores, nil
+}
+
+// lockPost locks the post of the operation until the end of tx, checks the
+// user holds one of the Roles of the operation on its byline and loads the
+// post as it is stored. Byline changes update the post row too, so they wait
+// for the lock.
+func lockPost(ctx context.Context, tx *sql.Tx, userID int, batchOp model.BatchOperation) (model.Post, error) {
+	post, err := getPostByID(ctx, tx, batchOp.Post.ID, allFields, " for update")
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if len(batchOp.Roles) == 0 {
+		return post, nil
+	}
+
+	var role string
+	err = tx.QueryRowContext(ctx, `
+		select role
+		from post_authors
+		where post_id = $1 and user_id = $2
+	`, post.ID, userID).Scan(&role)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.Post{}, err
+	}
+
+	if !slices.Contains(batchOp.Roles, role) {
+		return model.Post{}, ErrUserIsNotAuthor
+	}
+
+	return post, nil
+}
+
+func deletePost(ctx context.Context, tx *sql.Tx, id int) error {
+	res, err := tx.ExecContext(ctx, `
+		delete from posts
+		where id = $1
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrNoPostWasFound
+	}
+
+	return nil
+}
+
+func (pr PostRepository) GetPostAuthors(ctx context.Context, postID int) ([]model.Author, error) {
+	const op = "news-crud.internal.post.get_authors.repository.GetPostAuthors"
+
+	authors, err := getAuthors(ctx, pr.db, postID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(authors[postID]) == 0 {
+		return nil, ErrNoPostWasFound
+	}
+
+	return authors[postID], nil
+}
+
+// UpdatePostAuthors replaces the byline of the post and keeps posts.author_id
+// pointing at the lead author.
+func (pr PostRepository) UpdatePostAuthors(ctx context.Context, postID int, authors []model.Author) error {
+	const op = "news-crud.internal.post.update_authors.repository.UpdatePostAuthors"
+
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		update posts
+		set author_id = $1
+		where id = $2
+	`, model.LeadAuthorID(authors), postID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if affected == 0 {
+		return ErrNoPostWasFound
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		delete from post_authors
+		where post_id = $1
+	`, postID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = insertAuthors(ctx, tx, postID, authors); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// getAuthors loads the bylines of the posts keyed by post ID.
+func getAuthors(ctx context.Context, q querier, postIDs ...int) (map[int][]model.Author, error) {
+	authors := make(map[int][]model.Author, len(postIDs))
+	if len(postIDs) == 0 {
+		return authors, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		select post_id, user_id, role, position
+		from post_authors
+		where post_id = any($1)
+		order by post_id, position
+	`, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			postID int
+			author model.Author
+		)
+		if err = rows.Scan(&postID, &author.UserID, &author.Role, &author.Position); err != nil {
+			return nil, err
+		}
+		authors[postID] = append(authors[postID], author)
+	}
+
+	return authors, rows.Err()
+}
+
+// getTags loads the tags of the posts keyed by post ID.
+func getTags(ctx context.Context, q querier, postIDs ...int) (map[int][]string, error) {
+	tags := make(map[int][]string, len(postIDs))
+	if len(postIDs) == 0 {
+		return tags, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		select post_id, tag
+		from post_tags
+		where post_id = any($1)
+		order by post_id, tag
+	`, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			postID int
+			tag    string
+		)
+		if err = rows.Scan(&postID, &tag); err != nil {
+			return nil, err
+		}
+		tags[postID] = append(tags[postID], tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// getMedia loads the media attached to the posts keyed by post ID.
+func getMedia(ctx context.Context, q querier, postIDs ...int) (map[int][]model.Media, error) {
+	media := make(map[int][]model.Media, len(postIDs))
+	if len(postIDs) == 0 {
+		return media, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		select pm.post_id, m.id, m.storage_key, m.content_type, m.size, m.width, m.height, m.blurhash
+		from post_media pm
+		join media m on m.id = pm.media_id
+		where pm.post_id = any($1)
+		order by pm.post_id, pm.position
+	`, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mediaIDs := make([]int, 0)
+
+	for rows.Next() {
+		var (
+			postID int
+			key    string
+			m      model.Media
+		)
+		if err = rows.Scan(&postID, &m.ID, &key, &m.ContentType, &m.Size, &m.Width, &m.Height, &m.Blurhash); err != nil {
+			return nil, err
+		}
+		m.URL = mediaModel.URL(key)
+		media[postID] = append(media[postID], m)
+		mediaIDs = append(mediaIDs, m.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	variants, err := getMediaVariants(ctx, q, mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range media {
+		for i := range m {
+			m[i].Variants = variants[m[i].ID]
+		}
+	}
+
+	return media, nil
+}
+
+// getMediaVariants loads the variants of processed images, smallest first.
+func getMediaVariants(ctx context.Context, q querier, mediaIDs []int) (map[int][]model.MediaVariant, error) {
+	variants := make(map[int][]model.MediaVariant)
+	if len(mediaIDs) == 0 {
+		return variants, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		select media_id, name, storage_key, content_type, width, height, size
+		from media_variants
+		where media_id = any($1)
+		order by media_id, width, name, content_type
+	`, pq.Array(mediaIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			mediaID int
+			key     string
+			v       model.MediaVariant
+		)
+		if err = rows.Scan(&mediaID, &v.Name, &key, &v.ContentType, &v.Width, &v.Height, &v.Size); err != nil {
+			return nil, err
+		}
+		v.URL = mediaModel.URL(key)
+		variants[mediaID] = append(variants[mediaID], v)
+	}
+
+	return variants, rows.Err()
+}
+
+// getReactions loads the reaction counters of the post.
+func getReactions(ctx context.Context, q querier, postID int) (map[string]int, error) {
+	rows, err := q.QueryContext(ctx, `
+		select reaction, count
+		from post_reaction_counts
+		where post_id = $1 and count > 0
+	`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			reaction string
+			count    int
+		)
+		if err = rows.Scan(&reaction, &count); err != nil {
+			return nil, err
+		}
+		reactions[reaction] = count
+	}
+
+	return reactions, rows.Err()
+}
+
+func orderBy(sortBy string) string {
+	switch sortBy {
+	case model.SortByReactions:
+		return " order by reaction_count desc, id desc"
+	case model.SortByCreatedAt:
+		return " order by created_at desc, id desc"
+	default:
+		return ""
+	}
+}
+
+// limit is the limit parameter of a query, null for no limit.
+func limit(n int) any {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+func insertAuthors(ctx context.Context, tx *sql.Tx, postID int, authors []model.Author) error {
+	for i, author := range authors {
+		if _, err := tx.ExecContext(ctx, `
+			insert into
+			    post_authors (post_id, user_id, role, position)
+			values ($1, $2, $3, $4)
+		`, postID, author.UserID, author.Role, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func insertTags(ctx context.Context, tx *sql.Tx, postID int, tags []string) error {
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `
+			insert into
+			    post_tags (post_id, tag)
+			values ($1, $2)
+		`, postID, tag); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/migrations"
+	"github.com/ananaslegend/news-crud/pkg/migrate"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -16,7 +19,7 @@ import (
 func TestNewsStorage(t *testing.T) {
 	ctx := context.Background()
 
-	// 1. Start the postgres container and run any migrations on it
+	// 1. Start the postgres container
 	container, err := postgres.RunContainer(
 		ctx,
 		testcontainers.WithImage("docker.io/postgres:16-alpine"),
@@ -32,19 +35,6 @@ func TestNewsStorage(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// Run any migrations on the database
-	_, _, err = container.Exec(ctx, []string{"psql", "-U", "test", "-d", "test", "-c", `create table if not exists posts (
-  id serial primary key,
-  title text not null,
-  content text not null,
-  author_id integer not null,
-  created_at timestamp not null default now(),
-  updated_at timestamp not null default now()
-);`})
-	if err != nil {
-		t.Fatal(err)
-	}
-
 	// Clean up the container after the test is complete
 	t.Cleanup(func() {
 		if err := container.Terminate(ctx); err != nil {
@@ -63,6 +53,23 @@ func TestNewsStorage(t *testing.T) {
 	}
 	defer conn.Close()
 
+	// Run the migrations, revert them all and run them again so every down
+	// migration is exercised too
+	migrator, err := migrate.New(conn, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = migrator.Down(ctx, len(applied)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
 	repo := NewPostRepository(conn)
 
 	t.Run("Test inserting post", func(t *testing.T) {
@@ -85,20 +92,262 @@ func TestNewsStorage(t *testing.T) {
 
 		require.NoError(t, err)
 
-		postInserted, err := repo.GetPostByID(ctx, postID)
+		postInserted, err := repo.GetPostByID(ctx, postID, nil)
 		require.NoError(t, err)
 
 		require.Equal(t, postToInsert.Title, postInserted.Title)
 		require.Equal(t, postToInsert.Content, postInserted.Content)
 		require.Equal(t, postToInsert.AuthorID, postInserted.AuthorID)
+		require.Equal(t, []model.Author{{UserID: 1, Role: model.AuthorRoleLead, Position: 0}}, postInserted.Authors)
+	})
+
+	t.Run("Test updating post authors", func(t *testing.T) {
+		t.Cleanup(func() {
+			_, err := conn.Exec("delete from posts where true")
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		postID, err := repo.CreatePost(ctx, model.NewPost("test", "test", "plain", 1))
+		require.NoError(t, err)
+
+		byline := model.NewByline([]model.Author{
+			{UserID: 2, Role: model.AuthorRoleLead},
+			{UserID: 1, Role: model.AuthorRoleContributor},
+		})
+
+		require.NoError(t, repo.UpdatePostAuthors(ctx, postID, byline))
+
+		post, err := repo.GetPostByID(ctx, postID, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, post.AuthorID)
+		require.Equal(t, byline, post.Authors)
+
+		require.ErrorIs(t, repo.UpdatePostAuthors(ctx, postID+1, byline), ErrNoPostWasFound)
+	})
+
+	t.Run("Test importing posts", func(t *testing.T) {
+		t.Cleanup(func() {
+			_, err := conn.Exec("delete from posts where true")
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		createdAt := time.Date(2015, 3, 4, 5, 6, 7, 0, time.UTC)
+		posts := []model.ImportedPost{
+			{SourceID: "10", Post: model.Post{Title: "old", Content: "old", AuthorID: 1, Tags: []string{"archive"},
+				CreatedAt: createdAt, UpdatedAt: createdAt}},
+			{SourceID: "11", Post: model.Post{Title: "older", Content: "older", AuthorID: 2,
+				CreatedAt: createdAt, UpdatedAt: createdAt}},
+			{SourceID: "10", Post: model.Post{Title: "again", Content: "again", AuthorID: 1,
+				CreatedAt: createdAt, UpdatedAt: createdAt}},
+		}
+
+		results, err := repo.CreatePosts(ctx, "wordpress", posts)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.False(t, results[0].Duplicate)
+		require.False(t, results[1].Duplicate)
+		require.Equal(t, model.ImportResult{PostID: results[0].PostID, Duplicate: true}, results[2])
+
+		post, err := repo.GetPostByID(ctx, results[0].PostID, nil)
+		require.NoError(t, err)
+		require.True(t, createdAt.Equal(post.CreatedAt.UTC()), "the original time is kept")
+		require.Equal(t, []string{"archive"}, post.Tags)
+
+		again, err := repo.CreatePosts(ctx, "wordpress", posts[1:2])
+		require.NoError(t, err)
+		require.Equal(t, []model.ImportResult{{PostID: results[1].PostID, Duplicate: true}}, again)
+
+		other, err := repo.CreatePosts(ctx, "csv", posts[1:2])
+		require.NoError(t, err)
+		require.False(t, other[0].Duplicate, "source ids are per source")
+	})
+
+	t.Run("Test getting post fields", func(t *testing.T) {
+		t.Cleanup(func() {
+			_, err := conn.Exec("delete from posts where true")
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		post := model.NewPost("test", "test", "plain", 1)
+		post.Tags = []string{"go"}
+
+		postID, err := repo.CreatePost(ctx, post)
+		require.NoError(t, err)
+
+		got, err := repo.GetPostByID(ctx, postID, []string{"title", "tags"})
+		require.NoError(t, err)
+		require.Equal(t, model.Post{ID: postID, Title: "test", Tags: []string{"go"}}, got)
+
+		posts, err := repo.GetPostByFilter(ctx, model.Filter{
+			DateFrom: post.CreatedAt.Add(-24 * time.Hour),
+			DateTo:   post.CreatedAt.Add(24 * time.Hour),
+			Fields:   []string{"created_at"},
+		})
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		require.Equal(t, postID, posts[0].ID)
+		require.Empty(t, posts[0].Title)
+		require.Nil(t, posts[0].Authors)
+	})
+
+	t.Run("Test paging posts and getting posts by authors", func(t *testing.T) {
+		t.Cleanup(func() {
+			_, err := conn.Exec("delete from posts where true")
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		ids := make([]int, 3)
+		for i := range ids {
+			post := model.NewPost("test", "test", "plain", 1)
+			post.CreatedAt = post.CreatedAt.Add(time.Duration(i) * time.Minute)
+
+			ids[i], err = repo.CreatePost(ctx, post)
+			require.NoError(t, err)
+		}
+
+		posts, err := repo.GetPostByFilter(ctx, model.Filter{
+			DateFrom: time.Now().Add(-24 * time.Hour),
+			DateTo:   time.Now().Add(24 * time.Hour),
+			SortBy:   model.SortByCreatedAt,
+			Fields:   []string{"id"},
+			Limit:    1,
+			Offset:   1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []model.Post{{ID: ids[1]}}, posts)
+
+		byAuthor, err := repo.GetPostsByAuthors(ctx, []int{1, 2}, 2, []string{"id"})
+		require.NoError(t, err)
+		require.Equal(t, map[int][]model.Post{1: {{ID: ids[2]}, {ID: ids[1]}}}, byAuthor)
+	})
+
+	t.Run("Test exporting posts", func(t *testing.T) {
+		t.Cleanup(func() {
+			_, err := conn.Exec("delete from posts where true")
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		ids := make([]int, 3)
+		for i := range ids {
+			post := model.NewPost("test", "test", "plain", 1)
+			post.CreatedAt = post.CreatedAt.Add(time.Duration(i) * time.Minute)
+
+			ids[i], err = repo.CreatePost(ctx, post)
+			require.NoError(t, err)
+		}
+
+		export := func(filter model.Filter, after *model.Cursor) ([]int, []model.Cursor) {
+			var (
+				got     []int
+				cursors []model.Cursor
+			)
+			err := repo.ExportPosts(ctx, filter, after, func(post model.Post, cursor model.Cursor) error {
+				got = append(got, post.ID)
+				cursors = append(cursors, cursor)
+				return nil
+			})
+			require.NoError(t, err)
+			return got, cursors
+		}
+
+		filter := model.Filter{SortBy: model.SortByCreatedAt, Fields: []string{"id"}, Limit: 2}
+		got, cursors := export(filter, nil)
+		require.Equal(t, []int{ids[2], ids[1]}, got)
+
+		got, _ = export(filter, &cursors[1])
+		require.Equal(t, []int{ids[0]}, got, "the export resumes after the cursor")
+
+		got, _ = export(model.Filter{Fields: []string{"id"}}, nil)
+		require.Equal(t, ids, got, "ids ascending without a sort")
+
+		got, _ = export(model.Filter{Fields: []string{"id"}, Limit: 1, Offset: 1}, nil)
+		require.Equal(t, []int{ids[1]}, got)
+
+		stop := errors.New("stop")
+		err = repo.ExportPosts(ctx, model.Filter{}, nil, func(model.Post, model.Cursor) error { return stop })
+		require.ErrorIs(t, err, stop)
+	})
+
+	t.Run("Test applying a batch", func(t *testing.T) {
+		t.Cleanup(func() {
+			_, err := conn.Exec("delete from posts where true")
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		postID, err := repo.CreatePost(ctx, model.NewPost("test", "test", "plain", 1))
+		require.NoError(t, err)
+
+		updated := model.NewPost("updated", "updated", "plain", 1)
+		updated.ID = postID
+
+		postIDs, befores, err := repo.ApplyBatch(ctx, 1, []model.BatchOperation{
+			{Op: model.BatchCreate, Post: model.NewPost("new", "new", "plain", 1)},
+			{Op: model.BatchUpdate, Post: updated, Roles: []string{model.AuthorRoleLead}},
+		})
+		require.NoError(t, err)
+		require.Len(t, postIDs, 2)
+		require.Equal(t, postID, postIDs[1])
+		require.Equal(t, "test", befores[1].Title, "the post as it was in the transaction")
+		require.Len(t, befores[1].Authors, 1)
+
+		got, err := repo.GetPostByID(ctx, postID, []string{"title"})
+		require.NoError(t, err)
+		require.Equal(t, "updated", got.Title)
+
+		_, _, err = repo.ApplyBatch(ctx, 2, []model.BatchOperation{
+			{Op: model.BatchDelete, Post: model.Post{ID: postID}, Roles: []string{model.AuthorRoleLead}},
+		})
+		require.ErrorIs(t, err, ErrUserIsNotAuthor, "user 2 is not on the byline")
+
+		_, _, err = repo.ApplyBatch(ctx, 1, []model.BatchOperation{
+			{Op: model.BatchDelete, Post: model.Post{ID: postID}},
+			{Op: model.BatchDelete, Post: model.Post{ID: postID + 100}},
+		})
+		var batchErr *model.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, 1, batchErr.Index)
+		require.ErrorIs(t, err, ErrNoPostWasFound)
+
+		_, err = repo.GetPostByID(ctx, postID, nil)
+		require.NoError(t, err, "a failed batch is undone")
+
+		require.ErrorIs(t, repo.DeletePost(ctx, postID+100), ErrNoPostWasFound)
 	})
 
 	t.Run("Test Get post (fail case)", func(t *testing.T) {
 		invalidPostID := 228
 
-		_, err := repo.GetPostByID(ctx, invalidPostID)
+		_, err := repo.GetPostByID(ctx, invalidPostID, nil)
 
 		require.ErrorIs(t, err, ErrNoPostWasFound)
 	})
 
 }
+
+func TestSelectFields(t *testing.T) {
+	require.Equal(t, "id, title, created_at", selectFields([]string{"title", "created_at"}).columns)
+	require.Equal(t, "id, excerpt, excerpt_generated", selectFields([]string{"excerpt"}).columns)
+	require.Equal(t, "id", selectFields([]string{"tags"}).columns)
+
+	sel := selectFields([]string{"title"})
+	require.True(t, sel.has("id"))
+	require.False(t, sel.has("authors"))
+
+	var post model.Post
+	require.Equal(t, []any{&post.ID, &post.Title}, sel.dest(&post))
+
+	require.Len(t, allFields.dest(&post), len(postColumns))
+	require.True(t, allFields.has("reactions"))
+}
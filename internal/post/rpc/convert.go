@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	postv1 "github.com/ananaslegend/news-crud/pkg/api/post/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+func toProto(post model.Post) *postv1.Post {
+	p := &postv1.Post{
+		Id:            int64(post.ID),
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		ContentHtml:   post.ContentHTML,
+		Excerpt:       post.Excerpt,
+		WordCount:     int32(post.WordCount),
+		ReadingTime:   int32(post.ReadingTime),
+		AuthorId:      int64(post.AuthorID),
+		Tags:          post.Tags,
+		CreatedAt:     asTimestamp(post.CreatedAt),
+		UpdatedAt:     asTimestamp(post.UpdatedAt),
+	}
+
+	for _, author := range post.Authors {
+		p.Authors = append(p.Authors, &postv1.Author{
+			UserId:   int64(author.UserID),
+			Role:     author.Role,
+			Position: int32(author.Position),
+		})
+	}
+
+	for _, m := range post.Media {
+		media := &postv1.Media{
+			Id:          int64(m.ID),
+			Url:         m.URL,
+			ContentType: m.ContentType,
+			Size:        m.Size,
+			Width:       int32(m.Width),
+			Height:      int32(m.Height),
+			Blurhash:    m.Blurhash,
+		}
+		for _, v := range m.Variants {
+			media.Variants = append(media.Variants, &postv1.MediaVariant{
+				Name:        v.Name,
+				Url:         v.URL,
+				ContentType: v.ContentType,
+				Width:       int32(v.Width),
+				Height:      int32(v.Height),
+				Size:        v.Size,
+			})
+		}
+		p.Media = append(p.Media, media)
+	}
+
+	if len(post.Reactions) > 0 {
+		p.Reactions = make(map[string]int64, len(post.Reactions))
+		for reaction, count := range post.Reactions {
+			p.Reactions[reaction] = int64(count)
+		}
+	}
+
+	return p
+}
+
+// asTimestamp leaves out times that were not loaded.
+func asTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// asTime is the zero time for a missing timestamp, like a missing date in
+// the HTTP API.
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
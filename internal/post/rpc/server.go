@@ -0,0 +1,311 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	apiKeyModel "github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/internal/post/service"
+	postv1 "github.com/ananaslegend/news-crud/pkg/api/post/v1"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/markup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"strings"
+)
+
+type CreatePostService interface {
+	CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error)
+}
+
+type GetPostByFilterService interface {
+	GetPostByFilter(ctx context.Context, filter model.Filter) ([]model.Post, error)
+}
+
+type GetPostByIDService interface {
+	GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error)
+}
+
+type StreamPostsService interface {
+	ExportPosts(ctx context.Context, filter model.Filter, after *model.Cursor, fn func(post model.Post, cursor model.Cursor) error) error
+}
+
+type UpdatePostService interface {
+	UpdatePost(ctx context.Context, userID int, post model.Post) error
+}
+
+type DeletePostService interface {
+	DeletePost(ctx context.Context, userID, postID int) error
+}
+
+// MaxListLimit and MaxStreamLimit cap the posts of a ListPosts and of a
+// StreamPosts call, the cap is the limit of filters without one.
+const (
+	MaxListLimit   = 100
+	MaxStreamLimit = 10000
+)
+
+// PostServer serves the posts over gRPC with the same services as the HTTP
+// handlers.
+type PostServer struct {
+	postv1.UnimplementedPostServiceServer
+
+	logger *slog.Logger
+
+	createPostService      CreatePostService
+	getPostByFilterService GetPostByFilterService
+	getPostByIDService     GetPostByIDService
+	streamPostsService     StreamPostsService
+	updatePostService      UpdatePostService
+	deletePostService      DeletePostService
+}
+
+func NewPostServer(
+	logger *slog.Logger,
+	createPostService CreatePostService,
+	getPostByFilterService GetPostByFilterService,
+	getPostByIDService GetPostByIDService,
+	streamPostsService StreamPostsService,
+	updatePostService UpdatePostService,
+	deletePostService DeletePostService,
+) *PostServer {
+	return &PostServer{
+		logger:                 logger,
+		createPostService:      createPostService,
+		getPostByFilterService: getPostByFilterService,
+		getPostByIDService:     getPostByIDService,
+		streamPostsService:     streamPostsService,
+		updatePostService:      updatePostService,
+		deletePostService:      deletePostService,
+	}
+}
+
+func (s PostServer) CreatePost(ctx context.Context, req *postv1.CreatePostRequest) (*postv1.CreatePostResponse, error) {
+	const op = "news-crud.internal.post.create.rpc.CreatePost"
+
+	userID, err := writer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetTitle() == "" || req.GetContent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title and content are required")
+	}
+
+	postID, err := s.createPostService.CreatePost(ctx, req.GetTitle(), req.GetContent(), req.GetContentFormat(),
+		req.GetExcerpt(), req.GetTags(), userID)
+	if err != nil {
+		return nil, s.statusError(op, err)
+	}
+
+	return &postv1.CreatePostResponse{PostId: int64(postID)}, nil
+}
+
+func (s PostServer) GetPost(ctx context.Context, req *postv1.GetPostRequest) (*postv1.GetPostResponse, error) {
+	const op = "news-crud.internal.post.get_by_id.rpc.GetPost"
+
+	if err := reader(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "id should not be negative")
+	}
+
+	fields, err := model.ParseFields(strings.Join(req.GetFields(), ","))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err = validateRepresentation(req.GetContent()); err != nil {
+		return nil, err
+	}
+
+	post, err := s.getPostByIDService.GetPostByID(ctx, int(req.GetId()), fields)
+	if err != nil {
+		return nil, s.statusError(op, err)
+	}
+
+	post.Represent(req.GetContent())
+
+	return &postv1.GetPostResponse{Post: toProto(post)}, nil
+}
+
+func (s PostServer) ListPosts(ctx context.Context, req *postv1.ListPostsRequest) (*postv1.ListPostsResponse, error) {
+	const op = "news-crud.internal.post.get_by_filter.rpc.ListPosts"
+
+	if err := reader(ctx); err != nil {
+		return nil, err
+	}
+
+	filter, err := parseFilter(req.GetFilter(), MaxListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.getPostByFilterService.GetPostByFilter(ctx, filter)
+	if err != nil {
+		return nil, s.statusError(op, err)
+	}
+
+	res := &postv1.ListPostsResponse{Posts: make([]*postv1.Post, len(posts))}
+	for i, post := range posts {
+		post.Represent(req.GetFilter().GetContent())
+		res.Posts[i] = toProto(post)
+	}
+
+	return res, nil
+}
+
+func (s PostServer) StreamPosts(req *postv1.StreamPostsRequest, stream postv1.PostService_StreamPostsServer) error {
+	const op = "news-crud.internal.post.get_by_filter.rpc.StreamPosts"
+
+	ctx := stream.Context()
+
+	if err := reader(ctx); err != nil {
+		return err
+	}
+
+	filter, err := parseFilter(req.GetFilter(), MaxStreamLimit)
+	if err != nil {
+		return err
+	}
+
+	// posts are sent as they are read from the database cursor, not loaded
+	// up front
+	err = s.streamPostsService.ExportPosts(ctx, filter, nil, func(post model.Post, _ model.Cursor) error {
+		post.Represent(req.GetFilter().GetContent())
+		return stream.Send(&postv1.StreamPostsResponse{Post: toProto(post)})
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return s.statusError(op, err)
+	}
+
+	return nil
+}
+
+func (s PostServer) UpdatePost(ctx context.Context, req *postv1.UpdatePostRequest) (*postv1.UpdatePostResponse, error) {
+	const op = "news-crud.internal.post.update.rpc.UpdatePost"
+
+	userID, err := writer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	post := model.Post{
+		ID:            int(req.GetId()),
+		Title:         req.GetTitle(),
+		Content:       req.GetContent(),
+		ContentFormat: req.GetContentFormat(),
+		Excerpt:       req.GetExcerpt(),
+	}
+	if req.GetTags() != nil {
+		post.Tags = append([]string{}, req.GetTags().GetValues()...)
+	}
+
+	if err = s.updatePostService.UpdatePost(ctx, userID, post); err != nil {
+		return nil, s.statusError(op, err)
+	}
+
+	return &postv1.UpdatePostResponse{}, nil
+}
+
+func (s PostServer) DeletePost(ctx context.Context, req *postv1.DeletePostRequest) (*postv1.DeletePostResponse, error) {
+	const op = "news-crud.internal.post.delete.rpc.DeletePost"
+
+	userID, err := writer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.deletePostService.DeletePost(ctx, userID, int(req.GetId())); err != nil {
+		return nil, s.statusError(op, err)
+	}
+
+	return &postv1.DeletePostResponse{}, nil
+}
+
+// parseFilter checks the filter of a list call and caps its limit at
+// maxLimit.
+func parseFilter(f *postv1.PostFilter, maxLimit int) (model.Filter, error) {
+	filter := model.Filter{
+		DateFrom: asTime(f.GetDateFrom()),
+		DateTo:   asTime(f.GetDateTo()),
+		SortBy:   f.GetSort(),
+		Limit:    int(f.GetLimit()),
+		Offset:   int(f.GetOffset()),
+	}
+
+	if err := filter.Validation(); err != nil {
+		return model.Filter{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if filter.Limit == 0 || filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+
+	var err error
+	if filter.Fields, err = model.ParseFields(strings.Join(f.GetFields(), ",")); err != nil {
+		return model.Filter{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err = validateRepresentation(f.GetContent()); err != nil {
+		return model.Filter{}, err
+	}
+
+	return filter, nil
+}
+
+// statusError maps errors of the post service to gRPC status codes.
+func (s PostServer) statusError(op string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrTooManyTags), errors.Is(err, model.ErrInvalidTagLen),
+		errors.Is(err, markup.ErrUnknownFormat), errors.Is(err, model.ErrExcerptTooLong):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrNoPostWasFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrUserHasNoPermission):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		s.logger.Error("cant handle call", slog.String("op", op), logs.Err(err))
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// writer returns the user of a call that changes posts, api keys need the
+// posts:write scope for it.
+func writer(ctx context.Context) (int, error) {
+	userID, ok := contexts.GetUserID(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "credentials are required")
+	}
+
+	if !contexts.HasScope(ctx, apiKeyModel.ScopePostsWrite) {
+		return 0, status.Error(codes.PermissionDenied, "api key has no posts:write scope")
+	}
+
+	return userID, nil
+}
+
+// reader checks api keys have the posts:read scope, anyone else may read.
+func reader(ctx context.Context) error {
+	if !contexts.HasScope(ctx, apiKeyModel.ScopePostsRead) {
+		return status.Error(codes.PermissionDenied, "api key has no posts:read scope")
+	}
+
+	return nil
+}
+
+func validateRepresentation(representation string) error {
+	switch representation {
+	case "", model.RepresentationSource, model.RepresentationHTML:
+		return nil
+	default:
+		return status.Errorf(codes.InvalidArgument, "content should be one of source, html, got %q", representation)
+	}
+}
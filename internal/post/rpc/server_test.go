@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	apiKeyModel "github.com/ananaslegend/news-crud/internal/apikey/model"
+	"github.com/ananaslegend/news-crud/internal/middleware"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/internal/post/service"
+	postv1 "github.com/ananaslegend/news-crud/pkg/api/post/v1"
+	"github.com/ananaslegend/news-crud/pkg/jwt"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+const secret = "test-secret"
+
+// posts serves two posts, post 2 may only be changed by its author 7.
+type posts []model.Post
+
+func (p posts) CreatePost(_ context.Context, _, _, _, _ string, _ []string, authorID int) (int, error) {
+	return 10 + authorID, nil
+}
+
+func (p posts) GetPostByFilter(_ context.Context, filter model.Filter) ([]model.Post, error) {
+	if filter.Limit < 1 || filter.Limit > MaxListLimit {
+		return nil, errors.New("limit is not capped")
+	}
+	return p.page(filter), nil
+}
+
+func (p posts) ExportPosts(_ context.Context, filter model.Filter, _ *model.Cursor, fn func(model.Post, model.Cursor) error) error {
+	if filter.Limit < 1 || filter.Limit > MaxStreamLimit {
+		return errors.New("limit is not capped")
+	}
+
+	for _, post := range p.page(filter) {
+		if err := fn(post, model.Cursor{ID: post.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p posts) page(filter model.Filter) []model.Post {
+	page := append([]model.Post{}, p[min(filter.Offset, len(p)):]...)
+	return page[:min(filter.Limit, len(page))]
+}
+
+func (p posts) GetPostByID(_ context.Context, id int, _ []string) (model.Post, error) {
+	for _, post := range p {
+		if post.ID == id {
+			return post, nil
+		}
+	}
+	return model.Post{}, service.ErrNoPostWasFound
+}
+
+func (p posts) UpdatePost(context.Context, int, model.Post) error {
+	return errors.New("db is down")
+}
+
+func (p posts) DeletePost(_ context.Context, userID, _ int) error {
+	if userID != 7 {
+		return service.ErrUserHasNoPermission
+	}
+	return nil
+}
+
+// apiKeys knows a key with every scope, one that can only write and one
+// that can only read.
+type apiKeys struct{}
+
+func (apiKeys) Authenticate(_ context.Context, plain string) (apiKeyModel.APIKey, error) {
+	switch plain {
+	case "writer":
+		return apiKeyModel.APIKey{ID: 1, UserID: 7, Scopes: []string{apiKeyModel.ScopePostsRead, apiKeyModel.ScopePostsWrite}}, nil
+	case "publisher":
+		return apiKeyModel.APIKey{ID: 3, UserID: 7, Scopes: []string{apiKeyModel.ScopePostsWrite}}, nil
+	case "reader":
+		return apiKeyModel.APIKey{ID: 2, UserID: 7, Scopes: []string{apiKeyModel.ScopePostsRead}}, nil
+	default:
+		return apiKeyModel.APIKey{}, errors.New("unknown key")
+	}
+}
+
+func newClient(t *testing.T) postv1.PostServiceClient {
+	store := posts{
+		{ID: 1, Title: "First", Content: "# hi", ContentHTML: "<h1>hi</h1>", AuthorID: 7},
+		{ID: 2, Title: "Second", Content: "two", ContentHTML: "<p>two</p>", AuthorID: 7},
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(middleware.UnaryAuth(secret, apiKeys{})),
+		grpc.StreamInterceptor(middleware.StreamAuth(secret, apiKeys{})),
+	)
+	postv1.RegisterPostServiceServer(server, NewPostServer(slogdiscard.NewDiscardLogger(), store, store, store, store, store, store))
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return postv1.NewPostServiceClient(conn)
+}
+
+func withToken(t *testing.T, userID int) context.Context {
+	token, err := jwt.NewToken(userID, nil, time.Minute, []byte(secret))
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), middleware.AuthorizationMetadata, "Bearer "+token)
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), middleware.APIKeyMetadata, key)
+}
+
+func TestPostServer_StreamPosts(t *testing.T) {
+	client := newClient(t)
+
+	stream, err := client.StreamPosts(context.Background(), &postv1.StreamPostsRequest{
+		Filter: &postv1.PostFilter{Content: model.RepresentationHTML},
+	})
+	require.NoError(t, err)
+
+	var got []*postv1.Post
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, res.GetPost())
+	}
+
+	require.Len(t, got, 2)
+	require.Equal(t, "First", got[0].GetTitle())
+	require.Equal(t, "<h1>hi</h1>", got[0].GetContentHtml())
+	require.Empty(t, got[0].GetContent(), "only the html is sent")
+	require.Equal(t, int64(2), got[1].GetId())
+}
+
+func TestPostServer_ListPosts_Page(t *testing.T) {
+	client := newClient(t)
+
+	res, err := client.ListPosts(context.Background(), &postv1.ListPostsRequest{
+		Filter: &postv1.PostFilter{Limit: 1000},
+	})
+	require.NoError(t, err, "the limit is capped")
+	require.Len(t, res.GetPosts(), 2)
+
+	res, err = client.ListPosts(context.Background(), &postv1.ListPostsRequest{
+		Filter: &postv1.PostFilter{Limit: 1, Offset: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.GetPosts(), 1)
+	require.Equal(t, int64(2), res.GetPosts()[0].GetId())
+
+	_, err = client.ListPosts(context.Background(), &postv1.ListPostsRequest{
+		Filter: &postv1.PostFilter{Offset: -1},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPostServer_StreamPosts_InvalidFilter(t *testing.T) {
+	client := newClient(t)
+
+	stream, err := client.StreamPosts(context.Background(), &postv1.StreamPostsRequest{
+		Filter: &postv1.PostFilter{Sort: "title"},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPostServer_Auth(t *testing.T) {
+	client := newClient(t)
+	req := &postv1.CreatePostRequest{Title: "t", Content: "c"}
+
+	t.Run("Anonymous calls can not write", func(t *testing.T) {
+		_, err := client.CreatePost(context.Background(), req)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Invalid credentials are rejected", func(t *testing.T) {
+		_, err := client.CreatePost(withAPIKey("unknown"), req)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Api keys need the posts:write scope", func(t *testing.T) {
+		_, err := client.CreatePost(withAPIKey("reader"), req)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Api keys need the posts:read scope", func(t *testing.T) {
+		_, err := client.GetPost(withAPIKey("publisher"), &postv1.GetPostRequest{Id: 1})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = client.GetPost(withAPIKey("reader"), &postv1.GetPostRequest{Id: 1})
+		require.NoError(t, err)
+
+		stream, err := client.StreamPosts(withAPIKey("publisher"), &postv1.StreamPostsRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Api key with the scope writes as its user", func(t *testing.T) {
+		res, err := client.CreatePost(withAPIKey("writer"), req)
+		require.NoError(t, err)
+		require.Equal(t, int64(17), res.GetPostId())
+	})
+
+	t.Run("Token writes as its user", func(t *testing.T) {
+		res, err := client.CreatePost(withToken(t, 8), req)
+		require.NoError(t, err)
+		require.Equal(t, int64(18), res.GetPostId())
+	})
+}
+
+func TestPostServer_StatusCodes(t *testing.T) {
+	client := newClient(t)
+
+	_, err := client.GetPost(context.Background(), &postv1.GetPostRequest{Id: 9})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetPost(context.Background(), &postv1.GetPostRequest{Id: 1, Fields: []string{"nope"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.DeletePost(withToken(t, 8), &postv1.DeletePostRequest{Id: 2})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.DeletePost(withToken(t, 7), &postv1.DeletePostRequest{Id: 2})
+	require.NoError(t, err)
+
+	_, err = client.UpdatePost(withToken(t, 7), &postv1.UpdatePostRequest{Id: 2, Title: "t", Content: "c"})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, "internal error", status.Convert(err).Message(), "errors of the service are not leaked")
+}
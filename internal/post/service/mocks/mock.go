@@ -0,0 +1,578 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ananaslegend/news-crud/internal/post/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCreatePostRepository is a mock of CreatePostRepository interface.
+type MockCreatePostRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreatePostRepositoryMockRecorder
+}
+
+// MockCreatePostRepositoryMockRecorder is the mock recorder for MockCreatePostRepository.
+type MockCreatePostRepositoryMockRecorder struct {
+	mock *MockCreatePostRepository
+}
+
+// NewMockCreatePostRepository creates a new mock instance.
+func NewMockCreatePostRepository(ctrl *gomock.Controller) *MockCreatePostRepository {
+	mock := &MockCreatePostRepository{ctrl: ctrl}
+	mock.recorder = &MockCreatePostRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreatePostRepository) EXPECT() *MockCreatePostRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePost mocks base method.
+func (m *MockCreatePostRepository) CreatePost(ctx context.Context, post model.Post) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePost", ctx, post)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePost indicates an expected call of CreatePost.
+func (mr *MockCreatePostRepositoryMockRecorder) CreatePost(ctx, post any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockCreatePostRepository)(nil).CreatePost), ctx, post)
+}
+
+// MockCreatePostsRepository is a mock of CreatePostsRepository interface.
+type MockCreatePostsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreatePostsRepositoryMockRecorder
+}
+
+// MockCreatePostsRepositoryMockRecorder is the mock recorder for MockCreatePostsRepository.
+type MockCreatePostsRepositoryMockRecorder struct {
+	mock *MockCreatePostsRepository
+}
+
+// NewMockCreatePostsRepository creates a new mock instance.
+func NewMockCreatePostsRepository(ctrl *gomock.Controller) *MockCreatePostsRepository {
+	mock := &MockCreatePostsRepository{ctrl: ctrl}
+	mock.recorder = &MockCreatePostsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreatePostsRepository) EXPECT() *MockCreatePostsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePosts mocks base method.
+func (m *MockCreatePostsRepository) CreatePosts(ctx context.Context, source string, posts []model.ImportedPost) ([]model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePosts", ctx, source, posts)
+	ret0, _ := ret[0].([]model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePosts indicates an expected call of CreatePosts.
+func (mr *MockCreatePostsRepositoryMockRecorder) CreatePosts(ctx, source, posts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePosts", reflect.TypeOf((*MockCreatePostsRepository)(nil).CreatePosts), ctx, source, posts)
+}
+
+// MockDeletePostRepository is a mock of DeletePostRepository interface.
+type MockDeletePostRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletePostRepositoryMockRecorder
+}
+
+// MockDeletePostRepositoryMockRecorder is the mock recorder for MockDeletePostRepository.
+type MockDeletePostRepositoryMockRecorder struct {
+	mock *MockDeletePostRepository
+}
+
+// NewMockDeletePostRepository creates a new mock instance.
+func NewMockDeletePostRepository(ctrl *gomock.Controller) *MockDeletePostRepository {
+	mock := &MockDeletePostRepository{ctrl: ctrl}
+	mock.recorder = &MockDeletePostRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeletePostRepository) EXPECT() *MockDeletePostRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeletePost mocks base method.
+func (m *MockDeletePostRepository) DeletePost(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePost", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePost indicates an expected call of DeletePost.
+func (mr *MockDeletePostRepositoryMockRecorder) DeletePost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockDeletePostRepository)(nil).DeletePost), ctx, id)
+}
+
+// MockGetPostByIDRepository is a mock of GetPostByIDRepository interface.
+type MockGetPostByIDRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPostByIDRepositoryMockRecorder
+}
+
+// MockGetPostByIDRepositoryMockRecorder is the mock recorder for MockGetPostByIDRepository.
+type MockGetPostByIDRepositoryMockRecorder struct {
+	mock *MockGetPostByIDRepository
+}
+
+// NewMockGetPostByIDRepository creates a new mock instance.
+func NewMockGetPostByIDRepository(ctrl *gomock.Controller) *MockGetPostByIDRepository {
+	mock := &MockGetPostByIDRepository{ctrl: ctrl}
+	mock.recorder = &MockGetPostByIDRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPostByIDRepository) EXPECT() *MockGetPostByIDRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPostByID mocks base method.
+func (m *MockGetPostByIDRepository) GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostByID", ctx, id, fields)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostByID indicates an expected call of GetPostByID.
+func (mr *MockGetPostByIDRepositoryMockRecorder) GetPostByID(ctx, id, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByID", reflect.TypeOf((*MockGetPostByIDRepository)(nil).GetPostByID), ctx, id, fields)
+}
+
+// MockGetPostByFilterRepository is a mock of GetPostByFilterRepository interface.
+type MockGetPostByFilterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPostByFilterRepositoryMockRecorder
+}
+
+// MockGetPostByFilterRepositoryMockRecorder is the mock recorder for MockGetPostByFilterRepository.
+type MockGetPostByFilterRepositoryMockRecorder struct {
+	mock *MockGetPostByFilterRepository
+}
+
+// NewMockGetPostByFilterRepository creates a new mock instance.
+func NewMockGetPostByFilterRepository(ctrl *gomock.Controller) *MockGetPostByFilterRepository {
+	mock := &MockGetPostByFilterRepository{ctrl: ctrl}
+	mock.recorder = &MockGetPostByFilterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPostByFilterRepository) EXPECT() *MockGetPostByFilterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPostByFilter mocks base method.
+func (m *MockGetPostByFilterRepository) GetPostByFilter(ctx context.Context, filter model.Filter) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostByFilter indicates an expected call of GetPostByFilter.
+func (mr *MockGetPostByFilterRepositoryMockRecorder) GetPostByFilter(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByFilter", reflect.TypeOf((*MockGetPostByFilterRepository)(nil).GetPostByFilter), ctx, filter)
+}
+
+// MockExportPostsRepository is a mock of ExportPostsRepository interface.
+type MockExportPostsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportPostsRepositoryMockRecorder
+}
+
+// MockExportPostsRepositoryMockRecorder is the mock recorder for MockExportPostsRepository.
+type MockExportPostsRepositoryMockRecorder struct {
+	mock *MockExportPostsRepository
+}
+
+// NewMockExportPostsRepository creates a new mock instance.
+func NewMockExportPostsRepository(ctrl *gomock.Controller) *MockExportPostsRepository {
+	mock := &MockExportPostsRepository{ctrl: ctrl}
+	mock.recorder = &MockExportPostsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportPostsRepository) EXPECT() *MockExportPostsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExportPosts mocks base method.
+func (m *MockExportPostsRepository) ExportPosts(ctx context.Context, filter model.Filter, after *model.Cursor, fn func(model.Post, model.Cursor) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPosts", ctx, filter, after, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportPosts indicates an expected call of ExportPosts.
+func (mr *MockExportPostsRepositoryMockRecorder) ExportPosts(ctx, filter, after, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPosts", reflect.TypeOf((*MockExportPostsRepository)(nil).ExportPosts), ctx, filter, after, fn)
+}
+
+// MockUpdatePostRepository is a mock of UpdatePostRepository interface.
+type MockUpdatePostRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdatePostRepositoryMockRecorder
+}
+
+// MockUpdatePostRepositoryMockRecorder is the mock recorder for MockUpdatePostRepository.
+type MockUpdatePostRepositoryMockRecorder struct {
+	mock *MockUpdatePostRepository
+}
+
+// NewMockUpdatePostRepository creates a new mock instance.
+func NewMockUpdatePostRepository(ctrl *gomock.Controller) *MockUpdatePostRepository {
+	mock := &MockUpdatePostRepository{ctrl: ctrl}
+	mock.recorder = &MockUpdatePostRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdatePostRepository) EXPECT() *MockUpdatePostRepositoryMockRecorder {
+	return m.recorder
+}
+
+// UpdatePost mocks base method.
+func (m *MockUpdatePostRepository) UpdatePost(ctx context.Context, post model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePost", ctx, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePost indicates an expected call of UpdatePost.
+func (mr *MockUpdatePostRepositoryMockRecorder) UpdatePost(ctx, post any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockUpdatePostRepository)(nil).UpdatePost), ctx, post)
+}
+
+// MockApplyBatchRepository is a mock of ApplyBatchRepository interface.
+type MockApplyBatchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplyBatchRepositoryMockRecorder
+}
+
+// MockApplyBatchRepositoryMockRecorder is the mock recorder for MockApplyBatchRepository.
+type MockApplyBatchRepositoryMockRecorder struct {
+	mock *MockApplyBatchRepository
+}
+
+// NewMockApplyBatchRepository creates a new mock instance.
+func NewMockApplyBatchRepository(ctrl *gomock.Controller) *MockApplyBatchRepository {
+	mock := &MockApplyBatchRepository{ctrl: ctrl}
+	mock.recorder = &MockApplyBatchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplyBatchRepository) EXPECT() *MockApplyBatchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApplyBatch mocks base method.
+func (m *MockApplyBatchRepository) ApplyBatch(ctx context.Context, userID int, ops []model.BatchOperation) ([]int, []model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", ctx, userID, ops)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].([]model.Post)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockApplyBatchRepositoryMockRecorder) ApplyBatch(ctx, userID, ops any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockApplyBatchRepository)(nil).ApplyBatch), ctx, userID, ops)
+}
+
+// MockGetPostAuthorsRepository is a mock of GetPostAuthorsRepository interface.
+type MockGetPostAuthorsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetPostAuthorsRepositoryMockRecorder
+}
+
+// MockGetPostAuthorsRepositoryMockRecorder is the mock recorder for MockGetPostAuthorsRepository.
+type MockGetPostAuthorsRepositoryMockRecorder struct {
+	mock *MockGetPostAuthorsRepository
+}
+
+// NewMockGetPostAuthorsRepository creates a new mock instance.
+func NewMockGetPostAuthorsRepository(ctrl *gomock.Controller) *MockGetPostAuthorsRepository {
+	mock := &MockGetPostAuthorsRepository{ctrl: ctrl}
+	mock.recorder = &MockGetPostAuthorsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetPostAuthorsRepository) EXPECT() *MockGetPostAuthorsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPostAuthors mocks base method.
+func (m *MockGetPostAuthorsRepository) GetPostAuthors(ctx context.Context, postID int) ([]model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostAuthors", ctx, postID)
+	ret0, _ := ret[0].([]model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostAuthors indicates an expected call of GetPostAuthors.
+func (mr *MockGetPostAuthorsRepositoryMockRecorder) GetPostAuthors(ctx, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostAuthors", reflect.TypeOf((*MockGetPostAuthorsRepository)(nil).GetPostAuthors), ctx, postID)
+}
+
+// MockUpdatePostAuthorsRepository is a mock of UpdatePostAuthorsRepository interface.
+type MockUpdatePostAuthorsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpdatePostAuthorsRepositoryMockRecorder
+}
+
+// MockUpdatePostAuthorsRepositoryMockRecorder is the mock recorder for MockUpdatePostAuthorsRepository.
+type MockUpdatePostAuthorsRepositoryMockRecorder struct {
+	mock *MockUpdatePostAuthorsRepository
+}
+
+// NewMockUpdatePostAuthorsRepository creates a new mock instance.
+func NewMockUpdatePostAuthorsRepository(ctrl *gomock.Controller) *MockUpdatePostAuthorsRepository {
+	mock := &MockUpdatePostAuthorsRepository{ctrl: ctrl}
+	mock.recorder = &MockUpdatePostAuthorsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpdatePostAuthorsRepository) EXPECT() *MockUpdatePostAuthorsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// UpdatePostAuthors mocks base method.
+func (m *MockUpdatePostAuthorsRepository) UpdatePostAuthors(ctx context.Context, postID int, authors []model.Author) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostAuthors", ctx, postID, authors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePostAuthors indicates an expected call of UpdatePostAuthors.
+func (mr *MockUpdatePostAuthorsRepositoryMockRecorder) UpdatePostAuthors(ctx, postID, authors any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostAuthors", reflect.TypeOf((*MockUpdatePostAuthorsRepository)(nil).UpdatePostAuthors), ctx, postID, authors)
+}
+
+// MockUserPostUpdatePermissionService is a mock of UserPostUpdatePermissionService interface.
+type MockUserPostUpdatePermissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPostUpdatePermissionServiceMockRecorder
+}
+
+// MockUserPostUpdatePermissionServiceMockRecorder is the mock recorder for MockUserPostUpdatePermissionService.
+type MockUserPostUpdatePermissionServiceMockRecorder struct {
+	mock *MockUserPostUpdatePermissionService
+}
+
+// NewMockUserPostUpdatePermissionService creates a new mock instance.
+func NewMockUserPostUpdatePermissionService(ctrl *gomock.Controller) *MockUserPostUpdatePermissionService {
+	mock := &MockUserPostUpdatePermissionService{ctrl: ctrl}
+	mock.recorder = &MockUserPostUpdatePermissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPostUpdatePermissionService) EXPECT() *MockUserPostUpdatePermissionServiceMockRecorder {
+	return m.recorder
+}
+
+// UserCanUpdatePost mocks base method.
+func (m *MockUserPostUpdatePermissionService) UserCanUpdatePost(ctx context.Context, userID, postID int) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserCanUpdatePost", ctx, userID, postID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UserCanUpdatePost indicates an expected call of UserCanUpdatePost.
+func (mr *MockUserPostUpdatePermissionServiceMockRecorder) UserCanUpdatePost(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCanUpdatePost", reflect.TypeOf((*MockUserPostUpdatePermissionService)(nil).UserCanUpdatePost), ctx, userID, postID)
+}
+
+// MockUserPostDeletePermissionService is a mock of UserPostDeletePermissionService interface.
+type MockUserPostDeletePermissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPostDeletePermissionServiceMockRecorder
+}
+
+// MockUserPostDeletePermissionServiceMockRecorder is the mock recorder for MockUserPostDeletePermissionService.
+type MockUserPostDeletePermissionServiceMockRecorder struct {
+	mock *MockUserPostDeletePermissionService
+}
+
+// NewMockUserPostDeletePermissionService creates a new mock instance.
+func NewMockUserPostDeletePermissionService(ctrl *gomock.Controller) *MockUserPostDeletePermissionService {
+	mock := &MockUserPostDeletePermissionService{ctrl: ctrl}
+	mock.recorder = &MockUserPostDeletePermissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPostDeletePermissionService) EXPECT() *MockUserPostDeletePermissionServiceMockRecorder {
+	return m.recorder
+}
+
+// UserCanDeletePost mocks base method.
+func (m *MockUserPostDeletePermissionService) UserCanDeletePost(ctx context.Context, userID, postID int) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserCanDeletePost", ctx, userID, postID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UserCanDeletePost indicates an expected call of UserCanDeletePost.
+func (mr *MockUserPostDeletePermissionServiceMockRecorder) UserCanDeletePost(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCanDeletePost", reflect.TypeOf((*MockUserPostDeletePermissionService)(nil).UserCanDeletePost), ctx, userID, postID)
+}
+
+// MockUserPostAuthorsPermissionService is a mock of UserPostAuthorsPermissionService interface.
+type MockUserPostAuthorsPermissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserPostAuthorsPermissionServiceMockRecorder
+}
+
+// MockUserPostAuthorsPermissionServiceMockRecorder is the mock recorder for MockUserPostAuthorsPermissionService.
+type MockUserPostAuthorsPermissionServiceMockRecorder struct {
+	mock *MockUserPostAuthorsPermissionService
+}
+
+// NewMockUserPostAuthorsPermissionService creates a new mock instance.
+func NewMockUserPostAuthorsPermissionService(ctrl *gomock.Controller) *MockUserPostAuthorsPermissionService {
+	mock := &MockUserPostAuthorsPermissionService{ctrl: ctrl}
+	mock.recorder = &MockUserPostAuthorsPermissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserPostAuthorsPermissionService) EXPECT() *MockUserPostAuthorsPermissionServiceMockRecorder {
+	return m.recorder
+}
+
+// UserCanManagePostAuthors mocks base method.
+func (m *MockUserPostAuthorsPermissionService) UserCanManagePostAuthors(ctx context.Context, userID, postID int) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserCanManagePostAuthors", ctx, userID, postID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UserCanManagePostAuthors indicates an expected call of UserCanManagePostAuthors.
+func (mr *MockUserPostAuthorsPermissionServiceMockRecorder) UserCanManagePostAuthors(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCanManagePostAuthors", reflect.TypeOf((*MockUserPostAuthorsPermissionService)(nil).UserCanManagePostAuthors), ctx, userID, postID)
+}
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockAuditService) Record(ctx context.Context, actorID int, action string, postID int, before, after any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, actorID, action, postID, before, after)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditServiceMockRecorder) Record(ctx, actorID, action, postID, before, after any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditService)(nil).Record), ctx, actorID, action, postID, before, after)
+}
+
+// MockViewRecorderService is a mock of ViewRecorderService interface.
+type MockViewRecorderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockViewRecorderServiceMockRecorder
+}
+
+// MockViewRecorderServiceMockRecorder is the mock recorder for MockViewRecorderService.
+type MockViewRecorderServiceMockRecorder struct {
+	mock *MockViewRecorderService
+}
+
+// NewMockViewRecorderService creates a new mock instance.
+func NewMockViewRecorderService(ctrl *gomock.Controller) *MockViewRecorderService {
+	mock := &MockViewRecorderService{ctrl: ctrl}
+	mock.recorder = &MockViewRecorderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockViewRecorderService) EXPECT() *MockViewRecorderServiceMockRecorder {
+	return m.recorder
+}
+
+// RecordView mocks base method.
+func (m *MockViewRecorderService) RecordView(ctx context.Context, postID int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordView", ctx, postID)
+}
+
+// RecordView indicates an expected call of RecordView.
+func (mr *MockViewRecorderServiceMockRecorder) RecordView(ctx, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordView", reflect.TypeOf((*MockViewRecorderService)(nil).RecordView), ctx, postID)
+}
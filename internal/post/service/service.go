@@ -4,33 +4,58 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	auditModel "github.com/ananaslegend/news-crud/internal/audit/model"
 	"github.com/ananaslegend/news-crud/internal/post/model"
 	"github.com/ananaslegend/news-crud/internal/post/repository"
 	"github.com/ananaslegend/news-crud/pkg/logs"
+	"github.com/ananaslegend/news-crud/pkg/markup"
 	"log/slog"
+	"strings"
 	"time"
 )
 
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
 type CreatePostRepository interface {
 	CreatePost(ctx context.Context, post model.Post) (int, error)
 }
 
+type CreatePostsRepository interface {
+	CreatePosts(ctx context.Context, source string, posts []model.ImportedPost) ([]model.ImportResult, error)
+}
+
 type DeletePostRepository interface {
 	DeletePost(ctx context.Context, id int) error
 }
 
 type GetPostByIDRepository interface {
-	GetPostByID(ctx context.Context, id int) (model.Post, error)
+	GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error)
 }
 
 type GetPostByFilterRepository interface {
 	GetPostByFilter(ctx context.Context, filter model.Filter) ([]model.Post, error)
 }
 
+type ExportPostsRepository interface {
+	ExportPosts(ctx context.Context, filter model.Filter, after *model.Cursor, fn func(post model.Post, cursor model.Cursor) error) error
+}
+
 type UpdatePostRepository interface {
 	UpdatePost(ctx context.Context, post model.Post) error
 }
 
+type ApplyBatchRepository interface {
+	ApplyBatch(ctx context.Context, userID int, ops []model.BatchOperation) ([]int, []model.Post, error)
+}
+
+type GetPostAuthorsRepository interface {
+	GetPostAuthors(ctx context.Context, postID int) ([]model.Author, error)
+}
+
+type UpdatePostAuthorsRepository interface {
+	UpdatePostAuthors(ctx context.Context, postID int, authors []model.Author) error
+}
+
 type UserPostUpdatePermissionService interface {
 	UserCanUpdatePost(ctx context.Context, userID, postID int) bool
 }
@@ -39,46 +64,92 @@ type UserPostDeletePermissionService interface {
 	UserCanDeletePost(ctx context.Context, userID, postID int) bool
 }
 
+type UserPostAuthorsPermissionService interface {
+	UserCanManagePostAuthors(ctx context.Context, userID, postID int) bool
+}
+
+// AuditService records who did what to which post. Failures are reported by the
+// audit service itself and do not undo the action.
+type AuditService interface {
+	Record(ctx context.Context, actorID int, action string, postID int, before, after any) error
+}
+
+// ViewRecorderService counts reads of a post without waiting for storage.
+type ViewRecorderService interface {
+	RecordView(ctx context.Context, postID int)
+}
+
 type PostService struct {
 	logger *slog.Logger
 
 	createPostRepository   CreatePostRepository
+	createPostsRepository  CreatePostsRepository
 	postByIDRepository     GetPostByIDRepository
 	postByFilterRepository GetPostByFilterRepository
+	exportPostsRepository  ExportPostsRepository
 	updatePostRepository   UpdatePostRepository
 	deletePostRepository   DeletePostRepository
+	applyBatchRepository   ApplyBatchRepository
+
+	getPostAuthorsRepository    GetPostAuthorsRepository
+	updatePostAuthorsRepository UpdatePostAuthorsRepository
 
-	updatePermissionService UserPostUpdatePermissionService
-	deletePermissionService UserPostDeletePermissionService
+	updatePermissionService  UserPostUpdatePermissionService
+	deletePermissionService  UserPostDeletePermissionService
+	authorsPermissionService UserPostAuthorsPermissionService
+
+	auditService        AuditService
+	viewRecorderService ViewRecorderService
 }
 
 func NewPostService(
 	logger *slog.Logger,
 	createPostRepository CreatePostRepository,
+	createPostsRepository CreatePostsRepository,
 	postByIDRepository GetPostByIDRepository,
 	postByFilterRepository GetPostByFilterRepository,
+	exportPostsRepository ExportPostsRepository,
 	updatePostRepository UpdatePostRepository,
 	deletePostRepository DeletePostRepository,
+	applyBatchRepository ApplyBatchRepository,
+	getPostAuthorsRepository GetPostAuthorsRepository,
+	updatePostAuthorsRepository UpdatePostAuthorsRepository,
 	updatePermissionService UserPostUpdatePermissionService,
 	deletePermissionService UserPostDeletePermissionService,
+	authorsPermissionService UserPostAuthorsPermissionService,
+	auditService AuditService,
+	viewRecorderService ViewRecorderService,
 ) *PostService {
 	return &PostService{
-		logger:                  logger,
-		createPostRepository:    createPostRepository,
-		postByIDRepository:      postByIDRepository,
-		postByFilterRepository:  postByFilterRepository,
-		updatePostRepository:    updatePostRepository,
-		deletePostRepository:    deletePostRepository,
-		updatePermissionService: updatePermissionService,
-		deletePermissionService: deletePermissionService,
+		logger:                      logger,
+		createPostRepository:        createPostRepository,
+		createPostsRepository:       createPostsRepository,
+		postByIDRepository:          postByIDRepository,
+		postByFilterRepository:      postByFilterRepository,
+		exportPostsRepository:       exportPostsRepository,
+		updatePostRepository:        updatePostRepository,
+		deletePostRepository:        deletePostRepository,
+		applyBatchRepository:        applyBatchRepository,
+		getPostAuthorsRepository:    getPostAuthorsRepository,
+		updatePostAuthorsRepository: updatePostAuthorsRepository,
+		updatePermissionService:     updatePermissionService,
+		deletePermissionService:     deletePermissionService,
+		authorsPermissionService:    authorsPermissionService,
+		auditService:                auditService,
+		viewRecorderService:         viewRecorderService,
 	}
 }
 
-func (ps PostService) CreatePost(ctx context.Context, title, content string, authorID int) (int, error) {
+// CreatePost stores the post together with its content rendered to HTML, an
+// empty content format means plain text. Without an excerpt one is generated.
+func (ps PostService) CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error) {
 	const op = "news-crud.internal.post.create.service.CreatePost"
 	logger := ps.logger.With(slog.String("op", op))
 
-	post := model.NewPost(title, content, authorID)
+	post, err := prepareCreate(title, content, contentFormat, excerpt, tags, authorID)
+	if err != nil {
+		return 0, err
+	}
 
 	postID, err := ps.createPostRepository.CreatePost(ctx, post)
 	if err != nil {
@@ -86,13 +157,123 @@ func (ps PostService) CreatePost(ctx context.Context, title, content string, aut
 		return 0, ErrCantCretePost
 	}
 
+	post.ID = postID
+	_ = ps.auditService.Record(ctx, authorID, auditModel.ActionPostCreate, postID, nil, post)
+
 	return postID, nil
 }
 
-func (ps PostService) GetPostByID(ctx context.Context, id int) (model.Post, error) {
+// prepareCreate makes the post the author creates, rendered and summarized.
+func prepareCreate(title, content, contentFormat, excerpt string, tags []string, authorID int) (model.Post, error) {
+	if contentFormat == "" {
+		contentFormat = markup.FormatPlain
+	}
+
+	post := model.NewPost(title, content, contentFormat, authorID)
+
+	var err error
+	if post.ContentHTML, err = markup.Render(post.ContentFormat, post.Content); err != nil {
+		return model.Post{}, err
+	}
+
+	post.Excerpt = excerpt
+	if err = summarize(&post); err != nil {
+		return model.Post{}, err
+	}
+
+	post.Tags = model.NormalizeTags(tags)
+	if err = model.ValidateTags(post.Tags); err != nil {
+		return model.Post{}, err
+	}
+
+	return post, nil
+}
+
+// ImportPosts stores a batch of posts brought over from the source with
+// their own timestamps and authors, rendered and summarized like CreatePost
+// does. A post that can not be prepared gets its error in its result while
+// the others are stored, an error means none of the batch was stored.
+func (ps PostService) ImportPosts(ctx context.Context, actorID int, source string, posts []model.ImportedPost) ([]model.ImportResult, error) {
+	const op = "news-crud.internal.post.import.service.ImportPosts"
+
+	results := make([]model.ImportResult, len(posts))
+
+	valid := make([]model.ImportedPost, 0, len(posts))
+	positions := make([]int, 0, len(posts))
+	for i, post := range posts {
+		if err := prepareImport(&post); err != nil {
+			results[i].Err = err
+			continue
+		}
+		valid = append(valid, post)
+		positions = append(positions, i)
+	}
+
+	stored, err := ps.createPostsRepository.CreatePosts(ctx, source, valid)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for j, res := range stored {
+		results[positions[j]] = res
+		if res.Duplicate {
+			continue
+		}
+
+		post := valid[j].Post
+		post.ID = res.PostID
+		_ = ps.auditService.Record(ctx, actorID, auditModel.ActionPostImport, res.PostID, nil, post)
+	}
+
+	return results, nil
+}
+
+// prepareImport renders and summarizes the post, the byline defaults to the
+// lead author and missing timestamps to now.
+func prepareImport(post *model.ImportedPost) error {
+	if strings.TrimSpace(post.SourceID) == "" {
+		return model.ErrEmptySourceID
+	}
+	if post.AuthorID <= 0 {
+		return model.ErrInvalidAuthorUserID
+	}
+
+	if post.ContentFormat == "" {
+		post.ContentFormat = markup.FormatPlain
+	}
+
+	var err error
+	if post.ContentHTML, err = markup.Render(post.ContentFormat, post.Content); err != nil {
+		return err
+	}
+	if err = summarize(&post.Post); err != nil {
+		return err
+	}
+
+	post.Tags = model.NormalizeTags(post.Tags)
+	if err = model.ValidateTags(post.Tags); err != nil {
+		return err
+	}
+
+	if len(post.Authors) == 0 {
+		post.Authors = []model.Author{{UserID: post.AuthorID, Role: model.AuthorRoleLead}}
+	}
+
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = time.Now()
+	}
+	if post.UpdatedAt.Before(post.CreatedAt) {
+		post.UpdatedAt = post.CreatedAt
+	}
+
+	return nil
+}
+
+// GetPostByID loads the fields of the post, nil fields means all fields.
+func (ps PostService) GetPostByID(ctx context.Context, id int, fields []string) (model.Post, error) {
 	const op = "news-crud.internal.post.get_by_id.service.GetPostByID"
 
-	post, err := ps.postByIDRepository.GetPostByID(ctx, id)
+	post, err := ps.postByIDRepository.GetPostByID(ctx, id, fields)
 	if err != nil {
 		if errors.Is(err, repository.ErrNoPostWasFound) {
 			return model.Post{}, ErrNoPostWasFound
@@ -101,6 +282,8 @@ func (ps PostService) GetPostByID(ctx context.Context, id int) (model.Post, erro
 		return model.Post{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	ps.viewRecorderService.RecordView(ctx, id)
+
 	return post, nil
 }
 
@@ -119,30 +302,288 @@ func (ps PostService) GetPostByFilter(ctx context.Context, filter model.Filter)
 	return posts, nil
 }
 
+// ExportPosts passes the posts of the filter to fn one at a time with the
+// cursor to resume after each, see PostRepository.ExportPosts.
+func (ps PostService) ExportPosts(
+	ctx context.Context,
+	filter model.Filter,
+	after *model.Cursor,
+	fn func(post model.Post, cursor model.Cursor) error,
+) error {
+	const op = "news-crud.internal.post.export.service.ExportPosts"
+
+	if err := ps.exportPostsRepository.ExportPosts(ctx, filter, after, fn); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (ps PostService) UpdatePost(ctx context.Context, userID int, post model.Post) error {
 	const op = "news-crud.internal.post.update.service.UpdatePost"
 
-	if ok := ps.updatePermissionService.UserCanUpdatePost(ctx, userID, post.ID); !ok {
-		return ErrUserHasNoPermission
+	post, before, err := ps.prepareUpdate(ctx, userID, post)
+	if err != nil {
+		return err
 	}
 
-	post.UpdatedAt = time.Now()
+	if err = ps.updatePostRepository.UpdatePost(ctx, post); err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return ErrNoPostWasFound
+		}
 
-	if err := ps.updatePostRepository.UpdatePost(ctx, post); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostUpdate, post.ID, before, updated(before, post))
+
 	return nil
 }
 
+// prepareUpdate checks the user may update the post and renders and
+// summarizes it, before is the post as it is stored.
+func (ps PostService) prepareUpdate(ctx context.Context, userID int, post model.Post) (model.Post, model.Post, error) {
+	const op = "news-crud.internal.post.update.service.prepareUpdate"
+
+	post.Tags = model.NormalizeTags(post.Tags)
+	if err := model.ValidateTags(post.Tags); err != nil {
+		return model.Post{}, model.Post{}, err
+	}
+
+	if ok := ps.updatePermissionService.UserCanUpdatePost(ctx, userID, post.ID); !ok {
+		_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostUpdateDenied, post.ID, nil, post)
+		return model.Post{}, model.Post{}, ErrUserHasNoPermission
+	}
+
+	before, err := ps.postByIDRepository.GetPostByID(ctx, post.ID, nil)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return model.Post{}, model.Post{}, ErrNoPostWasFound
+		}
+
+		return model.Post{}, model.Post{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if post.ContentFormat == "" {
+		post.ContentFormat = before.ContentFormat
+	}
+
+	if post.ContentHTML, err = markup.Render(post.ContentFormat, post.Content); err != nil {
+		return model.Post{}, model.Post{}, err
+	}
+
+	// an unchanged generated excerpt is sent back by clients that edit the
+	// post they got, it is generated again for the new content
+	if before.ExcerptGenerated && post.Excerpt == before.Excerpt {
+		post.Excerpt = ""
+	}
+	if err = summarize(&post); err != nil {
+		return model.Post{}, model.Post{}, err
+	}
+
+	post.UpdatedAt = time.Now()
+
+	return post, before, nil
+}
+
+// updated is the stored post before with the update post applied.
+func updated(before, post model.Post) model.Post {
+	after := before
+	after.Title, after.Content, after.UpdatedAt = post.Title, post.Content, post.UpdatedAt
+	after.ContentFormat, after.ContentHTML = post.ContentFormat, post.ContentHTML
+	after.Excerpt, after.ExcerptGenerated = post.Excerpt, post.ExcerptGenerated
+	after.WordCount, after.ReadingTime = post.WordCount, post.ReadingTime
+	if post.Tags != nil {
+		after.Tags = post.Tags
+	}
+	return after
+}
+
 func (ps PostService) DeletePost(ctx context.Context, userID, postID int) error {
 	const op = "news-crud.internal.post.delete.service.DeletePost"
 
+	before, err := ps.prepareDelete(ctx, userID, postID)
+	if err != nil {
+		return err
+	}
+
+	if err = ps.deletePostRepository.DeletePost(ctx, postID); err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return ErrNoPostWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostDelete, postID, before, nil)
+
+	return nil
+}
+
+// prepareDelete checks the user may delete the post and loads it as it is
+// stored.
+func (ps PostService) prepareDelete(ctx context.Context, userID, postID int) (model.Post, error) {
+	const op = "news-crud.internal.post.delete.service.prepareDelete"
+
 	if ok := ps.deletePermissionService.UserCanDeletePost(ctx, userID, postID); !ok {
+		_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostDeleteDenied, postID, nil, nil)
+		return model.Post{}, ErrUserHasNoPermission
+	}
+
+	before, err := ps.postByIDRepository.GetPostByID(ctx, postID, nil)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return model.Post{}, ErrNoPostWasFound
+		}
+
+		return model.Post{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return before, nil
+}
+
+// ApplyBatch applies the operations of the user in order, each checked and
+// audited like a request of its own. Without atomic every operation is
+// applied on its own and gets its result. With atomic they are applied in
+// one transaction: when one fails it gets the error and the others
+// ErrBatchAborted, and none is applied. The byline roles of the user are
+// checked again in the transaction, and the audit gets the posts as they
+// were in it.
+func (ps PostService) ApplyBatch(ctx context.Context, userID int, ops []model.BatchOperation, atomic bool) ([]model.BatchResult, error) {
+	const op = "news-crud.internal.post.batch.service.ApplyBatch"
+
+	if err := model.ValidateBatch(ops); err != nil {
+		return nil, err
+	}
+
+	if !atomic {
+		results := make([]model.BatchResult, len(ops))
+		for i, batchOp := range ops {
+			results[i] = ps.applyOne(ctx, userID, batchOp)
+		}
+		return results, nil
+	}
+
+	prepared := make([]model.BatchOperation, len(ops))
+	for i, batchOp := range ops {
+		var err error
+		if prepared[i], err = ps.prepareBatchOp(ctx, userID, batchOp); err != nil {
+			return aborted(len(ops), i, err), nil
+		}
+	}
+
+	postIDs, befores, err := ps.applyBatchRepository.ApplyBatch(ctx, userID, prepared)
+	if err != nil {
+		var batchErr *model.BatchError
+		if !errors.As(err, &batchErr) {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		i := batchErr.Index
+		switch {
+		case errors.Is(batchErr.Err, repository.ErrNoPostWasFound):
+			return aborted(len(ops), i, ErrNoPostWasFound), nil
+		case errors.Is(batchErr.Err, repository.ErrUserIsNotAuthor):
+			// the byline changed since the operation was checked
+			if prepared[i].Op == model.BatchUpdate {
+				_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostUpdateDenied, prepared[i].Post.ID, nil, prepared[i].Post)
+			} else {
+				_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostDeleteDenied, prepared[i].Post.ID, nil, nil)
+			}
+			return aborted(len(ops), i, ErrUserHasNoPermission), nil
+		default:
+			return aborted(len(ops), i, fmt.Errorf("%s: %w", op, batchErr.Err)), nil
+		}
+	}
+
+	results := make([]model.BatchResult, len(ops))
+	for i, batchOp := range prepared {
+		results[i].PostID = postIDs[i]
+
+		switch batchOp.Op {
+		case model.BatchCreate:
+			batchOp.Post.ID = postIDs[i]
+			_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostCreate, postIDs[i], nil, batchOp.Post)
+		case model.BatchUpdate:
+			_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostUpdate, postIDs[i], befores[i],
+				updated(befores[i], batchOp.Post))
+		case model.BatchDelete:
+			_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostDelete, postIDs[i], befores[i], nil)
+		}
+	}
+
+	return results, nil
+}
+
+func (ps PostService) applyOne(ctx context.Context, userID int, batchOp model.BatchOperation) model.BatchResult {
+	post := batchOp.Post
+
+	switch batchOp.Op {
+	case model.BatchCreate:
+		postID, err := ps.CreatePost(ctx, post.Title, post.Content, post.ContentFormat, post.Excerpt, post.Tags, userID)
+		return model.BatchResult{PostID: postID, Err: err}
+	case model.BatchUpdate:
+		return model.BatchResult{PostID: post.ID, Err: ps.UpdatePost(ctx, userID, post)}
+	case model.BatchDelete:
+		return model.BatchResult{PostID: post.ID, Err: ps.DeletePost(ctx, userID, post.ID)}
+	default:
+		return model.BatchResult{PostID: post.ID, Err: model.ErrUnknownBatchOp}
+	}
+}
+
+// batchRoles are the byline roles the permission service asks for to update
+// and delete a post, an atomic batch checks them again in its transaction.
+var batchRoles = map[string][]string{
+	model.BatchUpdate: {model.AuthorRoleLead, model.AuthorRoleContributor},
+	model.BatchDelete: {model.AuthorRoleLead},
+}
+
+// prepareBatchOp checks and prepares the operation like CreatePost,
+// UpdatePost and DeletePost do.
+func (ps PostService) prepareBatchOp(ctx context.Context, userID int, batchOp model.BatchOperation) (model.BatchOperation, error) {
+	post := batchOp.Post
+
+	var err error
+	switch batchOp.Op {
+	case model.BatchCreate:
+		post, err = prepareCreate(post.Title, post.Content, post.ContentFormat, post.Excerpt, post.Tags, userID)
+	case model.BatchUpdate:
+		post, _, err = ps.prepareUpdate(ctx, userID, post)
+	case model.BatchDelete:
+		_, err = ps.prepareDelete(ctx, userID, post.ID)
+	default:
+		err = model.ErrUnknownBatchOp
+	}
+
+	return model.BatchOperation{Op: batchOp.Op, Post: post, Roles: batchRoles[batchOp.Op]}, err
+}
+
+// aborted is the results of a batch the operation at index stopped.
+func aborted(n, index int, err error) []model.BatchResult {
+	results := make([]model.BatchResult, n)
+	for i := range results {
+		results[i].Err = model.ErrBatchAborted
+	}
+	results[index].Err = err
+	return results
+}
+
+// UpdatePostAuthors replaces the byline, the order of authors is the byline order.
+func (ps PostService) UpdatePostAuthors(ctx context.Context, userID, postID int, authors []model.Author) error {
+	const op = "news-crud.internal.post.update_authors.service.UpdatePostAuthors"
+
+	byline := model.NewByline(authors)
+	if err := model.ValidateByline(byline); err != nil {
+		return err
+	}
+
+	if ok := ps.authorsPermissionService.UserCanManagePostAuthors(ctx, userID, postID); !ok {
+		_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostAuthorsUpdateDenied, postID, nil, byline)
 		return ErrUserHasNoPermission
 	}
 
-	if err := ps.deletePostRepository.DeletePost(ctx, postID); err != nil {
+	before, err := ps.getPostAuthorsRepository.GetPostAuthors(ctx, postID)
+	if err != nil {
 		if errors.Is(err, repository.ErrNoPostWasFound) {
 			return ErrNoPostWasFound
 		}
@@ -150,5 +591,35 @@ func (ps PostService) DeletePost(ctx context.Context, userID, postID int) error
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err = ps.updatePostAuthorsRepository.UpdatePostAuthors(ctx, postID, byline); err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return ErrNoPostWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_ = ps.auditService.Record(ctx, userID, auditModel.ActionPostAuthorsUpdate, postID, before, byline)
+
+	return nil
+}
+
+// summarize counts the words of the rendered content, estimates the reading
+// time and, when the author did not write an excerpt, generates one.
+func summarize(post *model.Post) error {
+	if err := model.ValidateExcerpt(post.Excerpt); err != nil {
+		return err
+	}
+
+	text := markup.Text(post.ContentHTML)
+
+	post.WordCount = markup.Words(text)
+	post.ReadingTime = model.ReadingTime(post.WordCount)
+
+	post.ExcerptGenerated = strings.TrimSpace(post.Excerpt) == ""
+	if post.ExcerptGenerated {
+		post.Excerpt = markup.Excerpt(text, model.ExcerptLength)
+	}
+
 	return nil
 }
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	auditModel "github.com/ananaslegend/news-crud/internal/audit/model"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/internal/post/repository"
+	mock_service "github.com/ananaslegend/news-crud/internal/post/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/markup"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+const userID = 7
+
+// batchMocks are the dependencies of PostService a batch uses.
+type batchMocks struct {
+	create           *mock_service.MockCreatePostRepository
+	byID             *mock_service.MockGetPostByIDRepository
+	update           *mock_service.MockUpdatePostRepository
+	delete           *mock_service.MockDeletePostRepository
+	batch            *mock_service.MockApplyBatchRepository
+	updatePermission *mock_service.MockUserPostUpdatePermissionService
+	deletePermission *mock_service.MockUserPostDeletePermissionService
+	audit            *mock_service.MockAuditService
+}
+
+func newBatchService(c *gomock.Controller) (*PostService, batchMocks) {
+	m := batchMocks{
+		create:           mock_service.NewMockCreatePostRepository(c),
+		byID:             mock_service.NewMockGetPostByIDRepository(c),
+		update:           mock_service.NewMockUpdatePostRepository(c),
+		delete:           mock_service.NewMockDeletePostRepository(c),
+		batch:            mock_service.NewMockApplyBatchRepository(c),
+		updatePermission: mock_service.NewMockUserPostUpdatePermissionService(c),
+		deletePermission: mock_service.NewMockUserPostDeletePermissionService(c),
+		audit:            mock_service.NewMockAuditService(c),
+	}
+
+	s := NewPostService(
+		slogdiscard.NewDiscardLogger(),
+		m.create,
+		nil,
+		m.byID,
+		nil,
+		nil,
+		m.update,
+		m.delete,
+		m.batch,
+		nil,
+		nil,
+		m.updatePermission,
+		m.deletePermission,
+		nil,
+		m.audit,
+		nil,
+	)
+	return s, m
+}
+
+func update(id int, contentFormat string) model.BatchOperation {
+	return model.BatchOperation{Op: model.BatchUpdate, Post: model.Post{
+		ID: id, Title: "t", Content: "c", ContentFormat: contentFormat,
+	}}
+}
+
+var (
+	create = model.BatchOperation{Op: model.BatchCreate, Post: model.Post{Title: "t", Content: "c"}}
+	del    = model.BatchOperation{Op: model.BatchDelete, Post: model.Post{ID: 3}}
+)
+
+func TestPostService_ApplyBatch_AtomicFails(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, m := newBatchService(c)
+	m.updatePermission.EXPECT().UserCanUpdatePost(gomock.Any(), userID, 2).Return(true)
+	m.byID.EXPECT().GetPostByID(gomock.Any(), 2, nil).Return(model.Post{ID: 2, ContentFormat: "plain"}, nil)
+
+	results, err := s.ApplyBatch(context.Background(), userID, []model.BatchOperation{
+		create, update(2, "rtf"), del,
+	}, true)
+	require.NoError(t, err)
+
+	require.Len(t, results, 3)
+	require.ErrorIs(t, results[0].Err, model.ErrBatchAborted)
+	require.ErrorIs(t, results[1].Err, markup.ErrUnknownFormat)
+	require.ErrorIs(t, results[2].Err, model.ErrBatchAborted)
+}
+
+func TestPostService_ApplyBatch_AtomicDenied(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, m := newBatchService(c)
+	m.updatePermission.EXPECT().UserCanUpdatePost(gomock.Any(), userID, 2).Return(true)
+	m.byID.EXPECT().GetPostByID(gomock.Any(), 2, nil).Return(model.Post{ID: 2, ContentFormat: "plain"}, nil)
+	m.deletePermission.EXPECT().UserCanDeletePost(gomock.Any(), userID, 3).Return(false)
+	m.audit.EXPECT().Record(gomock.Any(), userID, auditModel.ActionPostDeleteDenied, 3, nil, nil)
+	m.batch.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	results, err := s.ApplyBatch(context.Background(), userID, []model.BatchOperation{
+		update(2, ""), del,
+	}, true)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, results[0].Err, model.ErrBatchAborted)
+	require.ErrorIs(t, results[1].Err, ErrUserHasNoPermission)
+}
+
+func TestPostService_ApplyBatch_AtomicNotFound(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, m := newBatchService(c)
+	m.updatePermission.EXPECT().UserCanUpdatePost(gomock.Any(), userID, 2).Return(true)
+	m.byID.EXPECT().GetPostByID(gomock.Any(), 2, nil).Return(model.Post{ID: 2, ContentFormat: "plain"}, nil)
+	m.deletePermission.EXPECT().UserCanDeletePost(gomock.Any(), userID, 3).Return(true)
+	m.byID.EXPECT().GetPostByID(gomock.Any(), 3, nil).Return(model.Post{ID: 3}, nil)
+	m.batch.EXPECT().ApplyBatch(gomock.Any(), userID, gomock.Len(3)).Return(nil, nil,
+		fmt.Errorf("apply: %w", &model.BatchError{Index: 2, Err: repository.ErrNoPostWasFound}))
+
+	results, err := s.ApplyBatch(context.Background(), userID, []model.BatchOperation{
+		create, update(2, ""), del,
+	}, true)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, results[0].Err, model.ErrBatchAborted)
+	require.ErrorIs(t, results[1].Err, model.ErrBatchAborted)
+	require.ErrorIs(t, results[2].Err, ErrNoPostWasFound, "the handler answers 404 for it")
+}
+
+func TestPostService_ApplyBatch_AtomicBylineChanged(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, m := newBatchService(c)
+	m.deletePermission.EXPECT().UserCanDeletePost(gomock.Any(), userID, 3).Return(true)
+	m.byID.EXPECT().GetPostByID(gomock.Any(), 3, nil).Return(model.Post{ID: 3}, nil)
+	m.batch.EXPECT().ApplyBatch(gomock.Any(), userID, []model.BatchOperation{
+		{Op: model.BatchDelete, Post: model.Post{ID: 3}, Roles: []string{model.AuthorRoleLead}},
+	}).Return(nil, nil, fmt.Errorf("apply: %w", &model.BatchError{Index: 0, Err: repository.ErrUserIsNotAuthor}))
+	m.audit.EXPECT().Record(gomock.Any(), userID, auditModel.ActionPostDeleteDenied, 3, nil, nil)
+
+	results, err := s.ApplyBatch(context.Background(), userID, []model.BatchOperation{del}, true)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, results[0].Err, ErrUserHasNoPermission)
+}
+
+func TestPostService_ApplyBatch_NotAtomic(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	s, m := newBatchService(c)
+	m.create.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(10, nil)
+	m.audit.EXPECT().Record(gomock.Any(), userID, auditModel.ActionPostCreate, 10, nil, gomock.Any())
+
+	m.deletePermission.EXPECT().UserCanDeletePost(gomock.Any(), userID, 3).Return(false)
+	m.audit.EXPECT().Record(gomock.Any(), userID, auditModel.ActionPostDeleteDenied, 3, nil, nil)
+
+	m.updatePermission.EXPECT().UserCanUpdatePost(gomock.Any(), userID, 4).Return(true)
+	m.byID.EXPECT().GetPostByID(gomock.Any(), 4, nil).Return(model.Post{ID: 4, ContentFormat: "plain"}, nil)
+	m.update.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).Return(nil)
+	m.audit.EXPECT().Record(gomock.Any(), userID, auditModel.ActionPostUpdate, 4, gomock.Any(), gomock.Any())
+
+	m.batch.EXPECT().ApplyBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	results, err := s.ApplyBatch(context.Background(), userID, []model.BatchOperation{
+		create, del, update(4, ""),
+	}, false)
+	require.NoError(t, err)
+
+	require.Equal(t, []model.BatchResult{
+		{PostID: 10},
+		{PostID: 3, Err: ErrUserHasNoPermission},
+		{PostID: 4},
+	}, results)
+}
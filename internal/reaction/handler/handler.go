@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/reaction/model"
+	"github.com/ananaslegend/news-crud/internal/reaction/service"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type AddReactionService interface {
+	AddReaction(ctx context.Context, userID, postID int, reaction string) error
+}
+
+type RemoveReactionService interface {
+	RemoveReaction(ctx context.Context, userID, postID int, reaction string) error
+}
+
+type GetReactionsService interface {
+	GetReactions() model.Set
+}
+
+type ReactionHandler struct {
+	logger *slog.Logger
+
+	addReactionService    AddReactionService
+	removeReactionService RemoveReactionService
+	getReactionsService   GetReactionsService
+}
+
+func NewReactionHandler(
+	logger *slog.Logger,
+	addReactionService AddReactionService,
+	removeReactionService RemoveReactionService,
+	getReactionsService GetReactionsService,
+) *ReactionHandler {
+	return &ReactionHandler{
+		logger:                logger,
+		addReactionService:    addReactionService,
+		removeReactionService: removeReactionService,
+		getReactionsService:   getReactionsService,
+	}
+}
+
+// AddReaction puts the reaction of the user on the post, repeating it is a no-op.
+func (h ReactionHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.reaction.add.handler.AddReaction"
+	h.react(w, r, h.logger.With(slog.String("op", op)), h.addReactionService.AddReaction)
+}
+
+// RemoveReaction takes the reaction of the user back, repeating it is a no-op.
+func (h ReactionHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.reaction.remove.handler.RemoveReaction"
+	h.react(w, r, h.logger.With(slog.String("op", op)), h.removeReactionService.RemoveReaction)
+}
+
+func (h ReactionHandler) GetReactions(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.reaction.get.handler.GetReactions"
+	logger := h.logger.With(slog.String("op", op))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(h.getReactionsService.GetReactions()); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
+
+func (h ReactionHandler) react(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *slog.Logger,
+	react func(ctx context.Context, userID, postID int, reaction string) error,
+) {
+	postID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || postID < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userID := contexts.MustGetUserID(r.Context())
+
+	err = react(r.Context(), userID, postID, r.PathValue("reaction"))
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, service.ErrUnknownReaction):
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+	case errors.Is(err, service.ErrNoPostWasFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		logger.Error("cant react to post", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
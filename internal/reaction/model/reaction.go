@@ -0,0 +1,23 @@
+package model
+
+import "slices"
+
+// Like is always available, other reactions are configured.
+const Like = "like"
+
+// Set is the list of reactions readers may leave on posts.
+type Set []string
+
+func NewSet(configured []string) Set {
+	set := Set{Like}
+	for _, reaction := range configured {
+		if reaction != "" && !slices.Contains(set, reaction) {
+			set = append(set, reaction)
+		}
+	}
+	return set
+}
+
+func (s Set) Contains(reaction string) bool {
+	return slices.Contains(s, reaction)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+var (
+	ErrNoPostWasFound = errors.New("post not found")
+)
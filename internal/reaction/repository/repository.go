@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+const foreignKeyViolation = "23503"
+
+type ReactionRepository struct {
+	db *sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) *ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+// AddReaction stores the reaction of the user and bumps the counters of the post.
+// Adding a reaction twice changes nothing.
+func (rr ReactionRepository) AddReaction(ctx context.Context, postID, userID int, reaction string, createdAt time.Time) error {
+	const op = "news-crud.internal.reaction.add.repository.AddReaction"
+
+	err := rr.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			insert into
+			    post_reactions (post_id, user_id, reaction, created_at)
+			values ($1, $2, $3, $4)
+			on conflict do nothing
+		`, postID, userID, reaction, createdAt)
+		if err != nil {
+			return err
+		}
+
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			return err
+		}
+
+		return changeCounters(ctx, tx, postID, reaction, 1)
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return ErrNoPostWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RemoveReaction deletes the reaction of the user and decrements the counters
+// of the post. Removing a missing reaction changes nothing.
+func (rr ReactionRepository) RemoveReaction(ctx context.Context, postID, userID int, reaction string) error {
+	const op = "news-crud.internal.reaction.remove.repository.RemoveReaction"
+
+	err := rr.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			delete from post_reactions
+			where post_id = $1 and user_id = $2 and reaction = $3
+		`, postID, userID, reaction)
+		if err != nil {
+			return err
+		}
+
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			return err
+		}
+
+		return changeCounters(ctx, tx, postID, reaction, -1)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (rr ReactionRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := rr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func changeCounters(ctx context.Context, tx *sql.Tx, postID int, reaction string, delta int) error {
+	if _, err := tx.ExecContext(ctx, `
+		insert into
+		    post_reaction_counts (post_id, reaction, count)
+		values ($1, $2, greatest($3, 0))
+		on conflict (post_id, reaction) do update
+		set count = post_reaction_counts.count + $3
+	`, postID, reaction, delta); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		update posts
+		set reaction_count = reaction_count + $1
+		where id = $2
+	`, delta, postID)
+
+	return err
+}
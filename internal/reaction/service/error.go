@@ -0,0 +1,8 @@
+package service
+
+import "errors"
+
+var (
+	ErrUnknownReaction = errors.New("unknown reaction")
+	ErrNoPostWasFound  = errors.New("post not found")
+)
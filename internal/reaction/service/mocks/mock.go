@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAddReactionRepository is a mock of AddReactionRepository interface.
+type MockAddReactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddReactionRepositoryMockRecorder
+}
+
+// MockAddReactionRepositoryMockRecorder is the mock recorder for MockAddReactionRepository.
+type MockAddReactionRepositoryMockRecorder struct {
+	mock *MockAddReactionRepository
+}
+
+// NewMockAddReactionRepository creates a new mock instance.
+func NewMockAddReactionRepository(ctrl *gomock.Controller) *MockAddReactionRepository {
+	mock := &MockAddReactionRepository{ctrl: ctrl}
+	mock.recorder = &MockAddReactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddReactionRepository) EXPECT() *MockAddReactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddReaction mocks base method.
+func (m *MockAddReactionRepository) AddReaction(ctx context.Context, postID, userID int, reaction string, createdAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, postID, userID, reaction, createdAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockAddReactionRepositoryMockRecorder) AddReaction(ctx, postID, userID, reaction, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockAddReactionRepository)(nil).AddReaction), ctx, postID, userID, reaction, createdAt)
+}
+
+// MockRemoveReactionRepository is a mock of RemoveReactionRepository interface.
+type MockRemoveReactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRemoveReactionRepositoryMockRecorder
+}
+
+// MockRemoveReactionRepositoryMockRecorder is the mock recorder for MockRemoveReactionRepository.
+type MockRemoveReactionRepositoryMockRecorder struct {
+	mock *MockRemoveReactionRepository
+}
+
+// NewMockRemoveReactionRepository creates a new mock instance.
+func NewMockRemoveReactionRepository(ctrl *gomock.Controller) *MockRemoveReactionRepository {
+	mock := &MockRemoveReactionRepository{ctrl: ctrl}
+	mock.recorder = &MockRemoveReactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRemoveReactionRepository) EXPECT() *MockRemoveReactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// RemoveReaction mocks base method.
+func (m *MockRemoveReactionRepository) RemoveReaction(ctx context.Context, postID, userID int, reaction string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, postID, userID, reaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockRemoveReactionRepositoryMockRecorder) RemoveReaction(ctx, postID, userID, reaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockRemoveReactionRepository)(nil).RemoveReaction), ctx, postID, userID, reaction)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/reaction/model"
+	"github.com/ananaslegend/news-crud/internal/reaction/repository"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type AddReactionRepository interface {
+	AddReaction(ctx context.Context, postID, userID int, reaction string, createdAt time.Time) error
+}
+
+type RemoveReactionRepository interface {
+	RemoveReaction(ctx context.Context, postID, userID int, reaction string) error
+}
+
+type ReactionService struct {
+	reactions model.Set
+
+	addReactionRepository    AddReactionRepository
+	removeReactionRepository RemoveReactionRepository
+}
+
+func NewReactionService(
+	reactions model.Set,
+	addReactionRepository AddReactionRepository,
+	removeReactionRepository RemoveReactionRepository,
+) *ReactionService {
+	return &ReactionService{
+		reactions:                reactions,
+		addReactionRepository:    addReactionRepository,
+		removeReactionRepository: removeReactionRepository,
+	}
+}
+
+func (s ReactionService) AddReaction(ctx context.Context, userID, postID int, reaction string) error {
+	const op = "news-crud.internal.reaction.add.service.AddReaction"
+
+	if !s.reactions.Contains(reaction) {
+		return ErrUnknownReaction
+	}
+
+	if err := s.addReactionRepository.AddReaction(ctx, postID, userID, reaction, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNoPostWasFound) {
+			return ErrNoPostWasFound
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s ReactionService) RemoveReaction(ctx context.Context, userID, postID int, reaction string) error {
+	const op = "news-crud.internal.reaction.remove.service.RemoveReaction"
+
+	if !s.reactions.Contains(reaction) {
+		return ErrUnknownReaction
+	}
+
+	if err := s.removeReactionRepository.RemoveReaction(ctx, postID, userID, reaction); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s ReactionService) GetReactions() model.Set {
+	return s.reactions
+}
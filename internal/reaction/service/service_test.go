@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/reaction/model"
+	"github.com/ananaslegend/news-crud/internal/reaction/repository"
+	mock_service "github.com/ananaslegend/news-crud/internal/reaction/service/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestNewSet(t *testing.T) {
+	set := model.NewSet([]string{"😂", "", model.Like, "😂", "😡"})
+
+	require.Equal(t, model.Set{model.Like, "😂", "😡"}, set)
+	require.True(t, set.Contains(model.Like))
+	require.False(t, set.Contains("👎"))
+}
+
+func TestReactionService_AddReaction(t *testing.T) {
+	type mockBehavior func(add *mock_service.MockAddReactionRepository)
+
+	tests := []struct {
+		name         string
+		reaction     string
+		mockBehavior mockBehavior
+		wantErr      error
+	}{
+		{
+			name:     "Like is always available",
+			reaction: model.Like,
+			mockBehavior: func(add *mock_service.MockAddReactionRepository) {
+				add.EXPECT().AddReaction(gomock.Any(), 2, 1, model.Like, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "Configured reaction",
+			reaction: "😂",
+			mockBehavior: func(add *mock_service.MockAddReactionRepository) {
+				add.EXPECT().AddReaction(gomock.Any(), 2, 1, "😂", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:         "Unknown reaction",
+			reaction:     "👎",
+			mockBehavior: func(add *mock_service.MockAddReactionRepository) {},
+			wantErr:      ErrUnknownReaction,
+		},
+		{
+			name:     "Post does not exist",
+			reaction: model.Like,
+			mockBehavior: func(add *mock_service.MockAddReactionRepository) {
+				add.EXPECT().AddReaction(gomock.Any(), 2, 1, model.Like, gomock.Any()).
+					Return(repository.ErrNoPostWasFound)
+			},
+			wantErr: ErrNoPostWasFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			add := mock_service.NewMockAddReactionRepository(ctrl)
+			remove := mock_service.NewMockRemoveReactionRepository(ctrl)
+			tt.mockBehavior(add)
+
+			srv := NewReactionService(model.NewSet([]string{"😂"}), add, remove)
+
+			err := srv.AddReaction(context.Background(), 1, 2, tt.reaction)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReactionService_RemoveReaction_UnknownReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	srv := NewReactionService(
+		model.NewSet(nil),
+		mock_service.NewMockAddReactionRepository(ctrl),
+		mock_service.NewMockRemoveReactionRepository(ctrl),
+	)
+
+	require.ErrorIs(t, srv.RemoveReaction(context.Background(), 1, 2, "😂"), ErrUnknownReaction)
+}
@@ -0,0 +1,75 @@
+package model
+
+import "github.com/ananaslegend/news-crud/pkg/markup"
+
+// User is a user to seed together with the posts it writes. Users are told
+// apart by email, so seeding twice creates nothing new.
+type User struct {
+	Email string
+	Name  string
+	Posts []Post
+}
+
+type Post struct {
+	Title         string
+	Content       string
+	ContentFormat string
+	Tags          []string
+}
+
+// Result counts what a seed created.
+type Result struct {
+	Users int
+	Posts int
+}
+
+// Fixtures is the sample data of a development database.
+var Fixtures = []User{
+	{
+		Email: "editor@news.local",
+		Name:  "Erin Editor",
+		Posts: []Post{
+			{
+				Title:         "City council approves new cycling lanes",
+				ContentFormat: markup.FormatMarkdown,
+				Content: "The city council voted **7 to 2** on Tuesday to build 12 km of protected cycling lanes.\n\n" +
+					"Construction starts in spring and should be finished by the end of the year.",
+				Tags: []string{"city", "transport"},
+			},
+			{
+				Title:         "Library extends its opening hours",
+				ContentFormat: markup.FormatPlain,
+				Content:       "From next month the central library stays open until 10 pm on weekdays.",
+				Tags:          []string{"city", "culture"},
+			},
+		},
+	},
+	{
+		Email: "reporter@news.local",
+		Name:  "Riley Reporter",
+		Posts: []Post{
+			{
+				Title:         "Local team wins the regional final",
+				ContentFormat: markup.FormatMarkdown,
+				Content: "A late goal settled the final in front of a sold out stadium.\n\n" +
+					"## What comes next\n\nThe team plays in the national cup in autumn.",
+				Tags: []string{"sport"},
+			},
+			{
+				Title:         "Heat wave expected this weekend",
+				ContentFormat: markup.FormatHTML,
+				Content:       "<p>Temperatures may reach <strong>35 °C</strong>. Drink water and avoid the midday sun.</p>",
+				Tags:          []string{"weather"},
+			},
+			{
+				Title:         "New bakery opens in the old town",
+				ContentFormat: markup.FormatPlain,
+				Content:       "The bakery on Market Street opened on Saturday with a queue around the block.",
+			},
+		},
+	},
+	{
+		Email: "reader@news.local",
+		Name:  "Rowan Reader",
+	},
+}
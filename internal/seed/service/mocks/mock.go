@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEnsureUserRepository is a mock of EnsureUserRepository interface.
+type MockEnsureUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnsureUserRepositoryMockRecorder
+}
+
+// MockEnsureUserRepositoryMockRecorder is the mock recorder for MockEnsureUserRepository.
+type MockEnsureUserRepositoryMockRecorder struct {
+	mock *MockEnsureUserRepository
+}
+
+// NewMockEnsureUserRepository creates a new mock instance.
+func NewMockEnsureUserRepository(ctrl *gomock.Controller) *MockEnsureUserRepository {
+	mock := &MockEnsureUserRepository{ctrl: ctrl}
+	mock.recorder = &MockEnsureUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEnsureUserRepository) EXPECT() *MockEnsureUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// EnsureUser mocks base method.
+func (m *MockEnsureUserRepository) EnsureUser(ctx context.Context, email, name string) (int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureUser", ctx, email, name)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EnsureUser indicates an expected call of EnsureUser.
+func (mr *MockEnsureUserRepositoryMockRecorder) EnsureUser(ctx, email, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureUser", reflect.TypeOf((*MockEnsureUserRepository)(nil).EnsureUser), ctx, email, name)
+}
+
+// MockCreatePostService is a mock of CreatePostService interface.
+type MockCreatePostService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreatePostServiceMockRecorder
+}
+
+// MockCreatePostServiceMockRecorder is the mock recorder for MockCreatePostService.
+type MockCreatePostServiceMockRecorder struct {
+	mock *MockCreatePostService
+}
+
+// NewMockCreatePostService creates a new mock instance.
+func NewMockCreatePostService(ctrl *gomock.Controller) *MockCreatePostService {
+	mock := &MockCreatePostService{ctrl: ctrl}
+	mock.recorder = &MockCreatePostServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreatePostService) EXPECT() *MockCreatePostServiceMockRecorder {
+	return m.recorder
+}
+
+// CreatePost mocks base method.
+func (m *MockCreatePostService) CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePost", ctx, title, content, contentFormat, excerpt, tags, authorID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePost indicates an expected call of CreatePost.
+func (mr *MockCreatePostServiceMockRecorder) CreatePost(ctx, title, content, contentFormat, excerpt, tags, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockCreatePostService)(nil).CreatePost), ctx, title, content, contentFormat, excerpt, tags, authorID)
+}
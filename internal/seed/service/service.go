@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/seed/model"
+	"log/slog"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type EnsureUserRepository interface {
+	EnsureUser(ctx context.Context, email, name string) (id int, created bool, err error)
+}
+
+type CreatePostService interface {
+	CreatePost(ctx context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error)
+}
+
+type SeedService struct {
+	logger *slog.Logger
+
+	ensureUserRepository EnsureUserRepository
+	createPostService    CreatePostService
+}
+
+func NewSeedService(
+	logger *slog.Logger,
+	ensureUserRepository EnsureUserRepository,
+	createPostService CreatePostService,
+) *SeedService {
+	return &SeedService{
+		logger:               logger,
+		ensureUserRepository: ensureUserRepository,
+		createPostService:    createPostService,
+	}
+}
+
+// Seed creates the users and the posts they write. The posts of users that
+// already exist are not created again, so seeding is safe to repeat.
+func (s SeedService) Seed(ctx context.Context, users []model.User) (model.Result, error) {
+	const op = "news-crud.internal.seed.seed.service.Seed"
+	logger := s.logger.With(slog.String("op", op))
+
+	var res model.Result
+	for _, user := range users {
+		userID, created, err := s.ensureUserRepository.EnsureUser(ctx, user.Email, user.Name)
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", op, err)
+		}
+		if !created {
+			logger.Info("user already exists", slog.String("email", user.Email), slog.Int("user_id", userID))
+			continue
+		}
+		res.Users++
+
+		for _, post := range user.Posts {
+			if _, err = s.createPostService.CreatePost(
+				ctx, post.Title, post.Content, post.ContentFormat, "", post.Tags, userID,
+			); err != nil {
+				return res, fmt.Errorf("%s: post %q: %w", op, post.Title, err)
+			}
+			res.Posts++
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/seed/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/seed/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+var users = []model.User{
+	{
+		Email: "new@news.local",
+		Name:  "New",
+		Posts: []model.Post{
+			{Title: "First", Content: "one", ContentFormat: "plain", Tags: []string{"a"}},
+			{Title: "Second", Content: "two", ContentFormat: "markdown"},
+		},
+	},
+	{
+		Email: "old@news.local",
+		Name:  "Old",
+		Posts: []model.Post{{Title: "Not again", Content: "three"}},
+	},
+}
+
+func TestSeedService_Seed(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	ensure := mock_service.NewMockEnsureUserRepository(c)
+	ensure.EXPECT().EnsureUser(gomock.Any(), "new@news.local", "New").Return(7, true, nil)
+	ensure.EXPECT().EnsureUser(gomock.Any(), "old@news.local", "Old").Return(3, false, nil)
+
+	create := mock_service.NewMockCreatePostService(c)
+	gomock.InOrder(
+		create.EXPECT().CreatePost(gomock.Any(), "First", "one", "plain", "", []string{"a"}, 7).Return(1, nil),
+		create.EXPECT().CreatePost(gomock.Any(), "Second", "two", "markdown", "", nil, 7).Return(2, nil),
+	)
+
+	s := NewSeedService(slogdiscard.NewDiscardLogger(), ensure, create)
+
+	res, err := s.Seed(context.Background(), users)
+	require.NoError(t, err)
+	require.Equal(t, model.Result{Users: 1, Posts: 2}, res)
+}
+
+func TestSeedService_Seed_PostFails(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	ensure := mock_service.NewMockEnsureUserRepository(c)
+	ensure.EXPECT().EnsureUser(gomock.Any(), "new@news.local", "New").Return(7, true, nil)
+
+	dbErr := errors.New("db is down")
+	create := mock_service.NewMockCreatePostService(c)
+	create.EXPECT().CreatePost(gomock.Any(), "First", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 7).
+		Return(0, dbErr)
+
+	s := NewSeedService(slogdiscard.NewDiscardLogger(), ensure, create)
+
+	res, err := s.Seed(context.Background(), users)
+	require.ErrorIs(t, err, dbErr)
+	require.Equal(t, model.Result{Users: 1}, res)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/sitemap/service"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SitemapService interface {
+	WriteIndex(ctx context.Context, w io.Writer) error
+	WritePosts(ctx context.Context, w io.Writer, page int) error
+	WriteNews(ctx context.Context, w io.Writer) error
+}
+
+type SitemapHandler struct {
+	logger *slog.Logger
+
+	sitemapService SitemapService
+}
+
+func NewSitemapHandler(logger *slog.Logger, sitemapService SitemapService) *SitemapHandler {
+	return &SitemapHandler{
+		logger:         logger,
+		sitemapService: sitemapService,
+	}
+}
+
+func (h SitemapHandler) GetIndex(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.sitemap.index.handler.GetIndex"
+	h.stream(w, h.logger.With(slog.String("op", op)), func(w io.Writer) error {
+		return h.sitemapService.WriteIndex(r.Context(), w)
+	})
+}
+
+// GetSitemap serves /sitemaps/news.xml and the chunks /sitemaps/posts-<n>.xml.
+func (h SitemapHandler) GetSitemap(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.sitemap.get.handler.GetSitemap"
+	logger := h.logger.With(slog.String("op", op))
+
+	name := r.PathValue("name")
+
+	if name == "news.xml" {
+		h.stream(w, logger, func(w io.Writer) error {
+			return h.sitemapService.WriteNews(r.Context(), w)
+		})
+		return
+	}
+
+	page, ok := strings.CutPrefix(name, "posts-")
+	if ok {
+		page, ok = strings.CutSuffix(page, ".xml")
+	}
+
+	n, err := strconv.Atoi(page)
+	if !ok || err != nil || n < 1 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	h.stream(w, logger, func(w io.Writer) error {
+		return h.sitemapService.WritePosts(r.Context(), w, n)
+	})
+}
+
+// stream sends the sitemap while it is generated. Once the body has started
+// an error can only be logged, the truncated document is rejected by crawlers.
+func (h SitemapHandler) stream(w http.ResponseWriter, logger *slog.Logger, write func(w io.Writer) error) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+
+	err := write(w)
+	switch {
+	case errors.Is(err, service.ErrSitemapNotFound):
+		// nothing is written before it, the headers are not sent yet
+		w.Header().Del("Content-Type")
+		w.Header().Del("Cache-Control")
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		logger.Error("cant write sitemap", logs.Err(err))
+	}
+}
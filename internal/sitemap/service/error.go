@@ -0,0 +1,7 @@
+package service
+
+import "errors"
+
+var (
+	ErrSitemapNotFound = errors.New("sitemap not found")
+)
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ananaslegend/news-crud/internal/post/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGetSitemapChunksRepository is a mock of GetSitemapChunksRepository interface.
+type MockGetSitemapChunksRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetSitemapChunksRepositoryMockRecorder
+}
+
+// MockGetSitemapChunksRepositoryMockRecorder is the mock recorder for MockGetSitemapChunksRepository.
+type MockGetSitemapChunksRepositoryMockRecorder struct {
+	mock *MockGetSitemapChunksRepository
+}
+
+// NewMockGetSitemapChunksRepository creates a new mock instance.
+func NewMockGetSitemapChunksRepository(ctrl *gomock.Controller) *MockGetSitemapChunksRepository {
+	mock := &MockGetSitemapChunksRepository{ctrl: ctrl}
+	mock.recorder = &MockGetSitemapChunksRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetSitemapChunksRepository) EXPECT() *MockGetSitemapChunksRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSitemapChunks mocks base method.
+func (m *MockGetSitemapChunksRepository) GetSitemapChunks(ctx context.Context, chunkSize int, fn func(int, time.Time) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSitemapChunks", ctx, chunkSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetSitemapChunks indicates an expected call of GetSitemapChunks.
+func (mr *MockGetSitemapChunksRepositoryMockRecorder) GetSitemapChunks(ctx, chunkSize, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSitemapChunks", reflect.TypeOf((*MockGetSitemapChunksRepository)(nil).GetSitemapChunks), ctx, chunkSize, fn)
+}
+
+// MockStreamPostsByIDRangeRepository is a mock of StreamPostsByIDRangeRepository interface.
+type MockStreamPostsByIDRangeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamPostsByIDRangeRepositoryMockRecorder
+}
+
+// MockStreamPostsByIDRangeRepositoryMockRecorder is the mock recorder for MockStreamPostsByIDRangeRepository.
+type MockStreamPostsByIDRangeRepositoryMockRecorder struct {
+	mock *MockStreamPostsByIDRangeRepository
+}
+
+// NewMockStreamPostsByIDRangeRepository creates a new mock instance.
+func NewMockStreamPostsByIDRangeRepository(ctrl *gomock.Controller) *MockStreamPostsByIDRangeRepository {
+	mock := &MockStreamPostsByIDRangeRepository{ctrl: ctrl}
+	mock.recorder = &MockStreamPostsByIDRangeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamPostsByIDRangeRepository) EXPECT() *MockStreamPostsByIDRangeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// StreamPostsByIDRange mocks base method.
+func (m *MockStreamPostsByIDRangeRepository) StreamPostsByIDRange(ctx context.Context, fromID, toID int, fn func(model.Post) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPostsByIDRange", ctx, fromID, toID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPostsByIDRange indicates an expected call of StreamPostsByIDRange.
+func (mr *MockStreamPostsByIDRangeRepositoryMockRecorder) StreamPostsByIDRange(ctx, fromID, toID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPostsByIDRange", reflect.TypeOf((*MockStreamPostsByIDRangeRepository)(nil).StreamPostsByIDRange), ctx, fromID, toID, fn)
+}
+
+// MockStreamPostsSinceRepository is a mock of StreamPostsSinceRepository interface.
+type MockStreamPostsSinceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamPostsSinceRepositoryMockRecorder
+}
+
+// MockStreamPostsSinceRepositoryMockRecorder is the mock recorder for MockStreamPostsSinceRepository.
+type MockStreamPostsSinceRepositoryMockRecorder struct {
+	mock *MockStreamPostsSinceRepository
+}
+
+// NewMockStreamPostsSinceRepository creates a new mock instance.
+func NewMockStreamPostsSinceRepository(ctrl *gomock.Controller) *MockStreamPostsSinceRepository {
+	mock := &MockStreamPostsSinceRepository{ctrl: ctrl}
+	mock.recorder = &MockStreamPostsSinceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamPostsSinceRepository) EXPECT() *MockStreamPostsSinceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// StreamPostsSince mocks base method.
+func (m *MockStreamPostsSinceRepository) StreamPostsSince(ctx context.Context, since time.Time, limit int, fn func(model.Post) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPostsSince", ctx, since, limit, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPostsSince indicates an expected call of StreamPostsSince.
+func (mr *MockStreamPostsSinceRepositoryMockRecorder) StreamPostsSince(ctx, since, limit, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPostsSince", reflect.TypeOf((*MockStreamPostsSinceRepository)(nil).StreamPostsSince), ctx, since, limit, fn)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	"github.com/ananaslegend/news-crud/pkg/sitemap"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+// NewsPeriod is how long a post stays in the news sitemap.
+const NewsPeriod = 48 * time.Hour
+
+type GetSitemapChunksRepository interface {
+	GetSitemapChunks(ctx context.Context, chunkSize int, fn func(chunk int, lastMod time.Time) error) error
+}
+
+type StreamPostsByIDRangeRepository interface {
+	StreamPostsByIDRange(ctx context.Context, fromID, toID int, fn func(post model.Post) error) error
+}
+
+type StreamPostsSinceRepository interface {
+	StreamPostsSince(ctx context.Context, since time.Time, limit int, fn func(post model.Post) error) error
+}
+
+// Config describes the site, BaseURL has no trailing slash. PublicationName and
+// Language are announced in the news sitemap.
+type Config struct {
+	BaseURL         string
+	PublicationName string
+	Language        string
+}
+
+// SitemapService writes the sitemaps of all posts. Posts are split into chunks
+// by ID, so a chunk never holds more than sitemap.MaxURLs posts and the
+// chunk of a post never changes.
+type SitemapService struct {
+	config Config
+
+	chunksRepository     GetSitemapChunksRepository
+	postsByIDRepository  StreamPostsByIDRangeRepository
+	postsSinceRepository StreamPostsSinceRepository
+}
+
+func NewSitemapService(
+	config Config,
+	chunksRepository GetSitemapChunksRepository,
+	postsByIDRepository StreamPostsByIDRangeRepository,
+	postsSinceRepository StreamPostsSinceRepository,
+) *SitemapService {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &SitemapService{
+		config:               config,
+		chunksRepository:     chunksRepository,
+		postsByIDRepository:  postsByIDRepository,
+		postsSinceRepository: postsSinceRepository,
+	}
+}
+
+// WriteIndex writes the sitemap index pointing at the news sitemap and every
+// chunk of posts.
+func (s SitemapService) WriteIndex(ctx context.Context, w io.Writer) error {
+	const op = "news-crud.internal.sitemap.index.service.WriteIndex"
+
+	sw, err := sitemap.NewIndex(w)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = sw.AddSitemap(sitemap.Sitemap{Loc: s.config.BaseURL + "/sitemaps/news.xml"}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.chunksRepository.GetSitemapChunks(ctx, sitemap.MaxURLs, func(chunk int, lastMod time.Time) error {
+		return sw.AddSitemap(sitemap.Sitemap{Loc: s.postsSitemapURL(chunk + 1), LastMod: lastMod})
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = sw.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// WritePosts writes the sitemap of the page-th chunk of posts, starting from 1.
+// Chunks without posts are not in the index, ErrSitemapNotFound is returned
+// for them before anything is written.
+func (s SitemapService) WritePosts(ctx context.Context, w io.Writer, page int) error {
+	const op = "news-crud.internal.sitemap.posts.service.WritePosts"
+
+	fromID := (page-1)*sitemap.MaxURLs + 1
+	toID := page * sitemap.MaxURLs
+
+	// the urlset is started with its first post
+	var sw *sitemap.Writer
+	err := s.postsByIDRepository.StreamPostsByIDRange(ctx, fromID, toID, func(post model.Post) error {
+		if sw == nil {
+			var err error
+			if sw, err = sitemap.NewURLSet(w, false); err != nil {
+				return err
+			}
+		}
+		return sw.AddURL(sitemap.URL{Loc: s.postURL(post.ID), LastMod: post.UpdatedAt})
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if sw == nil {
+		return ErrSitemapNotFound
+	}
+
+	if err = sw.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// WriteNews writes the Google News sitemap of the posts published within NewsPeriod.
+func (s SitemapService) WriteNews(ctx context.Context, w io.Writer) error {
+	const op = "news-crud.internal.sitemap.news.service.WriteNews"
+
+	sw, err := sitemap.NewURLSet(w, true)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	since := time.Now().Add(-NewsPeriod)
+
+	err = s.postsSinceRepository.StreamPostsSince(ctx, since, sitemap.MaxNewsURLs, func(post model.Post) error {
+		return sw.AddURL(sitemap.URL{
+			Loc:     s.postURL(post.ID),
+			LastMod: post.UpdatedAt,
+			News: &sitemap.News{
+				PublicationName: s.config.PublicationName,
+				Language:        s.config.Language,
+				Title:           post.Title,
+				PublicationDate: post.CreatedAt,
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = sw.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s SitemapService) postsSitemapURL(page int) string {
+	return s.config.BaseURL + "/sitemaps/posts-" + strconv.Itoa(page) + ".xml"
+}
+
+func (s SitemapService) postURL(postID int) string {
+	return s.config.BaseURL + "/posts/" + strconv.Itoa(postID)
+}
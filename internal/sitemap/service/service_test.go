@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/sitemap/service/mocks"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestSitemapService_WriteIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	chunks := mock_service.NewMockGetSitemapChunksRepository(ctrl)
+	chunks.EXPECT().GetSitemapChunks(gomock.Any(), 50000, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int, fn func(int, time.Time) error) error {
+			// the second chunk is empty, all of its posts are deleted
+			require.NoError(t, fn(0, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+			return fn(2, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC))
+		})
+
+	s := NewSitemapService(Config{BaseURL: "https://example.com/"}, chunks, nil, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteIndex(context.Background(), &buf))
+
+	require.Contains(t, buf.String(), "<sitemap><loc>https://example.com/sitemaps/news.xml</loc></sitemap>")
+	require.Contains(t, buf.String(), "<sitemap><loc>https://example.com/sitemaps/posts-1.xml</loc><lastmod>2024-05-01T00:00:00Z</lastmod></sitemap>")
+	require.Contains(t, buf.String(), "<sitemap><loc>https://example.com/sitemaps/posts-3.xml</loc><lastmod>2024-05-03T00:00:00Z</lastmod></sitemap>")
+	require.NotContains(t, buf.String(), "posts-2.xml")
+}
+
+func TestSitemapService_WritePosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	posts := mock_service.NewMockStreamPostsByIDRangeRepository(ctrl)
+	posts.EXPECT().StreamPostsByIDRange(gomock.Any(), 50001, 100000, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _ int, fn func(model.Post) error) error {
+			return fn(model.Post{ID: 50002, UpdatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)})
+		})
+
+	s := NewSitemapService(Config{BaseURL: "https://example.com"}, nil, posts, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WritePosts(context.Background(), &buf, 2))
+
+	require.Contains(t, buf.String(), "<url><loc>https://example.com/posts/50002</loc><lastmod>2024-05-01T00:00:00Z</lastmod></url>")
+}
+
+func TestSitemapService_WritePosts_EmptyChunk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	posts := mock_service.NewMockStreamPostsByIDRangeRepository(ctrl)
+	posts.EXPECT().StreamPostsByIDRange(gomock.Any(), 150001, 200000, gomock.Any()).Return(nil)
+
+	s := NewSitemapService(Config{BaseURL: "https://example.com"}, nil, posts, nil)
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, s.WritePosts(context.Background(), &buf, 4), ErrSitemapNotFound)
+	require.Empty(t, buf.String(), "nothing is written for pages past the last chunk")
+}
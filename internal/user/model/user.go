@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// User is the public profile of a user, the email is not part of it.
+type User struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+}
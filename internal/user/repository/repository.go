@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/user/model"
+	"github.com/lib/pq"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// GetUsersByIDs loads the users keyed by ID, unknown IDs are left out.
+func (ur UserRepository) GetUsersByIDs(ctx context.Context, ids []int) (map[int]model.User, error) {
+	const op = "news-crud.internal.user.get_by_ids.repository.GetUsersByIDs"
+
+	users := make(map[int]model.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	rows, err := ur.db.QueryContext(ctx, `
+		select id, name, created_at
+		from users
+		where id = any($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user model.User
+		if err = rows.Scan(&user.ID, &user.Name, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users[user.ID] = user
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// EnsureUser returns the user with the email, creating it when there is none.
+// Created tells which one happened.
+func (ur UserRepository) EnsureUser(ctx context.Context, email, name string) (id int, created bool, err error) {
+	const op = "news-crud.internal.user.ensure.repository.EnsureUser"
+
+	if err = ur.db.QueryRowContext(ctx, `
+		with existing as (
+		    select id from users where email = $1 order by id limit 1
+		), inserted as (
+		    insert into
+		        users (email, name)
+		    select $1, $2
+		    where not exists (select 1 from existing)
+		    returning id
+		)
+		select id, false from existing
+		union all
+		select id, true from inserted
+	`, email, name).Scan(&id, &created); err != nil {
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, created, nil
+}
+
+// GetUserIDsByEmails finds the users by email, ignoring case, keyed by the
+// lowercased email. Unknown emails are left out, the oldest user wins when
+// several share one.
+func (ur UserRepository) GetUserIDsByEmails(ctx context.Context, emails []string) (map[string]int, error) {
+	const op = "news-crud.internal.user.get_ids_by_emails.repository.GetUserIDsByEmails"
+
+	ids := make(map[string]int, len(emails))
+	if len(emails) == 0 {
+		return ids, nil
+	}
+
+	rows, err := ur.db.QueryContext(ctx, `
+		select distinct on (lower(email)) lower(email), id
+		from users
+		where lower(email) = any(select lower(e) from unnest($1::text[]) as e)
+		order by lower(email), id
+	`, pq.Array(emails))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			email string
+			id    int
+		)
+		if err = rows.Scan(&email, &id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ids[email] = id
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ananaslegend/news-crud/internal/view/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type GetTrendingService interface {
+	GetTrending(ctx context.Context, window string, limit int) ([]model.Trending, error)
+}
+
+type ViewHandler struct {
+	logger *slog.Logger
+
+	getTrendingService GetTrendingService
+}
+
+func NewViewHandler(logger *slog.Logger, getTrendingService GetTrendingService) *ViewHandler {
+	return &ViewHandler{
+		logger:             logger,
+		getTrendingService: getTrendingService,
+	}
+}
+
+func (h ViewHandler) GetTrending(w http.ResponseWriter, r *http.Request) {
+	const op = "news-crud.internal.view.get_trending.handler.GetTrending"
+	logger := h.logger.With(slog.String("op", op))
+
+	window := model.DefaultWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		window = v
+	}
+
+	limit := model.DefaultTrendingLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	trending, err := h.getTrendingService.GetTrending(r.Context(), window, limit)
+	if err != nil {
+		if errors.Is(err, model.ErrUnknownWindow) || errors.Is(err, model.ErrInvalidLimit) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		logger.Error("cant get trending posts", logs.Err(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err = json.NewEncoder(w).Encode(trending); err != nil {
+		logger.Error("cant encode response", logs.Err(err))
+	}
+}
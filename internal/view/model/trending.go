@@ -0,0 +1,39 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultTrendingLimit = 10
+	MaxTrendingLimit     = 100
+)
+
+var (
+	ErrUnknownWindow = errors.New("window should be one of 1h, 24h, 7d")
+	ErrInvalidLimit  = errors.New("limit should be between 1 and 100")
+)
+
+// Window is the period trending posts are ranked over. Views lose half of
+// their weight every HalfLife, so recent reads count more.
+type Window struct {
+	Period   time.Duration
+	HalfLife time.Duration
+}
+
+var Windows = map[string]Window{
+	"1h":  {Period: time.Hour, HalfLife: 15 * time.Minute},
+	"24h": {Period: 24 * time.Hour, HalfLife: 6 * time.Hour},
+	"7d":  {Period: 7 * 24 * time.Hour, HalfLife: 36 * time.Hour},
+}
+
+const DefaultWindow = "24h"
+
+// Trending is a post ranked by its time-decayed views.
+type Trending struct {
+	PostID int     `json:"post_id"`
+	Title  string  `json:"title"`
+	Views  int     `json:"views"`
+	Score  float64 `json:"score"`
+}
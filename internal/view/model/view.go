@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// BucketSize is the resolution views are counted with.
+const BucketSize = 5 * time.Minute
+
+// View is a single read of a post. Viewer is the user or, for anonymous
+// readers, the client IP the view is deduplicated by.
+type View struct {
+	PostID   int
+	Viewer   string
+	ViewedAt time.Time
+}
+
+// Count is the number of views a post got within a bucket.
+type Count struct {
+	PostID int
+	Bucket time.Time
+	Views  int
+}
+
+// Counts sums the views per post and bucket.
+func Counts(views []View) []Count {
+	type key struct {
+		postID int
+		bucket time.Time
+	}
+
+	indexes := make(map[key]int)
+	counts := make([]Count, 0)
+
+	for _, view := range views {
+		k := key{postID: view.PostID, bucket: view.ViewedAt.UTC().Truncate(BucketSize)}
+
+		i, ok := indexes[k]
+		if !ok {
+			i = len(counts)
+			indexes[k] = i
+			counts = append(counts, Count{PostID: k.postID, Bucket: k.bucket})
+		}
+		counts[i].Views++
+	}
+
+	return counts
+}
+
+// RecorderConfig tunes the in-memory buffer views are collected in before
+// they are written in batches.
+type RecorderConfig struct {
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	DedupWindow   time.Duration
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/view/model"
+	"time"
+)
+
+type ViewRepository struct {
+	db *sql.DB
+}
+
+func NewViewRepository(db *sql.DB) *ViewRepository {
+	return &ViewRepository{db: db}
+}
+
+// AddViews adds the counts to the view buckets in a single transaction.
+// Views of posts deleted in the meantime are skipped.
+func (vr ViewRepository) AddViews(ctx context.Context, counts []model.Count) error {
+	const op = "news-crud.internal.view.add.repository.AddViews"
+
+	tx, err := vr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		insert into
+		    post_views (post_id, bucket, views)
+		select id, $2, $3
+		from posts
+		where id = $1
+		on conflict (post_id, bucket) do update
+		set views = post_views.views + excluded.views
+	`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, count := range counts {
+		if _, err = stmt.ExecContext(ctx, count.PostID, count.Bucket, count.Views); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetTrending ranks the posts viewed since the start of the window by the sum
+// of their views, each bucket halved for every halfLife it is old.
+func (vr ViewRepository) GetTrending(ctx context.Context, now time.Time, window model.Window, limit int) ([]model.Trending, error) {
+	const op = "news-crud.internal.view.get_trending.repository.GetTrending"
+
+	rows, err := vr.db.QueryContext(ctx, `
+		select p.id, p.title, sum(v.views),
+		       sum(v.views * power(0.5, extract(epoch from ($1::timestamp - v.bucket)) / $2::float8)) as score
+		from post_views v
+		join posts p on p.id = v.post_id
+		where v.bucket >= $3
+		group by p.id, p.title
+		order by score desc, p.id desc
+		limit $4
+	`, now.UTC(), window.HalfLife.Seconds(), now.UTC().Add(-window.Period), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	trending := make([]model.Trending, 0)
+
+	for rows.Next() {
+		var post model.Trending
+		if err = rows.Scan(&post.PostID, &post.Title, &post.Views, &post.Score); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		trending = append(trending, post)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return trending, nil
+}
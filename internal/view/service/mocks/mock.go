@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ananaslegend/news-crud/internal/view/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAddViewsRepository is a mock of AddViewsRepository interface.
+type MockAddViewsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddViewsRepositoryMockRecorder
+}
+
+// MockAddViewsRepositoryMockRecorder is the mock recorder for MockAddViewsRepository.
+type MockAddViewsRepositoryMockRecorder struct {
+	mock *MockAddViewsRepository
+}
+
+// NewMockAddViewsRepository creates a new mock instance.
+func NewMockAddViewsRepository(ctrl *gomock.Controller) *MockAddViewsRepository {
+	mock := &MockAddViewsRepository{ctrl: ctrl}
+	mock.recorder = &MockAddViewsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddViewsRepository) EXPECT() *MockAddViewsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddViews mocks base method.
+func (m *MockAddViewsRepository) AddViews(ctx context.Context, counts []model.Count) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddViews", ctx, counts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddViews indicates an expected call of AddViews.
+func (mr *MockAddViewsRepositoryMockRecorder) AddViews(ctx, counts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViews", reflect.TypeOf((*MockAddViewsRepository)(nil).AddViews), ctx, counts)
+}
+
+// MockGetTrendingRepository is a mock of GetTrendingRepository interface.
+type MockGetTrendingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetTrendingRepositoryMockRecorder
+}
+
+// MockGetTrendingRepositoryMockRecorder is the mock recorder for MockGetTrendingRepository.
+type MockGetTrendingRepositoryMockRecorder struct {
+	mock *MockGetTrendingRepository
+}
+
+// NewMockGetTrendingRepository creates a new mock instance.
+func NewMockGetTrendingRepository(ctrl *gomock.Controller) *MockGetTrendingRepository {
+	mock := &MockGetTrendingRepository{ctrl: ctrl}
+	mock.recorder = &MockGetTrendingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetTrendingRepository) EXPECT() *MockGetTrendingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTrending mocks base method.
+func (m *MockGetTrendingRepository) GetTrending(ctx context.Context, now time.Time, window model.Window, limit int) ([]model.Trending, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrending", ctx, now, window, limit)
+	ret0, _ := ret[0].([]model.Trending)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrending indicates an expected call of GetTrending.
+func (mr *MockGetTrendingRepositoryMockRecorder) GetTrending(ctx, now, window, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrending", reflect.TypeOf((*MockGetTrendingRepository)(nil).GetTrending), ctx, now, window, limit)
+}
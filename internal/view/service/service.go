@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/view/model"
+	"github.com/ananaslegend/news-crud/pkg/logs"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=service.go -destination=mocks/mock.go
+
+type AddViewsRepository interface {
+	AddViews(ctx context.Context, counts []model.Count) error
+}
+
+type GetTrendingRepository interface {
+	GetTrending(ctx context.Context, now time.Time, window model.Window, limit int) ([]model.Trending, error)
+}
+
+const flushTimeout = 5 * time.Second
+
+// ViewService counts post views. Views are buffered in memory and written in
+// batches by Run, so recording a view never waits for the database.
+type ViewService struct {
+	logger *slog.Logger
+	cfg    model.RecorderConfig
+
+	addViewsRepository    AddViewsRepository
+	getTrendingRepository GetTrendingRepository
+
+	views chan model.View
+
+	mu   sync.Mutex
+	seen map[seenKey]time.Time
+
+	now func() time.Time
+}
+
+type seenKey struct {
+	postID int
+	viewer string
+}
+
+func NewViewService(
+	logger *slog.Logger,
+	cfg model.RecorderConfig,
+	addViewsRepository AddViewsRepository,
+	getTrendingRepository GetTrendingRepository,
+) *ViewService {
+	return &ViewService{
+		logger:                logger,
+		cfg:                   cfg,
+		addViewsRepository:    addViewsRepository,
+		getTrendingRepository: getTrendingRepository,
+		views:                 make(chan model.View, cfg.BufferSize),
+		seen:                  make(map[seenKey]time.Time),
+		now:                   time.Now,
+	}
+}
+
+// RecordView counts a view of the post by the user or client IP of the request.
+// Repeated views by the same viewer within the dedup window are ignored, and
+// views are dropped while the buffer is full. Views of requests with neither
+// are not counted, they could not be told apart.
+func (vs *ViewService) RecordView(ctx context.Context, postID int) {
+	const op = "news-crud.internal.view.record.service.RecordView"
+
+	v, ok := viewer(ctx)
+	if !ok {
+		return
+	}
+
+	view := model.View{PostID: postID, Viewer: v, ViewedAt: vs.now()}
+
+	if !vs.firstView(view) {
+		return
+	}
+
+	select {
+	case vs.views <- view:
+	default:
+		vs.logger.Warn("view buffer is full, view dropped", slog.String("op", op))
+	}
+}
+
+// Run writes the buffered views until the context is done, then writes what is
+// left in the buffer.
+func (vs *ViewService) Run(ctx context.Context) {
+	ticker := time.NewTicker(vs.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.View, 0, vs.cfg.BatchSize)
+
+	for {
+		select {
+		case view := <-vs.views:
+			batch = append(batch, view)
+			if len(batch) >= vs.cfg.BatchSize {
+				batch = vs.flush(batch)
+			}
+		case <-ticker.C:
+			batch = vs.flush(batch)
+			vs.forgetSeen()
+		case <-ctx.Done():
+			for {
+				select {
+				case view := <-vs.views:
+					batch = append(batch, view)
+				default:
+					vs.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (vs *ViewService) GetTrending(ctx context.Context, window string, limit int) ([]model.Trending, error) {
+	const op = "news-crud.internal.view.get_trending.service.GetTrending"
+
+	w, ok := model.Windows[window]
+	if !ok {
+		return nil, model.ErrUnknownWindow
+	}
+
+	if limit < 1 || limit > model.MaxTrendingLimit {
+		return nil, model.ErrInvalidLimit
+	}
+
+	trending, err := vs.getTrendingRepository.GetTrending(ctx, vs.now(), w, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return trending, nil
+}
+
+// flush writes the batch and returns it emptied. A failed batch is logged and
+// dropped, view counts are not worth blocking the buffer for.
+func (vs *ViewService) flush(batch []model.View) []model.View {
+	const op = "news-crud.internal.view.record.service.flush"
+
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := vs.addViewsRepository.AddViews(ctx, model.Counts(batch)); err != nil {
+		vs.logger.Error("cant write views", slog.String("op", op), slog.Int("views", len(batch)), logs.Err(err))
+	}
+
+	return batch[:0]
+}
+
+func (vs *ViewService) firstView(view model.View) bool {
+	key := seenKey{postID: view.PostID, viewer: view.Viewer}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	if last, ok := vs.seen[key]; ok && view.ViewedAt.Sub(last) < vs.cfg.DedupWindow {
+		return false
+	}
+	vs.seen[key] = view.ViewedAt
+
+	return true
+}
+
+func (vs *ViewService) forgetSeen() {
+	expired := vs.now().Add(-vs.cfg.DedupWindow)
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	for key, last := range vs.seen {
+		if last.Before(expired) {
+			delete(vs.seen, key)
+		}
+	}
+}
+
+func viewer(ctx context.Context) (string, bool) {
+	if userID, ok := contexts.GetUserID(ctx); ok {
+		return "user:" + strconv.Itoa(userID), true
+	}
+
+	if ip, ok := contexts.GetClientIP(ctx); ok && ip != "" {
+		return "ip:" + ip, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/internal/contexts"
+	"github.com/ananaslegend/news-crud/internal/view/model"
+	mock_service "github.com/ananaslegend/news-crud/internal/view/service/mocks"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCounts(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 2, 0, 0, time.UTC)
+
+	counts := model.Counts([]model.View{
+		{PostID: 1, ViewedAt: at},
+		{PostID: 1, ViewedAt: at.Add(time.Minute)},
+		{PostID: 2, ViewedAt: at},
+		{PostID: 1, ViewedAt: at.Add(5 * time.Minute)},
+	})
+
+	require.Equal(t, []model.Count{
+		{PostID: 1, Bucket: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Views: 2},
+		{PostID: 2, Bucket: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Views: 1},
+		{PostID: 1, Bucket: time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC), Views: 1},
+	}, counts)
+}
+
+func TestViewService_RecordView(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	add := mock_service.NewMockAddViewsRepository(ctrl)
+
+	cfg := model.RecorderConfig{BufferSize: 10, BatchSize: 100, FlushInterval: time.Hour, DedupWindow: 30 * time.Minute}
+	s := NewViewService(slogdiscard.NewDiscardLogger(), cfg, add, nil)
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	user := contexts.SetUserID(context.Background(), 7)
+	anonymous := contexts.SetClientIP(context.Background(), "10.0.0.1")
+
+	s.RecordView(user, 1)
+	s.RecordView(user, 1) // same user within the window
+	s.RecordView(user, 2)
+	s.RecordView(anonymous, 1)
+	s.RecordView(anonymous, 1) // same IP within the window
+
+	// without a user or an IP the view is not counted
+	s.RecordView(context.Background(), 3)
+
+	now = now.Add(31 * time.Minute)
+	s.RecordView(user, 1)
+
+	add.EXPECT().AddViews(gomock.Any(), []model.Count{
+		{PostID: 1, Bucket: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Views: 2},
+		{PostID: 2, Bucket: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Views: 1},
+		{PostID: 1, Bucket: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), Views: 1},
+	}).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// buffered views are written once the context is done
+	s.Run(ctx)
+}
+
+func TestViewService_RecordView_FullBuffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cfg := model.RecorderConfig{BufferSize: 1, BatchSize: 100, FlushInterval: time.Hour}
+	s := NewViewService(slogdiscard.NewDiscardLogger(), cfg, mock_service.NewMockAddViewsRepository(ctrl), nil)
+
+	ctx := contexts.SetClientIP(context.Background(), "10.0.0.1")
+	s.RecordView(ctx, 1)
+	s.RecordView(ctx, 2)
+
+	require.Len(t, s.views, 1)
+}
+
+func TestViewService_GetTrending(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  string
+		limit   int
+		wantErr error
+	}{
+		{name: "Last day", window: "24h", limit: 10},
+		{name: "Unknown window", window: "30d", limit: 10, wantErr: model.ErrUnknownWindow},
+		{name: "Limit is too big", window: "1h", limit: 1000, wantErr: model.ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			trending := mock_service.NewMockGetTrendingRepository(ctrl)
+			if tt.wantErr == nil {
+				trending.EXPECT().GetTrending(gomock.Any(), gomock.Any(), model.Windows[tt.window], tt.limit).
+					Return([]model.Trending{{PostID: 1, Views: 3, Score: 2.5}}, nil)
+			}
+
+			s := NewViewService(slogdiscard.NewDiscardLogger(), model.RecorderConfig{}, nil, trending)
+
+			_, err := s.GetTrending(context.Background(), tt.window, tt.limit)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
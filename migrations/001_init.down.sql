@@ -1 +1 @@
-drop table if exists posts
\ No newline at end of file
+drop table if exists posts;
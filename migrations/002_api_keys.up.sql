@@ -0,0 +1,14 @@
+create table if not exists api_keys (
+  id serial primary key,
+  user_id integer not null,
+  name text not null,
+  prefix text not null unique,
+  key_hash text not null,
+  scopes text[] not null default '{}',
+  expires_at timestamp,
+  last_used_at timestamp,
+  created_at timestamp not null default now(),
+  revoked_at timestamp
+);
+
+create index if not exists api_keys_user_id_idx on api_keys (user_id);
@@ -0,0 +1,2 @@
+drop table if exists user_identities;
+drop table if exists users;
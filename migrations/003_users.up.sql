@@ -0,0 +1,15 @@
+create table if not exists users (
+  id serial primary key,
+  email text not null default '',
+  name text not null default '',
+  created_at timestamp not null default now()
+);
+
+create table if not exists user_identities (
+  issuer text not null,
+  subject text not null,
+  user_id integer not null references users (id) on delete cascade,
+  created_at timestamp not null default now(),
+  last_login_at timestamp not null default now(),
+  primary key (issuer, subject)
+);
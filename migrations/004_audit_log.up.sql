@@ -0,0 +1,25 @@
+create table if not exists audit_log (
+  id bigserial primary key,
+  actor_id integer not null,
+  action text not null,
+  post_id integer not null,
+  before jsonb,
+  after jsonb,
+  request_id text not null default '',
+  ip text not null default '',
+  created_at timestamp not null default now()
+);
+
+create index if not exists audit_log_post_id_idx on audit_log (post_id, id);
+create index if not exists audit_log_actor_id_idx on audit_log (actor_id, id);
+create index if not exists audit_log_created_at_idx on audit_log (created_at);
+
+create or replace function audit_log_append_only() returns trigger as $$
+begin
+  raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_log_append_only
+  before update or delete on audit_log
+  for each row execute function audit_log_append_only();
@@ -0,0 +1,15 @@
+create table if not exists post_authors (
+  post_id integer not null references posts (id) on delete cascade,
+  user_id integer not null,
+  role text not null check (role in ('lead', 'contributor')),
+  position integer not null,
+  primary key (post_id, user_id)
+);
+
+create unique index if not exists post_authors_lead_idx on post_authors (post_id) where role = 'lead';
+create index if not exists post_authors_user_id_idx on post_authors (user_id);
+
+insert into post_authors (post_id, user_id, role, position)
+select id, author_id, 'lead', 0
+from posts
+on conflict do nothing;
@@ -0,0 +1,18 @@
+create table if not exists comments (
+  id serial primary key,
+  post_id integer not null references posts (id) on delete cascade,
+  parent_id integer references comments (id) on delete cascade,
+  root_id integer references comments (id) on delete cascade,
+  author_id integer not null,
+  content text not null,
+  status text not null default 'pending' check (status in ('pending', 'approved', 'rejected')),
+  moderated_by integer,
+  moderated_at timestamp,
+  created_at timestamp not null default now(),
+  updated_at timestamp not null default now(),
+  deleted_at timestamp
+);
+
+create index if not exists comments_post_roots_idx on comments (post_id, id) where parent_id is null and status = 'approved';
+create index if not exists comments_root_id_idx on comments (root_id);
+create index if not exists comments_pending_idx on comments (id) where status = 'pending';
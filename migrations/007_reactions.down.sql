@@ -0,0 +1,4 @@
+drop index if exists posts_reaction_count_idx;
+alter table posts drop column if exists reaction_count;
+drop table if exists post_reaction_counts;
+drop table if exists post_reactions;
@@ -0,0 +1,18 @@
+create table if not exists post_reactions (
+  post_id integer not null references posts (id) on delete cascade,
+  user_id integer not null,
+  reaction text not null,
+  created_at timestamp not null default now(),
+  primary key (post_id, user_id, reaction)
+);
+
+create table if not exists post_reaction_counts (
+  post_id integer not null references posts (id) on delete cascade,
+  reaction text not null,
+  count integer not null default 0 check (count >= 0),
+  primary key (post_id, reaction)
+);
+
+alter table posts add column if not exists reaction_count integer not null default 0;
+
+create index if not exists posts_reaction_count_idx on posts (reaction_count desc, id desc);
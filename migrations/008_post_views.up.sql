@@ -0,0 +1,8 @@
+create table if not exists post_views (
+  post_id integer not null references posts (id) on delete cascade,
+  bucket timestamp not null,
+  views integer not null,
+  primary key (post_id, bucket)
+);
+
+create index if not exists post_views_bucket_idx on post_views (bucket);
@@ -0,0 +1,7 @@
+create table if not exists post_tags (
+  post_id integer not null references posts (id) on delete cascade,
+  tag text not null,
+  primary key (post_id, tag)
+);
+
+create index if not exists post_tags_tag_idx on post_tags (tag, post_id);
@@ -0,0 +1,2 @@
+alter table posts drop column if exists content_html;
+alter table posts drop column if exists content_format;
@@ -0,0 +1,13 @@
+alter table posts add column if not exists content_format text not null default 'plain';
+alter table posts add column if not exists content_html text not null default '';
+
+-- existing posts are plain text: escape them, split paragraphs on blank lines
+-- and keep single line breaks, like the service renders plain text
+update posts
+set content_html = '<p>' || replace(replace(
+    regexp_replace(
+        replace(replace(replace(replace(replace(replace(btrim(content, E' \n\r\t'), E'\r\n', E'\n'),
+            '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+        E'\\s*\n\\s*\n\\s*', E'\x01', 'g'),
+    E'\n', '<br>'), E'\x01', E'</p>\n<p>') || E'</p>\n'
+where content_format = 'plain' and content_html = '' and btrim(content, E' \n\r\t') <> '';
@@ -0,0 +1,2 @@
+drop table if exists post_media;
+drop table if exists media;
@@ -0,0 +1,18 @@
+create table if not exists media (
+  id serial primary key,
+  owner_id integer not null,
+  storage_key text not null unique,
+  content_type text not null,
+  size bigint not null,
+  filename text not null default '',
+  created_at timestamp not null default now()
+);
+
+create table if not exists post_media (
+  post_id integer not null references posts (id) on delete cascade,
+  media_id integer not null references media (id) on delete cascade,
+  position integer not null,
+  primary key (post_id, media_id)
+);
+
+create index if not exists post_media_media_id_idx on post_media (media_id);
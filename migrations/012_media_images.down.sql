@@ -0,0 +1,8 @@
+drop table if exists media_variants;
+drop index if exists media_unprocessed_idx;
+alter table media drop column if exists claimed_at;
+alter table media drop column if exists attempts;
+alter table media drop column if exists status;
+alter table media drop column if exists blurhash;
+alter table media drop column if exists height;
+alter table media drop column if exists width;
@@ -0,0 +1,20 @@
+-- images are processed after the upload, other media is ready right away
+alter table media add column if not exists width integer not null default 0;
+alter table media add column if not exists height integer not null default 0;
+alter table media add column if not exists blurhash text not null default '';
+alter table media add column if not exists status text not null default 'ready';
+alter table media add column if not exists attempts integer not null default 0;
+alter table media add column if not exists claimed_at timestamp;
+
+create index if not exists media_unprocessed_idx on media (id) where status in ('pending', 'processing');
+
+create table if not exists media_variants (
+  media_id integer not null references media (id) on delete cascade,
+  name text not null,
+  storage_key text not null unique,
+  content_type text not null,
+  width integer not null,
+  height integer not null,
+  size bigint not null,
+  primary key (media_id, name, content_type)
+);
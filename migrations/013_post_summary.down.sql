@@ -0,0 +1,4 @@
+alter table posts drop column if exists reading_time;
+alter table posts drop column if exists word_count;
+alter table posts drop column if exists excerpt_generated;
+alter table posts drop column if exists excerpt;
@@ -0,0 +1,25 @@
+alter table posts add column if not exists excerpt text not null default '';
+alter table posts add column if not exists excerpt_generated boolean not null default true;
+alter table posts add column if not exists word_count integer not null default 0;
+alter table posts add column if not exists reading_time integer not null default 0;
+
+-- existing posts get an approximation from the text of their rendering, the
+-- exact values are computed the next time a post is saved
+with texts as (
+  select id, btrim(regexp_replace(
+      replace(replace(replace(replace(replace(
+          regexp_replace(content_html, '<[^>]*>', ' ', 'g'),
+          '&lt;', '<'), '&gt;', '>'), '&#34;', '"'), '&#39;', ''''), '&amp;', '&'),
+      '\s+', ' ', 'g')) as text
+  from posts
+)
+update posts p
+set word_count = case when t.text = '' then 0 else array_length(regexp_split_to_array(t.text, ' '), 1) end,
+    excerpt = case
+        when length(t.text) <= 280 then t.text
+        else coalesce(substring(t.text from '^(.{0,279}[.!?])\s'), left(t.text, 279) || '…')
+    end
+from texts t
+where p.id = t.id;
+
+update posts set reading_time = (word_count + 199) / 200;
@@ -0,0 +1,11 @@
+-- posts imported from other systems remember where they came from, so an
+-- import can be repeated without duplicating them
+create table if not exists post_sources (
+  source text not null,
+  source_id text not null,
+  post_id integer not null references posts (id) on delete cascade,
+  imported_at timestamp not null default now(),
+  primary key (source, source_id)
+);
+
+create index if not exists post_sources_post_id_idx on post_sources (post_id);
@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations of the database so the
+// server binary can apply them itself.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,245 @@
+// Package client is a Go client of the news-crud HTTP API. It sends the
+// configured credentials with every request, retries rate limited requests and
+// server errors with backoff and maps error responses to *APIError.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff = 10 * time.Second
+
+	// maxErrorSize bounds the error message read from a response.
+	maxErrorSize = 4 << 10
+)
+
+// TokenSource supplies the bearer token. It is asked before every request,
+// so it may refresh tokens that are about to expire.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Config configures a Client. TokenSource and APIKey are both optional, reads
+// work anonymously. A zero MaxRetries means DefaultMaxRetries, a negative one
+// disables retries.
+type Config struct {
+	BaseURL     string
+	TokenSource TokenSource
+	APIKey      string
+	MaxRetries  int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+}
+
+type Client struct {
+	baseURL *url.URL
+	config  Config
+	client  *http.Client
+}
+
+// New creates a client of the API served at config.BaseURL, a nil httpClient
+// means http.DefaultClient.
+func New(config Config, httpClient *http.Client) (*Client, error) {
+	const op = "news-crud.pkg.client.New"
+
+	baseURL, err := url.Parse(strings.TrimSuffix(config.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("%s: base url should be absolute, got %q", op, config.BaseURL)
+	}
+
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.MinBackoff == 0 {
+		config.MinBackoff = DefaultMinBackoff
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		config:  config,
+		client:  httpClient,
+	}, nil
+}
+
+// call is one API request. Out, when set, receives the JSON body of a
+// successful response.
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	in     any
+	out    any
+}
+
+// do sends the call, retrying it on 429 and, for idempotent methods, on
+// server and network errors.
+func (c *Client) do(ctx context.Context, cl call) error {
+	var body []byte
+	if cl.in != nil {
+		var err error
+		if body, err = json.Marshal(cl.in); err != nil {
+			return fmt.Errorf("cant encode request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, cl, body)
+
+		var delay time.Duration
+		retry := attempt < c.config.MaxRetries
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return err
+			}
+			retry = retry && idempotent(cl.method)
+			delay = c.backoff(attempt)
+		case res.StatusCode == http.StatusTooManyRequests:
+			delay = retryAfter(res.Header, c.backoff(attempt))
+		case res.StatusCode >= http.StatusInternalServerError:
+			retry = retry && idempotent(cl.method)
+			delay = c.backoff(attempt)
+		default:
+			return decodeResponse(res, cl.out)
+		}
+
+		if !retry {
+			if err != nil {
+				return err
+			}
+			return decodeResponse(res, cl.out)
+		}
+
+		if res != nil {
+			io.Copy(io.Discard, io.LimitReader(res.Body, maxErrorSize))
+			res.Body.Close()
+		}
+
+		if err = sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, cl call, body []byte) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += cl.path
+	u.RawQuery = cl.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cl.method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.config.TokenSource != nil {
+		token, err := c.config.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cant get token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	if c.config.APIKey != "" {
+		req.Header.Set("X-API-Key", c.config.APIKey)
+	}
+
+	return c.client.Do(req)
+}
+
+func decodeResponse(res *http.Response, out any) error {
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return newAPIError(res)
+	}
+
+	if out == nil {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("cant decode response: %w", err)
+	}
+	return nil
+}
+
+// backoff is the exponential delay before the next attempt, with jitter so
+// clients that failed together do not retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.config.MinBackoff << attempt
+	if d <= 0 || d > c.config.MaxBackoff {
+		d = c.config.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter reads the seconds the server asked to wait.
+func retryAfter(h http.Header, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
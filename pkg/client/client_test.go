@@ -0,0 +1,386 @@
+package client
+
+import (
+	"context"
+	"errors"
+	commentHandler "github.com/ananaslegend/news-crud/internal/comment/handler"
+	commentModel "github.com/ananaslegend/news-crud/internal/comment/model"
+	"github.com/ananaslegend/news-crud/internal/docs"
+	"github.com/ananaslegend/news-crud/internal/middleware"
+	postHandler "github.com/ananaslegend/news-crud/internal/post/handler"
+	"github.com/ananaslegend/news-crud/internal/post/model"
+	postService "github.com/ananaslegend/news-crud/internal/post/service"
+	"github.com/ananaslegend/news-crud/pkg/jwt"
+	"github.com/ananaslegend/news-crud/pkg/logs/handler/slogdiscard"
+	"github.com/ananaslegend/news-crud/pkg/openapi"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const secret = "test-secret"
+
+// posts is an in-memory post service, only authors may change their posts.
+type posts struct {
+	mu     sync.Mutex
+	nextID int
+	posts  map[int]model.Post
+}
+
+func (p *posts) CreatePost(_ context.Context, title, content, contentFormat, excerpt string, tags []string, authorID int) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	post := model.NewPost(title, content, contentFormat, authorID)
+	post.ID, post.Excerpt, post.Tags = p.nextID, excerpt, tags
+	p.posts[post.ID] = post
+	return post.ID, nil
+}
+
+func (p *posts) GetPostByFilter(_ context.Context, filter model.Filter) ([]model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var list []model.Post
+	for _, post := range p.posts {
+		list = append(list, post)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	list = list[min(filter.Offset, len(list)):]
+	if filter.Limit > 0 {
+		list = list[:min(filter.Limit, len(list))]
+	}
+	if len(list) == 0 {
+		return nil, postService.ErrNoPostWasFound
+	}
+	return list, nil
+}
+
+func (p *posts) GetPostByID(_ context.Context, id int, _ []string) (model.Post, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	post, ok := p.posts[id]
+	if !ok {
+		return model.Post{}, postService.ErrNoPostWasFound
+	}
+	return post, nil
+}
+
+func (p *posts) UpdatePost(_ context.Context, userID int, post model.Post) error {
+	return p.change(userID, post.ID, func(old *model.Post) {
+		old.Title, old.Content = post.Title, post.Content
+	})
+}
+
+func (p *posts) DeletePost(_ context.Context, userID, postID int) error {
+	return p.change(userID, postID, func(old *model.Post) {
+		delete(p.posts, postID)
+	})
+}
+
+func (p *posts) UpdatePostAuthors(_ context.Context, userID, postID int, authors []model.Author) error {
+	if err := model.ValidateByline(authors); err != nil {
+		return err
+	}
+	return p.change(userID, postID, func(old *model.Post) {
+		old.Authors, old.AuthorID = model.NewByline(authors), model.LeadAuthorID(authors)
+	})
+}
+
+func (p *posts) ApplyBatch(ctx context.Context, userID int, ops []model.BatchOperation, _ bool) ([]model.BatchResult, error) {
+	results := make([]model.BatchResult, len(ops))
+	for i, op := range ops {
+		results[i].PostID = op.Post.ID
+
+		switch op.Op {
+		case model.BatchCreate:
+			results[i].PostID, results[i].Err = p.CreatePost(ctx, op.Post.Title, op.Post.Content, op.Post.ContentFormat,
+				op.Post.Excerpt, op.Post.Tags, userID)
+		case model.BatchUpdate:
+			results[i].Err = p.UpdatePost(ctx, userID, op.Post)
+		case model.BatchDelete:
+			results[i].Err = p.DeletePost(ctx, userID, op.Post.ID)
+		}
+	}
+	return results, nil
+}
+
+func (p *posts) change(userID, postID int, change func(post *model.Post)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	post, ok := p.posts[postID]
+	if !ok {
+		return postService.ErrNoPostWasFound
+	}
+	if post.AuthorID != userID {
+		return postService.ErrUserHasNoPermission
+	}
+
+	change(&post)
+	if _, ok = p.posts[postID]; ok {
+		p.posts[postID] = post
+	}
+	return nil
+}
+
+// comments pages through ten approved comments of post 1, oldest first.
+type comments struct {
+	commentHandler.CreateCommentService
+	commentHandler.GetModerationQueueService
+	commentHandler.UpdateCommentService
+	commentHandler.DeleteCommentService
+	commentHandler.ModerateCommentService
+}
+
+func (comments) GetCommentsByPostID(_ context.Context, postID int, page commentModel.Pagination) (commentModel.Page, error) {
+	var res commentModel.Page
+	if postID != 1 {
+		return res, nil
+	}
+
+	for id := page.Cursor + 1; id <= 10 && len(res.Comments) < page.Limit; id++ {
+		res.Comments = append(res.Comments, &commentModel.Comment{ID: id, PostID: postID, Status: commentModel.StatusApproved})
+	}
+	if last := len(res.Comments); last > 0 && res.Comments[last-1].ID < 10 {
+		res.NextCursor = res.Comments[last-1].ID
+	}
+	return res, nil
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	logger := slogdiscard.NewDiscardLogger()
+
+	store := &posts{posts: make(map[int]model.Post)}
+	postHdl := postHandler.NewPostHandler(logger, store, store, store, store, store, store, store)
+	commentHdl := commentHandler.NewCommentHandler(logger, nil, comments{}, nil, nil, nil, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /posts", middleware.Auth(secret, nil, postHdl.CreatePost))
+	mux.HandleFunc("GET /posts", postHdl.GetPostByFilter)
+	mux.HandleFunc("GET /posts/{id}", postHdl.GetPostByID)
+	mux.HandleFunc("PUT /posts/{id}", middleware.Auth(secret, nil, postHdl.UpdatePostByID))
+	mux.HandleFunc("DELETE /posts/{id}", middleware.Auth(secret, nil, postHdl.DeletePost))
+	mux.HandleFunc("PUT /posts/{id}/authors", middleware.Auth(secret, nil, postHdl.UpdatePostAuthors))
+	mux.HandleFunc("GET /posts/{id}/comments", commentHdl.GetCommentsByPostID)
+
+	doc, err := openapi.Load(docs.OpenAPI)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(middleware.ValidateResponses(doc, func(r *http.Request, err error) {
+		t.Errorf("%s %s: %v", r.Method, r.URL, err)
+	}, middleware.ValidateRequests(doc, mux)))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newClient(t *testing.T, baseURL string, userID int) *Client {
+	config := Config{BaseURL: baseURL, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	if userID != 0 {
+		token, err := jwt.NewToken(userID, nil, time.Hour, []byte(secret))
+		require.NoError(t, err)
+		config.TokenSource = StaticToken(token)
+	}
+
+	c, err := New(config, nil)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_Posts(t *testing.T) {
+	srv := newServer(t)
+	ctx := context.Background()
+
+	author, other, anonymous := newClient(t, srv.URL, 7), newClient(t, srv.URL, 8), newClient(t, srv.URL, 0)
+
+	id, err := author.CreatePost(ctx, NewPost{Title: "Title", Content: "Content", Tags: []string{"go"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, id)
+
+	post, err := anonymous.GetPost(ctx, id, GetPostParams{})
+	require.NoError(t, err)
+	require.Equal(t, "Title", post.Title)
+	require.Equal(t, 7, post.AuthorID)
+	require.Equal(t, []string{"go"}, post.Tags)
+
+	post, err = anonymous.GetPost(ctx, id, GetPostParams{Fields: []string{"id", "title"}})
+	require.NoError(t, err)
+	require.Equal(t, Post{ID: id, Title: "Title"}, post)
+
+	post.Title = "New title"
+	require.ErrorIs(t, anonymous.UpdatePost(ctx, post), ErrUnauthorized)
+	require.ErrorIs(t, other.UpdatePost(ctx, post), ErrForbidden)
+	require.NoError(t, author.UpdatePost(ctx, post))
+
+	post, err = anonymous.GetPost(ctx, id, GetPostParams{Fields: []string{"title"}})
+	require.NoError(t, err)
+	require.Equal(t, "New title", post.Title)
+
+	require.NoError(t, author.SetPostAuthors(ctx, id, []Author{
+		{UserID: 8, Role: AuthorRoleLead},
+		{UserID: 7, Role: AuthorRoleContributor},
+	}))
+	require.ErrorIs(t, author.DeletePost(ctx, id), ErrForbidden)
+	require.NoError(t, other.DeletePost(ctx, id))
+
+	_, err = anonymous.GetPost(ctx, id, GetPostParams{})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = author.CreatePost(ctx, NewPost{Title: "Title"})
+	require.ErrorIs(t, err, ErrBadRequest)
+	require.ErrorAs(t, err, &apiErr)
+	require.Contains(t, apiErr.Message, "content should be at least 1 characters")
+}
+
+func TestClient_Iterators(t *testing.T) {
+	srv := newServer(t)
+	ctx := context.Background()
+	c := newClient(t, srv.URL, 7)
+
+	page, err := c.ListPosts(ctx, ListPostsParams{})
+	require.NoError(t, err)
+	require.Empty(t, page)
+
+	for i := 0; i < 5; i++ {
+		_, err := c.CreatePost(ctx, NewPost{Title: "Title", Content: "Content"})
+		require.NoError(t, err)
+	}
+
+	var ids []int
+	it := c.Posts(ListPostsParams{Limit: 2, View: "compact"})
+	for it.Next(ctx) {
+		ids = append(ids, it.Value().ID)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+
+	ids = nil
+	comments := c.Comments(1, ListCommentsParams{Limit: 3})
+	for comments.Next(ctx) {
+		ids = append(ids, comments.Value().ID)
+	}
+	require.NoError(t, comments.Err())
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ids)
+
+	comments = c.Comments(1, ListCommentsParams{Limit: 1000})
+	require.False(t, comments.Next(ctx))
+	require.ErrorIs(t, comments.Err(), ErrBadRequest)
+}
+
+// countingSource counts the tokens it hands out.
+type countingSource struct {
+	calls atomic.Int32
+}
+
+func (s *countingSource) Token(context.Context) (string, error) {
+	s.calls.Add(1)
+	return "token", nil
+}
+
+func TestClient_Retries(t *testing.T) {
+	ctx := context.Background()
+
+	// flaky answers the first failures requests with status and then 200
+	flaky := func(status, failures int) (*httptest.Server, *atomic.Int32) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if int(calls.Add(1)) <= failures {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"post_id": 3}`))
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &calls
+	}
+
+	newFlakyClient := func(baseURL string, source TokenSource) *Client {
+		c, err := New(Config{
+			BaseURL:     baseURL,
+			TokenSource: source,
+			MaxRetries:  2,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  5 * time.Millisecond,
+		}, nil)
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("Server errors of idempotent requests are retried", func(t *testing.T) {
+		srv, calls := flaky(http.StatusServiceUnavailable, 2)
+		source := &countingSource{}
+
+		require.NoError(t, newFlakyClient(srv.URL, source).DeletePost(ctx, 3))
+		require.EqualValues(t, 3, calls.Load())
+		require.EqualValues(t, 3, source.calls.Load())
+	})
+
+	t.Run("Retries give up", func(t *testing.T) {
+		srv, calls := flaky(http.StatusBadGateway, 5)
+
+		err := newFlakyClient(srv.URL, StaticToken("token")).DeletePost(ctx, 3)
+		require.ErrorIs(t, err, ErrServer)
+		require.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("Server errors of creations are not retried", func(t *testing.T) {
+		srv, calls := flaky(http.StatusInternalServerError, 1)
+
+		_, err := newFlakyClient(srv.URL, StaticToken("token")).CreatePost(ctx, NewPost{Title: "t", Content: "c"})
+		require.ErrorIs(t, err, ErrServer)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("Rate limited creations are retried", func(t *testing.T) {
+		srv, calls := flaky(http.StatusTooManyRequests, 1)
+
+		id, err := newFlakyClient(srv.URL, StaticToken("token")).CreatePost(ctx, NewPost{Title: "t", Content: "c"})
+		require.NoError(t, err)
+		require.Equal(t, 3, id)
+		require.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("Retries stop with the context", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer srv.Close()
+
+		c := newFlakyClient(srv.URL, nil)
+		c.config.MinBackoff, c.config.MaxBackoff = time.Hour, time.Hour
+
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		err := c.DeletePost(ctx, 3)
+		require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{BaseURL: "localhost:8080"}, nil)
+	require.Error(t, err)
+
+	c, err := New(Config{BaseURL: "http://localhost:8080/api/"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultMaxRetries, c.config.MaxRetries)
+	require.Equal(t, "/api", c.baseURL.Path)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Comment is a comment with its replies. Deleted comments keep their place in
+// the thread with an empty content.
+type Comment struct {
+	ID        int        `json:"id"`
+	PostID    int        `json:"post_id"`
+	ParentID  *int       `json:"parent_id,omitempty"`
+	AuthorID  int        `json:"author_id"`
+	Content   string     `json:"content"`
+	Status    string     `json:"status"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Replies   []*Comment `json:"replies,omitempty"`
+}
+
+// CommentPage is a page of threads, NextCursor is zero on the last page.
+type CommentPage struct {
+	Comments   []*Comment `json:"comments"`
+	NextCursor int        `json:"next_cursor,omitempty"`
+}
+
+// ListCommentsParams pages through comments, the server default is used for
+// a zero Limit and the first page for a zero Cursor.
+type ListCommentsParams struct {
+	Limit  int
+	Cursor int
+}
+
+// ListComments returns a page of the approved threads of the post.
+func (c *Client) ListComments(ctx context.Context, postID int, params ListCommentsParams) (CommentPage, error) {
+	query := url.Values{}
+	setInt(query, "limit", params.Limit)
+	setInt(query, "cursor", params.Cursor)
+
+	var page CommentPage
+	err := c.do(ctx, call{method: http.MethodGet, path: postPath(postID) + "/comments", query: query, out: &page})
+	return page, err
+}
+
+// Comments iterates over all the approved threads of the post.
+func (c *Client) Comments(postID int, params ListCommentsParams) *Iterator[*Comment] {
+	return newIterator(func(ctx context.Context) ([]*Comment, bool, error) {
+		page, err := c.ListComments(ctx, postID, params)
+		if err != nil {
+			return nil, false, err
+		}
+
+		params.Cursor = page.NextCursor
+		return page.Comments, page.NextCursor != 0, nil
+	})
+}
+
+// CreateComment comments on the post, or replies to parentID when it is not
+// zero. The comment waits for moderation.
+func (c *Client) CreateComment(ctx context.Context, postID int, content string, parentID int) (int, error) {
+	req := struct {
+		Content  string `json:"content"`
+		ParentID *int   `json:"parent_id,omitempty"`
+	}{Content: content}
+	if parentID != 0 {
+		req.ParentID = &parentID
+	}
+
+	var res struct {
+		CommentID int `json:"comment_id"`
+	}
+	err := c.do(ctx, call{method: http.MethodPost, path: postPath(postID) + "/comments", in: req, out: &res})
+	return res.CommentID, err
+}
+
+func (c *Client) UpdateComment(ctx context.Context, id int, content string) error {
+	req := struct {
+		Content string `json:"content"`
+	}{Content: content}
+
+	return c.do(ctx, call{method: http.MethodPut, path: "/comments/" + strconv.Itoa(id), in: req})
+}
+
+func (c *Client) DeleteComment(ctx context.Context, id int) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: "/comments/" + strconv.Itoa(id)})
+}
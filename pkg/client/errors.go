@@ -0,0 +1,78 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadRequest           = errors.New("bad request")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrNotFound             = errors.New("not found")
+	ErrGone                 = errors.New("gone")
+	ErrTooLarge             = errors.New("request too large")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrRateLimited          = errors.New("rate limited")
+	ErrServer               = errors.New("server error")
+)
+
+// APIError is an error response of the API. It matches one of the Err
+// variables with errors.Is, Message is what the server said, often nothing.
+type APIError struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+	// RetryAfter is how long a rate limited client should wait.
+	RetryAfter time.Duration
+}
+
+func newAPIError(res *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorSize))
+
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		RequestID:  res.Header.Get("X-Request-ID"),
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		e.RetryAfter = retryAfter(res.Header, 0)
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("news-crud: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusGone:
+		return ErrGone
+	case e.StatusCode == http.StatusRequestEntityTooLarge:
+		return ErrTooLarge
+	case e.StatusCode == http.StatusUnsupportedMediaType:
+		return ErrUnsupportedMediaType
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import "context"
+
+// Iterator walks a paginated list, fetching the pages as it goes:
+//
+//	it := c.Posts(client.ListPostsParams{})
+//	for it.Next(ctx) {
+//		post := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	fetch func(ctx context.Context) (page []T, more bool, err error)
+
+	page []T
+	cur  T
+	more bool
+	err  error
+}
+
+func newIterator[T any](fetch func(ctx context.Context) ([]T, bool, error)) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, more: true}
+}
+
+// Next advances to the next item, false means the list is over or a page
+// could not be fetched, see Err.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if !it.more || it.err != nil {
+			return false
+		}
+
+		it.page, it.more, it.err = it.fetch(ctx)
+		if it.err != nil {
+			return false
+		}
+	}
+
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Value is the current item.
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err is the error that stopped the iteration, nil at the end of the list.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
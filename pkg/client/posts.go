@@ -0,0 +1,245 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ContentFormatPlain    = "plain"
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+
+	AuthorRoleLead        = "lead"
+	AuthorRoleContributor = "contributor"
+
+	// DefaultPageSize is the page size of iterators when none is given.
+	DefaultPageSize = 50
+)
+
+// Post is an article as the API returns it. When fields are selected only
+// those are set.
+type Post struct {
+	ID            int
+	Title         string
+	Content       string `json:",omitempty"`
+	ContentFormat string
+	ContentHTML   string `json:",omitempty"`
+	Excerpt       string
+	WordCount     int
+	ReadingTime   int
+	AuthorID      int
+	Authors       []Author
+	Tags          []string
+	Media         []Media
+	Reactions     map[string]int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type Author struct {
+	UserID   int
+	Role     string
+	Position int
+}
+
+type Media struct {
+	ID          int
+	URL         string
+	ContentType string
+	Size        int64
+	Width       int
+	Height      int
+	Blurhash    string
+	Variants    []MediaVariant
+}
+
+type MediaVariant struct {
+	Name        string
+	URL         string
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+}
+
+type NewPost struct {
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	ContentFormat string   `json:"content_format,omitempty"`
+	Excerpt       string   `json:"excerpt,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// GetPostParams selects the form of the content, "source", "html" or both
+// when empty, and the fields to return, all when empty.
+type GetPostParams struct {
+	Content string
+	Fields  []string
+}
+
+// ListPostsParams filters a list of posts. View "compact" leaves out the
+// content. A zero Limit returns all posts.
+type ListPostsParams struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	Sort     string
+	Content  string
+	Fields   []string
+	View     string
+	Limit    int
+	Offset   int
+}
+
+type TrendingPost struct {
+	PostID int     `json:"post_id"`
+	Title  string  `json:"title"`
+	Views  int     `json:"views"`
+	Score  float64 `json:"score"`
+}
+
+func (c *Client) CreatePost(ctx context.Context, post NewPost) (int, error) {
+	var res struct {
+		PostID int `json:"post_id"`
+	}
+
+	err := c.do(ctx, call{method: http.MethodPost, path: "/posts", in: post, out: &res})
+	return res.PostID, err
+}
+
+func (c *Client) GetPost(ctx context.Context, id int, params GetPostParams) (Post, error) {
+	query := url.Values{}
+	setString(query, "content", params.Content)
+	setString(query, "fields", strings.Join(params.Fields, ","))
+
+	var post Post
+	err := c.do(ctx, call{method: http.MethodGet, path: postPath(id), query: query, out: &post})
+	return post, err
+}
+
+// ListPosts returns one page of posts, an empty page when none matches.
+func (c *Client) ListPosts(ctx context.Context, params ListPostsParams) ([]Post, error) {
+	query := url.Values{}
+	if !params.DateFrom.IsZero() {
+		query.Set("dateFrom", params.DateFrom.Format(time.RFC3339))
+	}
+	if !params.DateTo.IsZero() {
+		query.Set("dateTo", params.DateTo.Format(time.RFC3339))
+	}
+	setString(query, "sort", params.Sort)
+	setString(query, "content", params.Content)
+	setString(query, "fields", strings.Join(params.Fields, ","))
+	setString(query, "view", params.View)
+	setInt(query, "limit", params.Limit)
+	setInt(query, "offset", params.Offset)
+
+	var posts []Post
+	err := c.do(ctx, call{method: http.MethodGet, path: "/posts", query: query, out: &posts})
+	if errors.Is(err, ErrNotFound) {
+		return []Post{}, nil
+	}
+	return posts, err
+}
+
+// Posts iterates over all the posts matching params, params.Limit posts or
+// DefaultPageSize at a time starting at params.Offset.
+func (c *Client) Posts(params ListPostsParams) *Iterator[Post] {
+	if params.Limit <= 0 {
+		params.Limit = DefaultPageSize
+	}
+
+	return newIterator(func(ctx context.Context) ([]Post, bool, error) {
+		posts, err := c.ListPosts(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+
+		params.Offset += len(posts)
+		return posts, len(posts) == params.Limit, nil
+	})
+}
+
+// UpdatePost replaces the post with post.ID.
+func (c *Client) UpdatePost(ctx context.Context, post Post) error {
+	return c.do(ctx, call{method: http.MethodPut, path: postPath(post.ID), in: post})
+}
+
+func (c *Client) DeletePost(ctx context.Context, id int) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: postPath(id)})
+}
+
+// SetPostAuthors replaces the byline, the order is the display order and
+// exactly one author should be the lead.
+func (c *Client) SetPostAuthors(ctx context.Context, id int, authors []Author) error {
+	type author struct {
+		UserID int    `json:"user_id"`
+		Role   string `json:"role"`
+	}
+
+	req := struct {
+		Authors []author `json:"authors"`
+	}{Authors: make([]author, len(authors))}
+	for i, a := range authors {
+		req.Authors[i] = author{UserID: a.UserID, Role: a.Role}
+	}
+
+	return c.do(ctx, call{method: http.MethodPut, path: postPath(id) + "/authors", in: req})
+}
+
+// SetPostMedia replaces the media of the post, in display order.
+func (c *Client) SetPostMedia(ctx context.Context, id int, mediaIDs []int) error {
+	req := struct {
+		MediaIDs []int `json:"media_ids"`
+	}{MediaIDs: mediaIDs}
+
+	return c.do(ctx, call{method: http.MethodPut, path: postPath(id) + "/media", in: req})
+}
+
+// React puts the reaction of the user on the post, replacing the previous one.
+func (c *Client) React(ctx context.Context, id int, reaction string) error {
+	return c.do(ctx, call{method: http.MethodPut, path: postPath(id) + "/reactions/" + reaction})
+}
+
+func (c *Client) Unreact(ctx context.Context, id int, reaction string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: postPath(id) + "/reactions/" + reaction})
+}
+
+// Reactions lists the reactions readers may leave.
+func (c *Client) Reactions(ctx context.Context) ([]string, error) {
+	var reactions []string
+	err := c.do(ctx, call{method: http.MethodGet, path: "/reactions", out: &reactions})
+	return reactions, err
+}
+
+// Trending returns the most viewed posts of the window, "1h", "24h" or "7d",
+// the server defaults are used for zero values.
+func (c *Client) Trending(ctx context.Context, window string, limit int) ([]TrendingPost, error) {
+	query := url.Values{}
+	setString(query, "window", window)
+	setInt(query, "limit", limit)
+
+	var trending []TrendingPost
+	err := c.do(ctx, call{method: http.MethodGet, path: "/posts/trending", query: query, out: &trending})
+	return trending, err
+}
+
+func postPath(id int) string {
+	return "/posts/" + strconv.Itoa(id)
+}
+
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setInt(query url.Values, key string, value int) {
+	if value != 0 {
+		query.Set(key, strconv.Itoa(value))
+	}
+}
@@ -0,0 +1,77 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFeed = Feed{
+	Title:  "News",
+	Link:   "https://example.com/posts",
+	Self:   "https://example.com/feeds/rss.xml",
+	Author: "News",
+	Items: []Item{
+		{
+			ID:         "https://example.com/posts/2",
+			Title:      "Tom & Jerry <live>",
+			Link:       "https://example.com/posts/2",
+			Content:    `<script>alert("x")</script>`,
+			Categories: []string{"cartoons"},
+			Published:  time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+			Updated:    time.Date(2024, 5, 3, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			ID:        "https://example.com/posts/1",
+			Title:     "First",
+			Link:      "https://example.com/posts/1",
+			Content:   "hello",
+			Published: time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("EEST", 3*60*60)),
+			Updated:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("EEST", 3*60*60)),
+		},
+	},
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, testFeed))
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, xml.Header))
+	require.Contains(t, out, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`)
+	require.Contains(t, out, `<title>Tom &amp; Jerry &lt;live&gt;</title>`)
+	require.Contains(t, out, `<description>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</description>`)
+	require.Contains(t, out, `<guid isPermaLink="false">https://example.com/posts/2</guid>`)
+	require.Contains(t, out, `<pubDate>Thu, 02 May 2024 10:00:00 +0000</pubDate>`)
+	require.Contains(t, out, `<pubDate>Wed, 01 May 2024 07:00:00 +0000</pubDate>`)
+	require.Contains(t, out, `<lastBuildDate>Fri, 03 May 2024 12:30:00 +0000</lastBuildDate>`)
+	require.Contains(t, out, `<category>cartoons</category>`)
+
+	var doc rss
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Channel.Items, 2)
+	require.Equal(t, `<script>alert("x")</script>`, doc.Channel.Items[0].Description)
+}
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, testFeed))
+
+	out := buf.String()
+	require.Contains(t, out, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	require.Contains(t, out, `<updated>2024-05-03T12:30:00Z</updated>`)
+	require.Contains(t, out, `<id>https://example.com/posts/1</id>`)
+	require.Contains(t, out, `<published>2024-05-01T07:00:00Z</published>`)
+	require.Contains(t, out, `<category term="cartoons"></category>`)
+	require.Contains(t, out, `<content type="html">&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</content>`)
+}
+
+func TestWriteAtom_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, Feed{Title: "News", Self: "https://example.com/feeds/atom.xml"}))
+
+	require.Contains(t, buf.String(), `<updated>1970-01-01T00:00:00Z</updated>`)
+}
@@ -0,0 +1,49 @@
+// Package frontmatter splits documents into their YAML front matter, the
+// block between two "---" lines at the very start, and their body.
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+var ErrUnclosed = errors.New("front matter is not closed")
+
+// Parse decodes the front matter into v and returns the body. A document
+// without front matter is all body and leaves v untouched.
+func Parse(data []byte, v any) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+
+	first, rest, _ := cutLine(data)
+	if string(first) != delimiter {
+		return data, nil
+	}
+
+	var header []byte
+	for len(rest) > 0 {
+		var line []byte
+		line, rest, _ = cutLine(rest)
+
+		if string(line) == delimiter {
+			if err := yaml.Unmarshal(header, v); err != nil {
+				return nil, fmt.Errorf("cant decode front matter: %w", err)
+			}
+			return rest, nil
+		}
+
+		header = append(header, line...)
+		header = append(header, '\n')
+	}
+
+	return nil, ErrUnclosed
+}
+
+// cutLine splits the first line off, without its line ending.
+func cutLine(data []byte) (line, rest []byte, found bool) {
+	line, rest, found = bytes.Cut(data, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r")), rest, found
+}
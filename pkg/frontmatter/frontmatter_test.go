@@ -0,0 +1,67 @@
+package frontmatter
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type header struct {
+	Title string   `yaml:"title"`
+	Tags  []string `yaml:"tags"`
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		wantHeader header
+		wantBody   string
+		wantErr    error
+	}{
+		{
+			name:       "Front matter and body",
+			doc:        "---\ntitle: Hello\ntags: [go, cli]\n---\n# Hello\n\nworld\n",
+			wantHeader: header{Title: "Hello", Tags: []string{"go", "cli"}},
+			wantBody:   "# Hello\n\nworld\n",
+		},
+		{
+			name:       "Windows line endings and BOM",
+			doc:        "\uFEFF---\r\ntitle: Hello\r\n---\r\nbody",
+			wantHeader: header{Title: "Hello"},
+			wantBody:   "body",
+		},
+		{
+			name:     "No front matter",
+			doc:      "# Title\n---\n",
+			wantBody: "# Title\n---\n",
+		},
+		{
+			name:       "Empty body",
+			doc:        "---\ntitle: Hello\n---",
+			wantHeader: header{Title: "Hello"},
+		},
+		{
+			name:    "Unclosed",
+			doc:     "---\ntitle: Hello\n",
+			wantErr: ErrUnclosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h header
+			body, err := Parse([]byte(tt.doc), &h)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantHeader, h)
+			require.Equal(t, tt.wantBody, string(body))
+		})
+	}
+
+	_, err := Parse([]byte("---\ntitle: [\n---\n"), &header{})
+	require.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package imaging
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+const base83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// blurhashSize is the width and height the image is scaled down to before it
+// is hashed, the placeholder is blurry anyway.
+const blurhashSize = 64
+
+// Blurhash encodes the image as a BlurHash placeholder (https://blurha.sh)
+// made of x by y components, each between 1 and 9.
+func Blurhash(img image.Image, x, y int) string {
+	x, y = min(max(x, 1), 9), min(max(y, 1), 9)
+
+	small := Resize(img, Rendition{Width: blurhashSize, Height: blurhashSize})
+	w, h := small.Rect.Dx(), small.Rect.Dy()
+
+	// the image is converted to linear light once
+	linear := make([][3]float64, w*h)
+	for py := 0; py < h; py++ {
+		for px := 0; px < w; px++ {
+			o := small.PixOffset(px, py)
+			linear[py*w+px] = [3]float64{
+				sRGBToLinear(small.Pix[o]),
+				sRGBToLinear(small.Pix[o+1]),
+				sRGBToLinear(small.Pix[o+2]),
+			}
+		}
+	}
+
+	factors := make([][3]float64, 0, x*y)
+	for j := 0; j < y; j++ {
+		for i := 0; i < x; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+
+			var f [3]float64
+			for py := 0; py < h; py++ {
+				for px := 0; px < w; px++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(px)/float64(w)) *
+						math.Cos(math.Pi*float64(j)*float64(py)/float64(h))
+					c := linear[py*w+px]
+					f[0] += basis * c[0]
+					f[1] += basis * c[1]
+					f[2] += basis * c[2]
+				}
+			}
+
+			scale := normalisation / float64(w*h)
+			factors = append(factors, [3]float64{f[0] * scale, f[1] * scale, f[2] * scale})
+		}
+	}
+
+	var b strings.Builder
+	encode83(&b, (x-1)+(y-1)*9, 1)
+
+	maximum := 1.0
+	if len(factors) > 1 {
+		actual := 0.0
+		for _, f := range factors[1:] {
+			actual = max(actual, math.Abs(f[0]), math.Abs(f[1]), math.Abs(f[2]))
+		}
+
+		quantised := int(max(0, min(82, math.Floor(actual*166-0.5))))
+		maximum = float64(quantised+1) / 166
+		encode83(&b, quantised, 1)
+	} else {
+		encode83(&b, 0, 1)
+	}
+
+	dc := factors[0]
+	encode83(&b, linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4)
+
+	for _, f := range factors[1:] {
+		quant := func(v float64) int {
+			return int(max(0, min(18, math.Floor(signPow(v/maximum, 0.5)*9+9.5))))
+		}
+		encode83(&b, quant(f[0])*19*19+quant(f[1])*19+quant(f[2]), 2)
+	}
+
+	return b.String()
+}
+
+func encode83(b *strings.Builder, value, length int) {
+	for i := 1; i <= length; i++ {
+		digit := value / int(math.Pow(83, float64(length-i))) % 83
+		b.WriteByte(base83[digit])
+	}
+}
+
+func sRGBToLinear(v uint8) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = max(0, min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
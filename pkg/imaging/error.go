@@ -0,0 +1,10 @@
+package imaging
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrMalformed         = errors.New("malformed image")
+	ErrInvalidRendition  = errors.New("invalid rendition")
+	ErrInvalidQuality    = errors.New("quality must be from 1 to 100")
+)
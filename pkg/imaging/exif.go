@@ -0,0 +1,96 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const tagOrientation = 0x0112
+
+// orientationTIFF is an EXIF block holding nothing but the orientation: a
+// big endian TIFF header and a single IFD with one SHORT entry.
+var orientationTIFF = []byte{
+	'M', 'M', 0x00, 0x2a, 0x00, 0x00, 0x00, 0x08, // header, IFD0 at offset 8
+	0x00, 0x01, // one entry
+	0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, // orientation, SHORT, count 1, value
+	0x00, 0x00, 0x00, 0x00, // no next IFD
+}
+
+func appendOrientationTIFF(out []byte, orientation int) []byte {
+	start := len(out)
+	out = append(out, orientationTIFF...)
+	binary.BigEndian.PutUint16(out[start+18:], uint16(orientation))
+	return out
+}
+
+// orientation reads the orientation tag of IFD0 of the EXIF TIFF data, 1 (no
+// transformation) if it is missing or invalid.
+func orientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		// the value of a single SHORT is stored in the entry itself
+		if order.Uint16(tiff[entry:]) == tagOrientation && order.Uint16(tiff[entry+2:]) == 3 {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+
+	return 1
+}
+
+// jpegOrientation finds the EXIF orientation of a JPEG file.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xff || data[1] != jpegSOI {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		if marker == jpegSOS {
+			return 1
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if end > len(data) {
+			return 1
+		}
+
+		if segment := data[i+4 : end]; marker == jpegAPP1 && bytes.HasPrefix(segment, exifHeader) {
+			return orientation(segment[len(exifHeader):])
+		}
+
+		i = end
+	}
+
+	return 1
+}
@@ -0,0 +1,236 @@
+// Package imaging decodes, resizes and re-encodes uploaded images and strips
+// their metadata.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatWebP = "webp"
+)
+
+// Supported reports whether images of the media type can be decoded and
+// stripped by the package.
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// ContentType is the media type of the format.
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+// Extension is the file extension of the format.
+func Extension(format string) string {
+	if format == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// Rendition is a variant of an image. A zero Width or Height keeps the aspect
+// ratio, Crop fills exactly Width x Height and cuts off what does not fit.
+// Images are never scaled up.
+type Rendition struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// ParseRendition parses "name:WIDTHxHEIGHT" with an optional ":crop" suffix,
+// "thumb:320x320:crop" or "large:1920x0".
+func ParseRendition(s string) (Rendition, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return Rendition{}, fmt.Errorf("%w: %q", ErrInvalidRendition, s)
+	}
+
+	width, height, ok := strings.Cut(parts[1], "x")
+	if !ok {
+		return Rendition{}, fmt.Errorf("%w: %q", ErrInvalidRendition, s)
+	}
+
+	r := Rendition{Name: parts[0]}
+
+	var err error
+	if r.Width, err = strconv.Atoi(width); err != nil || r.Width < 0 {
+		return Rendition{}, fmt.Errorf("%w: %q", ErrInvalidRendition, s)
+	}
+	if r.Height, err = strconv.Atoi(height); err != nil || r.Height < 0 {
+		return Rendition{}, fmt.Errorf("%w: %q", ErrInvalidRendition, s)
+	}
+	if r.Width == 0 && r.Height == 0 {
+		return Rendition{}, fmt.Errorf("%w: %q", ErrInvalidRendition, s)
+	}
+
+	if len(parts) == 3 {
+		if parts[2] != "crop" || r.Width == 0 || r.Height == 0 {
+			return Rendition{}, fmt.Errorf("%w: %q", ErrInvalidRendition, s)
+		}
+		r.Crop = true
+	}
+
+	return r, nil
+}
+
+// ParseFormat checks the output format is one the package can encode.
+func ParseFormat(s string) (string, error) {
+	switch s {
+	case FormatJPEG, FormatWebP:
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+}
+
+// DecodeConfig returns the dimensions of the image as it is displayed, that
+// is with the EXIF orientation applied, without decoding it.
+func DecodeConfig(data []byte) (image.Config, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	if jpegOrientation(data) >= 5 {
+		config.Width, config.Height = config.Height, config.Width
+	}
+
+	return config, nil
+}
+
+// Decode decodes the image and turns it the right way up. Only the first
+// frame of an animation is decoded.
+func Decode(data []byte) (*image.NRGBA, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+
+	return orient(nrgba, jpegOrientation(data)), nil
+}
+
+// orient applies the EXIF orientation, the rotations are clockwise.
+func orient(src *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 270
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+
+	return dst
+}
+
+// Resize scales the image to the rendition.
+func Resize(img image.Image, r Rendition) *image.NRGBA {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	var src image.Rectangle
+	var dw, dh int
+
+	if r.Crop {
+		scale := max(float64(r.Width)/w, float64(r.Height)/h)
+		tw, th := float64(r.Width), float64(r.Height)
+		if scale > 1 {
+			// the image is smaller than the box, crop to its aspect ratio instead
+			tw, th, scale = tw/scale, th/scale, 1
+		}
+
+		cw, ch := tw/scale, th/scale
+		x0 := b.Min.X + int((w-cw)/2)
+		y0 := b.Min.Y + int((h-ch)/2)
+		src = image.Rect(x0, y0, x0+round(cw), y0+round(ch))
+		dw, dh = round(tw), round(th)
+	} else {
+		scale := 1.0
+		if r.Width > 0 {
+			scale = min(scale, float64(r.Width)/w)
+		}
+		if r.Height > 0 {
+			scale = min(scale, float64(r.Height)/h)
+		}
+
+		src = b
+		dw, dh = round(w*scale), round(h*scale)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, max(dw, 1), max(dh, 1)))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, src, xdraw.Src, nil)
+
+	return dst
+}
+
+func round(f float64) int {
+	return int(f + 0.5)
+}
+
+// Encode writes the image in the format. JPEG has no transparency, so the
+// image is put on a white background, jpegQuality is its quality. WebP is
+// encoded losslessly, there is no lossy WebP encoder in pure Go.
+func Encode(w io.Writer, img image.Image, format string, jpegQuality int) error {
+	switch format {
+	case FormatJPEG:
+		b := img.Bounds()
+		flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, b.Min, draw.Over)
+
+		return jpeg.Encode(w, flat, &jpeg.Options{Quality: jpegQuality})
+	case FormatWebP:
+		return nativewebp.Encode(w, img, nil)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
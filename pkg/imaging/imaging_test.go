@@ -0,0 +1,247 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func gradient(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y * 2), B: uint8((x + y) % 256), A: 255})
+		}
+	}
+	return img
+}
+
+// exifWithGPS is little endian EXIF TIFF data with an orientation and a
+// camera serial that must not survive stripping.
+func exifWithGPS(orientation uint16) []byte {
+	tiff := []byte{'I', 'I', 0x2a, 0x00, 0x08, 0x00, 0x00, 0x00, 0x02, 0x00}
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0xa431) // body serial number, ASCII
+	tiff = binary.LittleEndian.AppendUint16(tiff, 2)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 38)
+	tiff = binary.LittleEndian.AppendUint16(tiff, tagOrientation)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	return append(tiff, "SERIAL1\x00"...)
+}
+
+func segment(marker byte, data []byte) []byte {
+	out := []byte{0xff, marker}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(data)+2))
+	return append(out, data...)
+}
+
+func TestStripMetadata_JPEG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, gradient(40, 20), nil))
+	encoded := buf.Bytes()
+
+	var data []byte
+	data = append(data, encoded[:2]...)
+	data = append(data, segment(jpegAPP1, append([]byte("Exif\x00\x00"), exifWithGPS(6)...))...)
+	data = append(data, segment(jpegAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>SERIAL1</x:xmpmeta>"))...)
+	data = append(data, segment(jpegCOM, []byte("shot by SERIAL1"))...)
+	data = append(data, encoded[2:]...)
+
+	require.Equal(t, 6, jpegOrientation(data))
+
+	stripped, err := StripMetadata("image/jpeg", data)
+	require.NoError(t, err)
+	require.NotContains(t, string(stripped), "SERIAL1")
+	require.NotContains(t, string(stripped), "xmpmeta")
+	require.Equal(t, 6, jpegOrientation(stripped))
+
+	config, err := DecodeConfig(stripped)
+	require.NoError(t, err)
+	require.Equal(t, 20, config.Width)
+	require.Equal(t, 40, config.Height)
+
+	img, err := Decode(stripped)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+}
+
+func TestStripMetadata_JPEG_NoOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, gradient(8, 8), nil))
+	encoded := buf.Bytes()
+
+	var data []byte
+	data = append(data, encoded[:2]...)
+	data = append(data, segment(jpegAPP1, append([]byte("Exif\x00\x00"), exifWithGPS(1)...))...)
+	data = append(data, encoded[2:]...)
+
+	stripped, err := StripMetadata("image/jpeg", data)
+	require.NoError(t, err)
+	require.Equal(t, encoded, stripped)
+}
+
+func chunk(chunkType string, data []byte) []byte {
+	out := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	out = append(out, chunkType...)
+	out = append(out, data...)
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(chunkType))
+	crc.Write(data)
+	return binary.BigEndian.AppendUint32(out, crc.Sum32())
+}
+
+func TestStripMetadata_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, gradient(8, 8)))
+	encoded := buf.Bytes()
+
+	// the header chunk is 25 bytes after the signature
+	ihdrEnd := len(pngSignature) + 25
+
+	var data []byte
+	data = append(data, encoded[:ihdrEnd]...)
+	data = append(data, chunk("tEXt", []byte("Author\x00SERIAL1"))...)
+	data = append(data, chunk("eXIf", exifWithGPS(1))...)
+	data = append(data, encoded[ihdrEnd:]...)
+
+	stripped, err := StripMetadata("image/png", data)
+	require.NoError(t, err)
+	require.Equal(t, encoded, stripped)
+}
+
+func riffChunk(fourCC string, data []byte) []byte {
+	out := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+	out = append(out, data...)
+	if len(data)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func TestStripMetadata_WebP(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, gradient(8, 4), FormatWebP, 0))
+	vp8l := buf.Bytes()[12:]
+
+	vp8x := []byte{webpFlagEXIF | webpFlagXMP, 0, 0, 0, 7, 0, 0, 3, 0, 0}
+
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, vp8l...)
+	body = append(body, riffChunk("EXIF", exifWithGPS(1))...)
+	body = append(body, riffChunk("XMP ", []byte("<x:xmpmeta>SERIAL1</x:xmpmeta>"))...)
+	data := append(riffChunk("RIFF", body)[:8], body...)
+
+	stripped, err := StripMetadata("image/webp", data)
+	require.NoError(t, err)
+	require.NotContains(t, string(stripped), "SERIAL1")
+	require.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:]))
+	require.Equal(t, byte(0), stripped[20]&(webpFlagEXIF|webpFlagXMP))
+
+	img, err := Decode(stripped)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 8, 4), img.Bounds())
+}
+
+func TestStripMetadata_GIF(t *testing.T) {
+	var buf bytes.Buffer
+	paletted := image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{color.Black, color.White})
+	require.NoError(t, gif.Encode(&buf, paletted, nil))
+	encoded := buf.Bytes()
+
+	comment := []byte{0x21, 0xfe, 7}
+	comment = append(comment, "SERIAL1"...)
+	comment = append(comment, 0)
+
+	data := append(append([]byte{}, encoded[:len(encoded)-1]...), comment...)
+	data = append(data, 0x3b)
+
+	stripped, err := StripMetadata("image/gif", data)
+	require.NoError(t, err)
+	require.Equal(t, encoded, stripped)
+}
+
+func TestStripMetadata_Malformed(t *testing.T) {
+	_, err := StripMetadata("image/jpeg", []byte{0xff, 0xd8, 0xff, 0xe1, 0xff})
+	require.ErrorIs(t, err, ErrMalformed)
+
+	_, err = StripMetadata("image/bmp", nil)
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestParseRendition(t *testing.T) {
+	r, err := ParseRendition("thumb:320x180:crop")
+	require.NoError(t, err)
+	require.Equal(t, Rendition{Name: "thumb", Width: 320, Height: 180, Crop: true}, r)
+
+	r, err = ParseRendition("large:1920x0")
+	require.NoError(t, err)
+	require.Equal(t, Rendition{Name: "large", Width: 1920}, r)
+
+	for _, s := range []string{"", "thumb", "thumb:320", "thumb:0x0", ":10x10", "thumb:10x0:crop", "thumb:10x10:fit", "thumb:-1x10"} {
+		_, err = ParseRendition(s)
+		require.ErrorIs(t, err, ErrInvalidRendition, s)
+	}
+}
+
+func TestResize(t *testing.T) {
+	img := gradient(400, 200)
+
+	require.Equal(t, image.Rect(0, 0, 100, 50), Resize(img, Rendition{Width: 100}).Bounds())
+	require.Equal(t, image.Rect(0, 0, 200, 100), Resize(img, Rendition{Width: 300, Height: 100}).Bounds())
+	require.Equal(t, image.Rect(0, 0, 100, 100), Resize(img, Rendition{Width: 100, Height: 100, Crop: true}).Bounds())
+
+	// never scaled up
+	require.Equal(t, image.Rect(0, 0, 400, 200), Resize(img, Rendition{Width: 800}).Bounds())
+	require.Equal(t, image.Rect(0, 0, 200, 200), Resize(img, Rendition{Width: 1000, Height: 1000, Crop: true}).Bounds())
+}
+
+func TestOrient(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{B: 255, A: 255})
+
+	// rotated by 90 degrees clockwise the left pixel ends up on top
+	rotated := orient(img, 6)
+	require.Equal(t, image.Rect(0, 0, 1, 2), rotated.Bounds())
+	require.Equal(t, color.NRGBA{R: 255, A: 255}, rotated.At(0, 0))
+	require.Equal(t, color.NRGBA{B: 255, A: 255}, rotated.At(0, 1))
+
+	rotated = orient(img, 8)
+	require.Equal(t, color.NRGBA{B: 255, A: 255}, rotated.At(0, 0))
+
+	mirrored := orient(img, 2)
+	require.Equal(t, color.NRGBA{B: 255, A: 255}, mirrored.At(0, 0))
+}
+
+func TestEncode(t *testing.T) {
+	for _, format := range []string{FormatJPEG, FormatWebP} {
+		var buf bytes.Buffer
+		require.NoError(t, Encode(&buf, gradient(30, 10), format, 80))
+
+		config, decoded, err := image.DecodeConfig(&buf)
+		require.NoError(t, err)
+		require.Equal(t, format, decoded)
+		require.Equal(t, 30, config.Width)
+		require.Equal(t, 10, config.Height)
+	}
+
+	require.ErrorIs(t, Encode(&bytes.Buffer{}, gradient(1, 1), "avif", 80), ErrUnsupportedFormat)
+}
+
+func TestBlurhash(t *testing.T) {
+	// checked against the reference implementation
+	require.Equal(t, "LrDm,aB1wtW@l[aujyf3gFfnfNfi", Blurhash(gradient(200, 120), 4, 3))
+	require.Len(t, Blurhash(gradient(10, 10), 1, 1), 6)
+}
@@ -0,0 +1,266 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// StripMetadata removes the metadata that can identify a source, EXIF (with
+// GPS positions and camera serials), XMP, IPTC, comments and text chunks,
+// without re-encoding the image. The EXIF orientation of a JPEG is kept, so
+// the image is still displayed the right way up.
+func StripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	case "image/gif":
+		return stripGIF(data)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+const (
+	jpegSOI   = 0xd8
+	jpegSOS   = 0xda
+	jpegAPP0  = 0xe0
+	jpegAPP1  = 0xe1
+	jpegAPP2  = 0xe2
+	jpegAPP14 = 0xee
+	jpegAPP15 = 0xef
+	jpegCOM   = 0xfe
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// stripJPEG keeps JFIF (APP0), the ICC profile (APP2) and the Adobe color
+// transform (APP14), the other application segments and comments are dropped.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != jpegSOI {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+
+	orientationKept := false
+	for i := 2; ; {
+		if i >= len(data) || data[i] != 0xff {
+			return nil, ErrMalformed
+		}
+		// markers may be preceded by any number of fill bytes
+		for i < len(data) && data[i] == 0xff {
+			i++
+		}
+		if i >= len(data) {
+			return nil, ErrMalformed
+		}
+
+		marker := data[i]
+		i++
+
+		// the entropy coded data follows the start of scan, it is copied as is
+		if marker == jpegSOS {
+			out = append(out, 0xff, marker)
+			return append(out, data[i:]...), nil
+		}
+
+		if marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			out = append(out, 0xff, marker)
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, ErrMalformed
+		}
+		end := i + int(binary.BigEndian.Uint16(data[i:]))
+		if end < i+2 || end > len(data) {
+			return nil, ErrMalformed
+		}
+		segment := data[i+2 : end]
+
+		switch {
+		case marker == jpegAPP1 && bytes.HasPrefix(segment, exifHeader):
+			if o := orientation(segment[len(exifHeader):]); o > 1 && !orientationKept {
+				out = append(out, 0xff, jpegAPP1)
+				out = binary.BigEndian.AppendUint16(out, uint16(2+len(exifHeader)+len(orientationTIFF)))
+				out = append(out, exifHeader...)
+				out = appendOrientationTIFF(out, o)
+				orientationKept = true
+			}
+		case marker == jpegAPP0, marker == jpegAPP2, marker == jpegAPP14:
+			out = append(out, data[i-2:end]...)
+		case marker >= jpegAPP0 && marker <= jpegAPP15, marker == jpegCOM:
+			// dropped
+		default:
+			out = append(out, data[i-2:end]...)
+		}
+
+		i = end
+	}
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNG drops the EXIF, text and modification time chunks.
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	for i := len(pngSignature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+
+		switch string(data[i+4 : i+8]) {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+			// dropped
+		default:
+			out = append(out, data[i:end]...)
+		}
+
+		if string(data[i+4:i+8]) == "IEND" {
+			return out, nil
+		}
+		i = end
+	}
+
+	return nil, ErrMalformed
+}
+
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+// stripWebP drops the EXIF and XMP chunks of the RIFF container and clears
+// their flags in the extended header.
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size&1
+		if size < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+
+		switch fourCC := string(data[i : i+4]); fourCC {
+		case "EXIF", "XMP ":
+			// dropped
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[i:end]...)
+			if size > 0 {
+				out[start+8] &^= webpFlagEXIF | webpFlagXMP
+			}
+		default:
+			out = append(out, data[i:end]...)
+		}
+
+		i = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+
+	return out, nil
+}
+
+// stripGIF drops comments and application extensions other than the looping
+// ones, XMP is stored in an application extension.
+func stripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return nil, ErrMalformed
+	}
+
+	i := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		i += 3 << (flags&0x07 + 1)
+	}
+	if i > len(data) {
+		return nil, ErrMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:i]...)
+
+	// subBlocks returns the end of the data sub-blocks starting at j.
+	subBlocks := func(j int) (int, error) {
+		for {
+			if j >= len(data) {
+				return 0, ErrMalformed
+			}
+			if data[j] == 0 {
+				return j + 1, nil
+			}
+			j += 1 + int(data[j])
+		}
+	}
+
+	for i < len(data) {
+		switch data[i] {
+		case 0x3b:
+			return append(out, data[i]), nil
+		case 0x21:
+			if i+2 > len(data) {
+				return nil, ErrMalformed
+			}
+			end, err := subBlocks(i + 2)
+			if err != nil {
+				return nil, err
+			}
+
+			label := data[i+1]
+			keep := label != 0xfe
+			if label == 0xff {
+				app := data[i+2 : end]
+				keep = len(app) > 11 && (string(app[1:12]) == "NETSCAPE2.0" || string(app[1:12]) == "ANIMEXTS1.0")
+			}
+			if keep {
+				out = append(out, data[i:end]...)
+			}
+			i = end
+		case 0x2c:
+			if i+10 > len(data) {
+				return nil, ErrMalformed
+			}
+			j := i + 10
+			if flags := data[i+9]; flags&0x80 != 0 {
+				j += 3 << (flags&0x07 + 1)
+			}
+			// lzw minimum code size
+			j++
+			end, err := subBlocks(j)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data[i:end]...)
+			i = end
+		default:
+			return nil, ErrMalformed
+		}
+	}
+
+	return nil, ErrMalformed
+}
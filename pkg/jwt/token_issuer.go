@@ -0,0 +1,24 @@
+package jwt
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+	"time"
+)
+
+// NewToken signs an HS256 access token for the user, valid for ttl.
+func NewToken(userID int, roles []string, ttl time.Duration, secret []byte) (string, error) {
+	now := time.Now()
+
+	claims := Claims{
+		UserID: userID,
+		Roles:  roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
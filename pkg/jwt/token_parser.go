@@ -6,7 +6,8 @@ import (
 )
 
 type Claims struct {
-	UserID int `json:"user_id"`
+	UserID int      `json:"user_id"`
+	Roles  []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
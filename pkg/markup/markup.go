@@ -0,0 +1,66 @@
+// Package markup renders user content to HTML that is safe to embed in a page.
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+var ErrUnknownFormat = errors.New("content format should be one of plain, markdown, html")
+
+var (
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy allows the formatting, links and images users usually write and
+	// drops scripts, styles, event handlers and unsafe URLs.
+	policy = bluemonday.UGCPolicy()
+
+	paragraphs = regexp.MustCompile(`\n\s*\n`)
+)
+
+// Render turns the source written in format into sanitized HTML.
+func Render(format, source string) (string, error) {
+	switch format {
+	case FormatPlain:
+		return renderPlain(source), nil
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return "", err
+		}
+		return policy.Sanitize(buf.String()), nil
+	case FormatHTML:
+		return policy.Sanitize(source), nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// renderPlain escapes the text and keeps its paragraphs and line breaks.
+func renderPlain(source string) string {
+	source = strings.ReplaceAll(strings.TrimSpace(source), "\r\n", "\n")
+	if source == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, p := range paragraphs.Split(source, -1) {
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(strings.TrimSpace(p)), "\n", "<br>"))
+		b.WriteString("</p>\n")
+	}
+
+	return b.String()
+}
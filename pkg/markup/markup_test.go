@@ -0,0 +1,115 @@
+package markup
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		source  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "Plain text is escaped",
+			format: FormatPlain,
+			source: "Tom & <b>Jerry</b>\nsecond line\n\nnext paragraph",
+			want:   "<p>Tom &amp; &lt;b&gt;Jerry&lt;/b&gt;<br>second line</p>\n<p>next paragraph</p>\n",
+		},
+		{
+			name:   "Markdown",
+			format: FormatMarkdown,
+			source: "# Title\n\nSome **bold** and ~~struck~~ text",
+			want:   "<h1>Title</h1>\n<p>Some <strong>bold</strong> and <del>struck</del> text</p>\n",
+		},
+		{
+			name:   "Markdown with raw script",
+			format: FormatMarkdown,
+			source: "hello <script>alert(1)</script>",
+			want:   "<p>hello alert(1)</p>\n",
+		},
+		{
+			name:   "Markdown with javascript link",
+			format: FormatMarkdown,
+			source: "[click](javascript:alert(1))",
+			want:   "<p>click</p>\n",
+		},
+		{
+			name:   "HTML is sanitized",
+			format: FormatHTML,
+			source: `<p onclick="steal()">hi <a href="https://example.com">there</a></p><iframe src="https://evil"></iframe>`,
+			want:   `<p>hi <a href="https://example.com" rel="nofollow">there</a></p>`,
+		},
+		{
+			name:    "Unknown format",
+			format:  "rst",
+			source:  "text",
+			wantErr: ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, tt.source)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	rendered := "<h1>Title</h1>\n<p>Tom &amp; <b>Jer</b>ry<br>next</p><script>alert(1)</script><ul><li>one</li><li>two</li></ul>"
+
+	require.Equal(t, "Title Tom & Jerry next one two", Text(rendered))
+	require.Equal(t, 6, Words(Text(rendered)))
+	require.Equal(t, 0, Words(Text("")))
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		maxLen int
+		want   string
+	}{
+		{
+			name:   "Short text is kept",
+			text:   "One sentence.  Two\nsentences.",
+			maxLen: 100,
+			want:   "One sentence. Two sentences.",
+		},
+		{
+			name:   "Cut after the last sentence that fits",
+			text:   "First one. Second one! Third one is too long to fit.",
+			maxLen: 30,
+			want:   "First one. Second one!",
+		},
+		{
+			name:   "Abbreviations do not end sentences",
+			text:   "Use tools, e.g. hammers. Then rest for a long while.",
+			maxLen: 30,
+			want:   "Use tools, e.g. hammers.",
+		},
+		{
+			name:   "Closing quotes belong to the sentence",
+			text:   `He said "stop." Then he left the building quietly.`,
+			maxLen: 20,
+			want:   `He said "stop."`,
+		},
+		{
+			name:   "Long first sentence is cut at a word",
+			text:   "Привіт світе, this sentence is far too long to fit",
+			maxLen: 20,
+			want:   "Привіт світе, this…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Excerpt(tt.text, tt.maxLen))
+		})
+	}
+}
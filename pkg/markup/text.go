@@ -0,0 +1,103 @@
+package markup
+
+import (
+	"golang.org/x/net/html"
+	"strings"
+	"unicode"
+)
+
+// blocks are the elements that separate words, text in inline elements is
+// joined as it is.
+var blocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "figcaption": true, "figure": true, "footer": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "img": true, "li": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "td": true, "th": true, "tr": true,
+	"ul": true,
+}
+
+// Text extracts the readable text of rendered HTML with whitespace collapsed.
+func Text(rendered string) string {
+	var b strings.Builder
+
+	z := html.NewTokenizer(strings.NewReader(rendered))
+	skip := 0
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style":
+				if tt == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			case blocks[tag]:
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// Words is the number of words of the text, stray punctuation is not a word.
+func Words(text string) int {
+	words := 0
+	for _, field := range strings.Fields(text) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			words++
+		}
+	}
+	return words
+}
+
+// Excerpt shortens the text to at most maxLen characters, cut after the last
+// sentence that fits. When even the first sentence is longer, it is cut after
+// the last word that fits and marked with an ellipsis.
+func Excerpt(text string, maxLen int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= maxLen {
+		return string(runes)
+	}
+
+	end := 0
+	for i := 0; i < maxLen; i++ {
+		if !strings.ContainsRune(".!?…", runes[i]) {
+			continue
+		}
+
+		// closing quotes and brackets belong to the sentence
+		j := i + 1
+		for j < len(runes) && strings.ContainsRune(`"')]”’»`, runes[j]) {
+			j++
+		}
+
+		if j <= maxLen && j+1 < len(runes) && runes[j] == ' ' && startsSentence(runes[j+1]) {
+			end = j
+		}
+	}
+
+	if end > 0 {
+		return string(runes[:end])
+	}
+
+	cut := string(runes[:maxLen])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+
+	return strings.TrimRight(cut, " ,;:-–—") + "…"
+}
+
+// startsSentence tells a sentence apart from an abbreviation like "e.g. this".
+func startsSentence(r rune) bool {
+	return unicode.IsUpper(r) || unicode.IsDigit(r) || strings.ContainsRune(`"'(“‘«`, r)
+}
@@ -0,0 +1,468 @@
+// Package migrate applies versioned SQL migrations to a Postgres database.
+// A migration is a pair of files, <version>_<name>.up.sql and
+// <version>_<name>.down.sql, the applied versions are recorded in the
+// schema_versions table with a checksum of their up file. Each migration
+// runs in its own transaction, and the migrator holds an advisory lock
+// meanwhile so replicas starting together apply every migration once.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidName      = errors.New("invalid migration file name")
+	ErrDuplicate        = errors.New("duplicate migration version")
+	ErrNoUp             = errors.New("migration has no up file")
+	ErrIrreversible     = errors.New("migration has no down file")
+	ErrUnknownVersion   = errors.New("database is at a version this build does not know")
+	ErrDirty            = errors.New("database was left dirty by golang-migrate")
+	ErrChecksumMismatch = errors.New("applied migration was changed")
+)
+
+// lockKey identifies the advisory lock held while migrating, it only has to
+// be the same for every replica.
+const lockKey int64 = 0x6e6577732d637275 // "news-cru"
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the hex SHA-256 of Up.
+	Checksum string
+
+	hasDown bool
+}
+
+// Status tells whether a migration is applied, and when. Modified is set
+// when the up file changed since it was applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New reads the migrations from the root of fsys, files other than .sql
+// files are ignored.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	const op = "news-crud.pkg.migrate.New"
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the migrations from the root of fsys, sorted by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d is both %s and %s", ErrDuplicate, version, m.Name, match[2])
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down, m.hasDown = string(data), true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrNoUp, m.Version, m.Name)
+		}
+		m.Checksum = checksum(m.Up)
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func checksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
+
+// Migrations returns the known migrations sorted by version.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies the migrations that are not applied yet, in order, and returns
+// them. It applies nothing when an applied migration was changed, and stops
+// at the first one that fails.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	const op = "news-crud.pkg.migrate.Up"
+
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err = verify(m.migrations, applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err = apply(ctx, conn, migration); err != nil {
+				return fmt.Errorf("%d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, latest first, and returns
+// them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	const op = "news-crud.pkg.migrate.Down"
+
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err = verify(m.migrations, applied); err != nil {
+			return err
+		}
+
+		versions := make([]int, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, version := range versions {
+			if len(done) == steps {
+				break
+			}
+
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+			}
+			if !migration.hasDown {
+				return fmt.Errorf("%w: %d_%s", ErrIrreversible, migration.Version, migration.Name)
+			}
+
+			if err = revert(ctx, conn, migration); err != nil {
+				return fmt.Errorf("%d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return done, nil
+}
+
+// Status lists every known migration and the applied ones this build does
+// not know, sorted by version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	const op = "news-crud.pkg.migrate.Status"
+
+	var applied map[int]appliedRow
+	err := m.locked(ctx, func(conn *sql.Conn) (err error) {
+		applied, err = m.applied(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			s.Applied, s.AppliedAt = true, row.appliedAt
+			s.Modified = row.checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, s)
+	}
+
+	for version, row := range applied {
+		statuses = append(statuses, Status{
+			Migration: Migration{Version: version, Name: row.name, Checksum: row.checksum},
+			Applied:   true,
+			AppliedAt: row.appliedAt,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+func (m *Migrator) find(version int) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+// locked runs fn on a connection holding the migration lock, waiting for
+// the lock as long as ctx allows. Session locks belong to a connection, so
+// everything fn does has to go through conn.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, `select pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("cant take migration lock: %w", err)
+	}
+	// unlock even when ctx is done, the connection goes back to the pool
+	defer conn.ExecContext(context.Background(), `select pg_advisory_unlock($1)`, lockKey)
+
+	return fn(conn)
+}
+
+// verify checks that the applied migrations are the ones of this build.
+func verify(migrations []Migration, applied map[int]appliedRow) error {
+	var changed []string
+	for _, migration := range migrations {
+		row, ok := applied[migration.Version]
+		if ok && row.checksum != migration.Checksum {
+			changed = append(changed, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+	}
+
+	if len(changed) > 0 {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+type appliedRow struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// applied creates the schema_versions table when missing and returns its
+// rows keyed by version.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]appliedRow, error) {
+	if err := m.init(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `select version, name, checksum, applied_at from schema_versions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedRow)
+	for rows.Next() {
+		var (
+			version int
+			row     appliedRow
+		)
+		if err = rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+
+	return applied, rows.Err()
+}
+
+// init creates the schema_versions table. A database migrated by
+// golang-migrate so far has the versions up to the one in its
+// schema_migrations table recorded as applied, and versions recorded
+// without a checksum get the one of this build.
+func (m *Migrator) init(ctx context.Context, conn *sql.Conn) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `
+		create table if not exists schema_versions (
+		  version integer primary key,
+		  name text not null,
+		  checksum text not null default '',
+		  applied_at timestamp not null default now()
+		);
+
+		alter table schema_versions add column if not exists checksum text not null default '';
+	`); err != nil {
+		return err
+	}
+
+	var empty, legacy bool
+	if err = tx.QueryRowContext(ctx, `
+		select not exists (select 1 from schema_versions),
+		       to_regclass('schema_migrations') is not null
+	`).Scan(&empty, &legacy); err != nil {
+		return err
+	}
+
+	if empty && legacy {
+		var (
+			version int
+			dirty   bool
+		)
+		err = tx.QueryRowContext(ctx, `select version, dirty from schema_migrations limit 1`).Scan(&version, &dirty)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return err
+		case dirty:
+			return fmt.Errorf("%w at version %d", ErrDirty, version)
+		default:
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+				if err = record(ctx, tx, migration); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	versions := make([]int64, len(m.migrations))
+	checksums := make([]string, len(m.migrations))
+	for i, migration := range m.migrations {
+		versions[i], checksums[i] = int64(migration.Version), migration.Checksum
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		update schema_versions s
+		set checksum = c.checksum
+		from unnest($1::integer[], $2::text[]) as c (version, checksum)
+		where s.version = c.version and s.checksum = ''
+	`, pq.Array(versions), pq.Array(checksums)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, migration.Up); err != nil {
+		return err
+	}
+	if err = record(ctx, tx, migration); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(migration.Down) != "" {
+		if _, err = tx.ExecContext(ctx, migration.Down); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `delete from schema_versions where version = $1`, migration.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func record(ctx context.Context, tx *sql.Tx, migration Migration) error {
+	_, err := tx.ExecContext(ctx, `
+		insert into
+		    schema_versions (version, name, checksum)
+		values ($1, $2, $3)
+	`, migration.Version, migration.Name, migration.Checksum)
+	return err
+}
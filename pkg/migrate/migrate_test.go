@@ -0,0 +1,137 @@
+package migrate
+
+import (
+	"github.com/ananaslegend/news-crud/migrations"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []Migration
+		wantErr error
+	}{
+		{
+			name: "Sorted by version",
+			files: fstest.MapFS{
+				"010_tags.up.sql":   {Data: []byte("create table tags ();")},
+				"010_tags.down.sql": {Data: []byte("drop table tags;")},
+				"002_posts.up.sql":  {Data: []byte("create table posts ();")},
+				"README.md":         {Data: []byte("not a migration")},
+			},
+			want: []Migration{
+				{Version: 2, Name: "posts", Up: "create table posts ();", Checksum: checksum("create table posts ();")},
+				{
+					Version:  10,
+					Name:     "tags",
+					Up:       "create table tags ();",
+					Down:     "drop table tags;",
+					Checksum: checksum("create table tags ();"),
+					hasDown:  true,
+				},
+			},
+		},
+		{
+			name: "Empty down file is reversible",
+			files: fstest.MapFS{
+				"001_init.up.sql":   {Data: []byte("select 1;")},
+				"001_init.down.sql": {Data: []byte("")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "init", Up: "select 1;", Checksum: checksum("select 1;"), hasDown: true},
+			},
+		},
+		{
+			name: "Invalid name",
+			files: fstest.MapFS{
+				"init.up.sql": {Data: []byte("select 1;")},
+			},
+			wantErr: ErrInvalidName,
+		},
+		{
+			name: "Version zero",
+			files: fstest.MapFS{
+				"000_init.up.sql": {Data: []byte("select 1;")},
+			},
+			wantErr: ErrInvalidName,
+		},
+		{
+			name: "Same version twice",
+			files: fstest.MapFS{
+				"001_init.up.sql":  {Data: []byte("select 1;")},
+				"001_posts.up.sql": {Data: []byte("select 2;")},
+			},
+			wantErr: ErrDuplicate,
+		},
+		{
+			name: "Down without up",
+			files: fstest.MapFS{
+				"001_init.down.sql": {Data: []byte("select 1;")},
+			},
+			wantErr: ErrNoUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.files)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	got, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+
+	for i, m := range got {
+		require.Equal(t, i+1, m.Version, "versions should have no gaps")
+		require.NotEmpty(t, strings.TrimSpace(m.Down), "%d_%s should have a down migration", m.Version, m.Name)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	require.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", checksum(""))
+	require.NotEqual(t, checksum("select 1;"), checksum("select 1; "))
+}
+
+func TestVerify(t *testing.T) {
+	known := []Migration{
+		{Version: 1, Name: "init", Checksum: checksum("a")},
+		{Version: 2, Name: "tags", Checksum: checksum("b")},
+		{Version: 3, Name: "media", Checksum: checksum("c")},
+	}
+
+	t.Run("Applied ones unchanged", func(t *testing.T) {
+		err := verify(known, map[int]appliedRow{
+			1: {name: "init", checksum: checksum("a")},
+			2: {name: "tags", checksum: checksum("b")},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Pending ones are not checked", func(t *testing.T) {
+		require.NoError(t, verify(known, map[int]appliedRow{}))
+	})
+
+	t.Run("Applied one changed", func(t *testing.T) {
+		err := verify(known, map[int]appliedRow{
+			1: {name: "init", checksum: checksum("a")},
+			2: {name: "tags", checksum: checksum("b2")},
+			3: {name: "media", checksum: checksum("c2")},
+		})
+		require.ErrorIs(t, err, ErrChecksumMismatch)
+		require.ErrorContains(t, err, "2_tags, 3_media")
+	})
+}
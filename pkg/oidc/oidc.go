@@ -0,0 +1,325 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	leeway        = time.Minute
+)
+
+var (
+	ErrIssuerMismatch     = errors.New("issuer mismatch")
+	ErrInvalidIDToken     = errors.New("invalid id token")
+	ErrUnknownSigningKey  = errors.New("unknown signing key")
+	ErrNoIDToken          = errors.New("token response has no id_token")
+	ErrTokenExchange      = errors.New("token exchange failed")
+	ErrUnexpectedResponse = errors.New("unexpected response from provider")
+)
+
+// Metadata is the subset of the provider discovery document the client relies on.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims are the ID token claims. Groups holds the claim configured as the
+// group claim, it is filled by Verify.
+type Claims struct {
+	Nonce  string   `json:"nonce"`
+	Email  string   `json:"email"`
+	Name   string   `json:"name"`
+	Groups []string `json:"-"`
+	jwt.RegisteredClaims
+}
+
+type Provider struct {
+	metadata Metadata
+	config   Config
+	client   *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// Discover fetches the provider metadata published under the issuer.
+func Discover(ctx context.Context, client *http.Client, issuer string, config Config) (*Provider, error) {
+	const op = "news-crud.pkg.oidc.Discover"
+
+	var metadata Metadata
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+discoveryPath, &metadata); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if metadata.Issuer != issuer {
+		return nil, fmt.Errorf("%s: %w: got %q, want %q", op, ErrIssuerMismatch, metadata.Issuer, issuer)
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+
+	return &Provider{
+		metadata: metadata,
+		config:   config,
+		client:   client,
+		keys:     make(map[string]*rsa.PublicKey),
+	}, nil
+}
+
+func (p *Provider) Issuer() string {
+	return p.metadata.Issuer
+}
+
+// AuthCodeURL builds the authorization endpoint URL using PKCE with the S256 method.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", strings.Join(p.config.Scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", Challenge(verifier))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return p.metadata.AuthorizationEndpoint + sep + v.Encode()
+}
+
+// Exchange trades an authorization code for the raw ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	const op = "news-crud.pkg.oidc.Exchange"
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("%s: %w: %s: %s", op, ErrTokenExchange, resp.Status, body)
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if token.IDToken == "" {
+		return "", ErrNoIDToken
+	}
+
+	return token.IDToken, nil
+}
+
+// Verify checks the ID token signature against the provider keys along with
+// issuer, audience, expiry and nonce. groupsClaim names the claim holding groups.
+func (p *Provider) Verify(ctx context.Context, raw, nonce, groupsClaim string) (Claims, error) {
+	const op = "news-crud.pkg.oidc.Verify"
+
+	var claims Claims
+	token, err := jwt.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(p.metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(leeway),
+	)
+	if err != nil || !token.Valid {
+		return Claims{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" || claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("%s: %w: subject or nonce mismatch", op, ErrInvalidIDToken)
+	}
+
+	if groupsClaim != "" {
+		var all jwt.MapClaims
+		if _, _, err = jwt.NewParser().ParseUnverified(raw, &all); err != nil {
+			return Claims{}, fmt.Errorf("%s: %w", op, err)
+		}
+		claims.Groups = stringList(all[groupsClaim])
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.RLock()
+	key, ok := p.keys[kid]
+	p.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	// the provider may have rotated its keys, so refetch them once
+	if err := p.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if key, ok = p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// tokens without kid are accepted when the provider publishes a single key
+	if kid == "" && len(p.keys) == 1 {
+		for _, key = range p.keys {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownSigningKey
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (p *Provider) refreshKeys(ctx context.Context) error {
+	const op = "news-crud.pkg.oidc.refreshKeys"
+
+	var set jwks
+	if err := getJSON(ctx, p.client, p.metadata.JWKSURI, &set); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return nil
+}
+
+// NewVerifier returns a random PKCE code verifier, it is also suitable as state or nonce.
+func NewVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge derives the S256 PKCE code challenge out of the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s: %s", ErrUnexpectedResponse, url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func stringList(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && !slices.Contains(list, s) {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
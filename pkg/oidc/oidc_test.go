@@ -0,0 +1,71 @@
+package oidc_test
+
+import (
+	"context"
+	"github.com/ananaslegend/news-crud/pkg/oidc"
+	"github.com/ananaslegend/news-crud/pkg/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestProvider_Verify(t *testing.T) {
+	idp := oidctest.NewProvider("news", "news-secret")
+	defer idp.Close()
+
+	provider, err := oidc.Discover(context.Background(), idp.Server.Client(), idp.Issuer(), oidc.Config{ClientID: "news"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":    idp.Issuer(),
+			"sub":    "staff-1",
+			"aud":    "news",
+			"iat":    now.Unix(),
+			"exp":    now.Add(time.Hour).Unix(),
+			"nonce":  "nonce",
+			"groups": []string{"a", "b"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c jwt.MapClaims)
+		nonce   string
+		wantErr bool
+	}{
+		{name: "Valid token", mutate: func(jwt.MapClaims) {}, nonce: "nonce"},
+		{name: "Wrong nonce", mutate: func(jwt.MapClaims) {}, nonce: "other", wantErr: true},
+		{name: "Wrong audience", mutate: func(c jwt.MapClaims) { c["aud"] = "other" }, nonce: "nonce", wantErr: true},
+		{name: "Wrong issuer", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil" }, nonce: "nonce", wantErr: true},
+		{name: "Expired", mutate: func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() }, nonce: "nonce", wantErr: true},
+		{name: "No expiry", mutate: func(c jwt.MapClaims) { delete(c, "exp") }, nonce: "nonce", wantErr: true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			claims := valid()
+			testCase.mutate(claims)
+
+			got, err := provider.Verify(context.Background(), idp.SignIDToken(claims), testCase.nonce, "groups")
+			if testCase.wantErr {
+				require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "staff-1", got.Subject)
+			require.Equal(t, []string{"a", "b"}, got.Groups)
+		})
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	// base64url(sha256(verifier)) without padding
+	require.Equal(t,
+		"XkZj-ZVtvcxKeXrM5axWbgZoflWo7nPvHfVSo_oTxn0",
+		oidc.Challenge("dBjftJeZ4CZ-TexGYr3EUs6Fn0i8UfFs5YFXQmCQK1k"),
+	)
+}
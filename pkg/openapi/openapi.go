@@ -0,0 +1,227 @@
+// Package openapi validates HTTP requests and responses against an OpenAPI
+// 3.1 document. It understands the subset of the specification and of JSON
+// Schema the documents of this service are written with.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	routes []route
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters"`
+	Responses  map[string]*Response  `json:"responses"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Put        *Operation   `json:"put"`
+	Post       *Operation   `json:"post"`
+	Delete     *Operation   `json:"delete"`
+	Head       *Operation   `json:"head"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter. A path parameter with
+// AllowReserved matches the rest of the path, slashes included.
+type Parameter struct {
+	Ref           string  `json:"$ref"`
+	Name          string  `json:"name"`
+	In            string  `json:"in"`
+	Required      bool    `json:"required"`
+	AllowReserved bool    `json:"allowReserved"`
+	Schema        *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref     string                `json:"$ref"`
+	Content map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Load parses the document and resolves its references.
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("cant parse openapi document: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported openapi version %q", doc.OpenAPI)
+	}
+
+	r := resolver{doc: &doc, seen: make(map[*Schema]bool)}
+	for _, s := range doc.Components.Schemas {
+		r.schema(&s)
+	}
+
+	for template, item := range doc.Paths {
+		if err := r.parameters(item.Parameters); err != nil {
+			return nil, fmt.Errorf("%s: %w", template, err)
+		}
+
+		for method, op := range item.operations() {
+			if err := r.operation(op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, template, err)
+			}
+		}
+
+		doc.routes = append(doc.routes, newRoute(template, item))
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// templates with more literal segments are tried first, /posts/trending
+	// wins over /posts/{id}
+	sort.Slice(doc.routes, func(i, j int) bool {
+		if doc.routes[i].literals != doc.routes[j].literals {
+			return doc.routes[i].literals > doc.routes[j].literals
+		}
+		return doc.routes[i].template < doc.routes[j].template
+	})
+
+	return &doc, nil
+}
+
+func (p *PathItem) operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		http.MethodGet:    p.Get,
+		http.MethodPut:    p.Put,
+		http.MethodPost:   p.Post,
+		http.MethodDelete: p.Delete,
+		http.MethodHead:   p.Head,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// operation returns the operation of the method, HEAD falls back to GET the
+// way net/http routes it.
+func (p *PathItem) operation(method string) *Operation {
+	op := p.operations()[method]
+	if op == nil && method == http.MethodHead {
+		op = p.Get
+	}
+	return op
+}
+
+// resolver replaces local references with what they point at.
+type resolver struct {
+	doc  *Document
+	seen map[*Schema]bool
+	err  error
+}
+
+func (r *resolver) operation(op *Operation) error {
+	if err := r.parameters(op.Parameters); err != nil {
+		return err
+	}
+
+	if op.RequestBody != nil {
+		for _, mt := range op.RequestBody.Content {
+			r.schema(&mt.Schema)
+		}
+	}
+
+	for status, res := range op.Responses {
+		if res.Ref != "" {
+			target, ok := r.doc.Components.Responses[strings.TrimPrefix(res.Ref, "#/components/responses/")]
+			if !ok {
+				return fmt.Errorf("unknown response %q", res.Ref)
+			}
+			op.Responses[status] = target
+			res = target
+		}
+
+		for _, mt := range res.Content {
+			r.schema(&mt.Schema)
+		}
+	}
+
+	return r.err
+}
+
+func (r *resolver) parameters(params []*Parameter) error {
+	for i, p := range params {
+		if p.Ref != "" {
+			target, ok := r.doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			if !ok {
+				return fmt.Errorf("unknown parameter %q", p.Ref)
+			}
+			params[i] = target
+			p = target
+		}
+
+		r.schema(&p.Schema)
+	}
+
+	return r.err
+}
+
+// schema resolves the schema in place and the schemas it is made of.
+// Recursive schemas end up pointing at themselves.
+func (r *resolver) schema(s **Schema) {
+	if *s == nil {
+		return
+	}
+
+	if ref := (*s).Ref; ref != "" {
+		target, ok := r.doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+		if !ok {
+			r.err = fmt.Errorf("unknown schema %q", ref)
+			return
+		}
+		*s = target
+	}
+
+	if r.seen[*s] {
+		return
+	}
+	r.seen[*s] = true
+
+	for name := range (*s).Properties {
+		p := (*s).Properties[name]
+		r.schema(&p)
+		(*s).Properties[name] = p
+	}
+
+	r.schema(&(*s).Items)
+	r.schema(&(*s).AdditionalProperties.Schema)
+
+	for i := range (*s).OneOf {
+		r.schema(&(*s).OneOf[i])
+	}
+}
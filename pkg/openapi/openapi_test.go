@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testDocument = `{
+	"openapi": "3.1.0",
+	"paths": {
+		"/posts": {
+			"post": {
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/NewPost"}}}
+				},
+				"responses": {
+					"201": {"content": {"application/json": {"schema": {
+						"type": "object",
+						"required": ["post_id"],
+						"properties": {"post_id": {"type": "integer"}}
+					}}}},
+					"400": {"$ref": "#/components/responses/BadRequest"}
+				}
+			},
+			"get": {
+				"parameters": [
+					{"name": "dateFrom", "in": "query", "required": true, "schema": {"type": "string", "format": "date-time"}},
+					{"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 100}},
+					{"name": "tag", "in": "query", "schema": {"type": "array", "items": {"type": "string"}, "maxItems": 2}}
+				],
+				"responses": {
+					"200": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Comment"}}}}}
+				}
+			}
+		},
+		"/posts/{id}": {
+			"parameters": [{"$ref": "#/components/parameters/ID"}],
+			"delete": {"responses": {"204": {}}}
+		},
+		"/posts/trending": {
+			"get": {"responses": {"200": {}}}
+		},
+		"/media/{key}": {
+			"get": {
+				"parameters": [{"name": "key", "in": "path", "required": true, "allowReserved": true, "schema": {"type": "string"}}],
+				"responses": {"200": {"content": {"image/png": {}}}}
+			}
+		}
+	},
+	"components": {
+		"parameters": {
+			"ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}
+		},
+		"responses": {
+			"BadRequest": {"content": {"text/plain": {}}}
+		},
+		"schemas": {
+			"NewPost": {
+				"type": "object",
+				"required": ["title"],
+				"additionalProperties": false,
+				"properties": {
+					"title": {"type": "string", "minLength": 1, "maxLength": 5},
+					"format": {"type": "string", "enum": ["plain", "markdown"]},
+					"tags": {"type": ["array", "null"], "items": {"type": "string"}}
+				}
+			},
+			"Comment": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"replies": {"type": "array", "items": {"$ref": "#/components/schemas/Comment"}}
+				}
+			}
+		}
+	}
+}`
+
+func loadTestDocument(t *testing.T) *Document {
+	doc, err := Load([]byte(testDocument))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestLoad(t *testing.T) {
+	_, err := Load([]byte(`{"openapi": "2.0"}`))
+	require.Error(t, err)
+
+	_, err = Load([]byte(`{"openapi": "3.1.0", "paths": {"/": {"get": {"responses": {
+		"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Missing"}}}}
+	}}}}}`))
+	require.ErrorContains(t, err, "Missing")
+}
+
+func TestDocument_Find(t *testing.T) {
+	doc := loadTestDocument(t)
+
+	tests := []struct {
+		method, path string
+		template     string
+		params       map[string]string
+	}{
+		{http.MethodGet, "/posts", "/posts", map[string]string{}},
+		{http.MethodGet, "/posts/trending", "/posts/trending", map[string]string{}},
+		{http.MethodDelete, "/posts/7", "/posts/{id}", map[string]string{"id": "7"}},
+		{http.MethodHead, "/media/a/b%20c.png", "/media/{key}", map[string]string{"key": "a/b c.png"}},
+	}
+
+	for _, tt := range tests {
+		m, ok := doc.Find(tt.method, tt.path)
+		require.True(t, ok, tt.path)
+		require.Equal(t, tt.template, m.Template)
+		require.Equal(t, tt.params, m.PathParams)
+	}
+
+	for _, path := range []string{"/posts/7/comments", "/media", "/media/", "/unknown"} {
+		_, ok := doc.Find(http.MethodGet, path)
+		require.False(t, ok, path)
+	}
+
+	_, ok := doc.Find(http.MethodPut, "/posts/7")
+	require.False(t, ok)
+}
+
+func TestMatch_ValidateRequest(t *testing.T) {
+	doc := loadTestDocument(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		wantErr string
+	}{
+		{name: "valid query", method: http.MethodGet, target: "/posts?dateFrom=2024-01-01T00:00:00Z&limit=10&tag=a&tag=b"},
+		{name: "missing required query", method: http.MethodGet, target: "/posts", wantErr: `query parameter "dateFrom": is required`},
+		{name: "bad date", method: http.MethodGet, target: "/posts?dateFrom=yesterday", wantErr: "RFC 3339"},
+		{name: "not an integer", method: http.MethodGet, target: "/posts?dateFrom=2024-01-01T00:00:00Z&limit=1.5", wantErr: `"limit": should be integer`},
+		{name: "out of range", method: http.MethodGet, target: "/posts?dateFrom=2024-01-01T00:00:00Z&limit=0", wantErr: "at least 1"},
+		{name: "too many values", method: http.MethodGet, target: "/posts?dateFrom=2024-01-01T00:00:00Z&tag=a&tag=b&tag=c", wantErr: "at most 2 items"},
+		{name: "bad path parameter", method: http.MethodDelete, target: "/posts/abc", wantErr: `path parameter "id"`},
+		{name: "valid body", method: http.MethodPost, target: "/posts", body: `{"title": "hi", "format": "plain", "tags": null}`},
+		{name: "missing body", method: http.MethodPost, target: "/posts", wantErr: ErrMissingBody.Error()},
+		{name: "invalid json", method: http.MethodPost, target: "/posts", body: `{`, wantErr: ErrInvalidJSON.Error()},
+		{name: "missing property", method: http.MethodPost, target: "/posts", body: `{}`, wantErr: "title is required"},
+		{name: "too long", method: http.MethodPost, target: "/posts", body: `{"title": "héllo!"}`, wantErr: "title should be at most 5 characters"},
+		{name: "not in enum", method: http.MethodPost, target: "/posts", body: `{"title": "a", "format": "html"}`, wantErr: `format should be one of "plain", "markdown"`},
+		{name: "wrong item type", method: http.MethodPost, target: "/posts", body: `{"title": "a", "tags": [1]}`, wantErr: "tags[0] should be string"},
+		{name: "unknown property", method: http.MethodPost, target: "/posts", body: `{"title": "a", "x": 1}`, wantErr: "x is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = http.NoBody
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+
+			r := httptest.NewRequest(tt.method, tt.target, body)
+			r.Header.Set("Content-Type", "application/json")
+
+			m, ok := doc.Find(r.Method, r.URL.Path)
+			require.True(t, ok)
+
+			err := m.ValidateRequest(r)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("body is kept for the handler", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(`{"title": "a"}`))
+		r.Header.Set("Content-Type", "application/json")
+
+		m, _ := doc.Find(r.Method, r.URL.Path)
+		require.NoError(t, m.ValidateRequest(r))
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"title": "a"}`, string(b))
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(`title=a`))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		m, _ := doc.Find(r.Method, r.URL.Path)
+		require.True(t, errors.Is(m.ValidateRequest(r), ErrUnknownContentType))
+	})
+}
+
+func TestMatch_ValidateResponse(t *testing.T) {
+	doc := loadTestDocument(t)
+
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	m, _ := doc.Find(http.MethodPost, "/posts")
+	require.NoError(t, m.ValidateResponse(http.StatusCreated, jsonHeader, []byte(`{"post_id": 1}`)))
+	require.NoError(t, m.ValidateResponse(http.StatusBadRequest, http.Header{"Content-Type": {"text/plain; charset=utf-8"}}, []byte("bad")))
+	require.NoError(t, m.ValidateResponse(http.StatusBadRequest, http.Header{}, nil))
+	require.ErrorContains(t, m.ValidateResponse(http.StatusCreated, jsonHeader, []byte(`{"post_id": "1"}`)), "post_id should be integer")
+	require.ErrorIs(t, m.ValidateResponse(http.StatusCreated, http.Header{"Content-Type": {"text/plain"}}, []byte(`{}`)), ErrUnknownContentType)
+	require.ErrorIs(t, m.ValidateResponse(http.StatusInternalServerError, jsonHeader, nil), ErrUnexpectedStatus)
+
+	m, _ = doc.Find(http.MethodGet, "/posts")
+	require.NoError(t, m.ValidateResponse(http.StatusOK, jsonHeader, []byte(`[{"id": 1, "replies": [{"id": 2, "replies": []}]}]`)))
+	require.ErrorContains(t, m.ValidateResponse(http.StatusOK, jsonHeader, []byte(`[{"id": 1, "replies": [{"id": "2"}]}]`)), "[0].replies[0].id should be integer")
+}
@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"net/url"
+	"strings"
+)
+
+type route struct {
+	template string
+	item     *PathItem
+	segments []segment
+	literals int
+}
+
+type segment struct {
+	literal string
+	param   string
+	rest    bool
+}
+
+func newRoute(template string, item *PathItem) route {
+	rt := route{template: template, item: item}
+
+	reserved := make(map[string]bool)
+	for _, p := range item.Parameters {
+		if p.In == "path" && p.AllowReserved {
+			reserved[p.Name] = true
+		}
+	}
+	for _, op := range item.operations() {
+		for _, p := range op.Parameters {
+			if p.In == "path" && p.AllowReserved {
+				reserved[p.Name] = true
+			}
+		}
+	}
+
+	for _, s := range strings.Split(strings.Trim(template, "/"), "/") {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			name := s[1 : len(s)-1]
+			rt.segments = append(rt.segments, segment{param: name, rest: reserved[name]})
+			continue
+		}
+
+		rt.segments = append(rt.segments, segment{literal: s})
+		rt.literals++
+	}
+
+	return rt
+}
+
+// match returns the path parameters when the path fits the template.
+func (rt route) match(path string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	params := make(map[string]string)
+
+	for i, s := range rt.segments {
+		if i >= len(parts) {
+			return nil, false
+		}
+
+		switch {
+		case s.rest:
+			params[s.param] = unescape(strings.Join(parts[i:], "/"))
+			return params, parts[i] != ""
+		case s.param != "":
+			if parts[i] == "" {
+				return nil, false
+			}
+			params[s.param] = unescape(parts[i])
+		case s.literal != parts[i]:
+			return nil, false
+		}
+	}
+
+	return params, len(parts) == len(rt.segments)
+}
+
+func unescape(s string) string {
+	if v, err := url.PathUnescape(s); err == nil {
+		return v
+	}
+	return s
+}
+
+// Match is the operation a request is routed to.
+type Match struct {
+	Template   string
+	Method     string
+	Operation  *Operation
+	PathParams map[string]string
+
+	parameters []*Parameter
+}
+
+// Find routes a request to an operation of the document. It reports false
+// for paths or methods the document does not describe.
+func (d *Document) Find(method, path string) (*Match, bool) {
+	for _, rt := range d.routes {
+		params, ok := rt.match(path)
+		if !ok {
+			continue
+		}
+
+		op := rt.item.operation(method)
+		if op == nil {
+			return nil, false
+		}
+
+		return &Match{
+			Template:   rt.template,
+			Method:     method,
+			Operation:  op,
+			PathParams: params,
+			parameters: mergeParameters(rt.item.Parameters, op.Parameters),
+		}, true
+	}
+
+	return nil, false
+}
+
+// mergeParameters lets operation parameters override the ones of the path.
+func mergeParameters(path, op []*Parameter) []*Parameter {
+	params := make([]*Parameter, 0, len(path)+len(op))
+	for _, p := range path {
+		overridden := false
+		for _, o := range op {
+			if o.Name == p.Name && o.In == p.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			params = append(params, p)
+		}
+	}
+	return append(params, op...)
+}
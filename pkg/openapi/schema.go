@@ -0,0 +1,254 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is the JSON Schema subset the documents use.
+type Schema struct {
+	Ref                  string               `json:"$ref"`
+	Type                 Types                `json:"type"`
+	Format               string               `json:"format"`
+	Enum                 []any                `json:"enum"`
+	Properties           map[string]*Schema   `json:"properties"`
+	Required             []string             `json:"required"`
+	AdditionalProperties AdditionalProperties `json:"additionalProperties"`
+	Items                *Schema              `json:"items"`
+	OneOf                []*Schema            `json:"oneOf"`
+	Minimum              *float64             `json:"minimum"`
+	Maximum              *float64             `json:"maximum"`
+	MinLength            *int                 `json:"minLength"`
+	MaxLength            *int                 `json:"maxLength"`
+	MinItems             *int                 `json:"minItems"`
+	MaxItems             *int                 `json:"maxItems"`
+}
+
+// Types is the type keyword, a single type or a list of them.
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type should be a string or an array of strings: %w", err)
+	}
+	*t = many
+	return nil
+}
+
+func (t Types) has(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AdditionalProperties is either false or a schema the extra properties
+// should match. Absent means anything goes.
+type AdditionalProperties struct {
+	Forbidden bool
+	Schema    *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		a.Forbidden = !allowed
+		return nil
+	}
+
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// Validate checks a value decoded by encoding/json, numbers as float64,
+// against the schema. The error names the path of the offending value.
+func (s *Schema) Validate(v any) error {
+	return s.validate("", v)
+}
+
+func (s *Schema) validate(path string, v any) error {
+	if s == nil {
+		return nil
+	}
+
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, option := range s.OneOf {
+			if option.validate(path, v) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fieldError(path, "should match exactly one schema, matched %d", matched)
+		}
+	}
+
+	if len(s.Type) > 0 && !s.matchesType(v) {
+		return fieldError(path, "should be %s", strings.Join(s.Type, " or "))
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(v) {
+		return fieldError(path, "should be one of %s", enumString(s.Enum))
+	}
+
+	switch v := v.(type) {
+	case string:
+		return s.validateString(path, v)
+	case float64:
+		return s.validateNumber(path, v)
+	case []any:
+		return s.validateArray(path, v)
+	case map[string]any:
+		return s.validateObject(path, v)
+	}
+
+	return nil
+}
+
+func (s *Schema) matchesType(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return s.Type.has("null")
+	case bool:
+		return s.Type.has("boolean")
+	case string:
+		return s.Type.has("string")
+	case float64:
+		return s.Type.has("number") || s.Type.has("integer") && v == math.Trunc(v)
+	case []any:
+		return s.Type.has("array")
+	case map[string]any:
+		return s.Type.has("object")
+	}
+	return false
+}
+
+func (s *Schema) inEnum(v any) bool {
+	for _, e := range s.Enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateString(path, v string) error {
+	n := utf8.RuneCountInString(v)
+	if s.MinLength != nil && n < *s.MinLength {
+		return fieldError(path, "should be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		return fieldError(path, "should be at most %d characters", *s.MaxLength)
+	}
+
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			return fieldError(path, "should be an RFC 3339 date-time")
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) validateNumber(path string, v float64) error {
+	if s.Minimum != nil && v < *s.Minimum {
+		return fieldError(path, "should be at least %v", *s.Minimum)
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		return fieldError(path, "should be at most %v", *s.Maximum)
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(path string, v []any) error {
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		return fieldError(path, "should have at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		return fieldError(path, "should have at most %d items", *s.MaxItems)
+	}
+
+	for i, item := range v {
+		if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(path string, v map[string]any) error {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			return fieldError(join(path, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := s.Properties[name]
+		switch {
+		case ok:
+		case s.AdditionalProperties.Forbidden:
+			return fieldError(join(path, name), "is not allowed")
+		default:
+			prop = s.AdditionalProperties.Schema
+		}
+
+		if err := prop.validate(join(path, name), v[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func enumString(enum []any) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		b, _ := json.Marshal(e)
+		values[i] = string(b)
+	}
+	return strings.Join(values, ", ")
+}
+
+// FieldError is a value that does not match its schema.
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + " " + e.Message
+}
+
+func fieldError(path, format string, args ...any) error {
+	return &FieldError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
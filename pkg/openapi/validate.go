@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxBodySize bounds the JSON request bodies that are validated, larger ones
+// are left to the handler limits.
+const maxBodySize = 10 << 20
+
+var (
+	ErrMissingBody        = errors.New("body is required")
+	ErrUnknownContentType = errors.New("content type is not supported")
+	ErrUnexpectedStatus   = errors.New("status is not documented")
+	ErrInvalidJSON        = errors.New("body is not valid JSON")
+)
+
+// RequestError is a part of a request or response that does not match the
+// document.
+type RequestError struct {
+	In   string
+	Name string
+	Err  error
+}
+
+func (e *RequestError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: %s", e.In, e.Err)
+	}
+	return fmt.Sprintf("%s parameter %q: %s", e.In, e.Name, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateRequest checks the parameters and the body of the request. The body
+// is read and put back for the handler.
+func (m *Match) ValidateRequest(r *http.Request) error {
+	for _, p := range m.parameters {
+		if err := m.validateParameter(r, p); err != nil {
+			return &RequestError{In: p.In, Name: p.Name, Err: err}
+		}
+	}
+
+	if m.Operation.RequestBody == nil {
+		return nil
+	}
+
+	if err := validateRequestBody(r, m.Operation.RequestBody); err != nil {
+		return &RequestError{In: "request body", Err: err}
+	}
+
+	return nil
+}
+
+func (m *Match) validateParameter(r *http.Request, p *Parameter) error {
+	var values []string
+	switch p.In {
+	case "path":
+		if v, ok := m.PathParams[p.Name]; ok {
+			values = []string{v}
+		}
+	case "query":
+		values = r.URL.Query()[p.Name]
+	case "header":
+		values = r.Header.Values(p.Name)
+	default:
+		return nil
+	}
+
+	if len(values) == 0 {
+		if p.Required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+
+	v, err := parseParameter(p.Schema, values)
+	if err != nil {
+		return err
+	}
+
+	return p.Schema.Validate(v)
+}
+
+// parseParameter turns the raw values into what encoding/json would have
+// produced for the schema, arrays are taken from repeated values.
+func parseParameter(s *Schema, values []string) (any, error) {
+	if s == nil {
+		return values[0], nil
+	}
+
+	if s.Type.has("array") {
+		items := make([]any, len(values))
+		for i, raw := range values {
+			v, err := parseScalar(s.Items, raw)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return items, nil
+	}
+
+	return parseScalar(s, values[0])
+}
+
+func parseScalar(s *Schema, raw string) (any, error) {
+	if s == nil {
+		return raw, nil
+	}
+
+	switch {
+	case s.Type.has("integer"), s.Type.has("number"):
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("should be %s", strings.Join(s.Type, " or "))
+		}
+		return v, nil
+	case s.Type.has("boolean"):
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("should be boolean")
+		}
+		return v, nil
+	}
+
+	return raw, nil
+}
+
+func validateRequestBody(r *http.Request, rb *RequestBody) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		if rb.Required {
+			return ErrMissingBody
+		}
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "" && len(rb.Content) == 1 {
+		// clients that do not say are assumed to send the only media type
+		for mediaType = range rb.Content {
+		}
+	}
+
+	mt, ok := lookupContent(rb.Content, mediaType)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownContentType, mediaType)
+	}
+
+	if !isJSON(mediaType) {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return err
+	}
+
+	if len(body) > maxBodySize {
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return nil
+	}
+	r.Body = readCloser{bytes.NewReader(body), r.Body}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if rb.Required {
+			return ErrMissingBody
+		}
+		return nil
+	}
+
+	return validateJSON(mt.Schema, body)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ValidateResponse checks a response written for the request.
+func (m *Match) ValidateResponse(status int, header http.Header, body []byte) error {
+	res, ok := m.Operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		res, ok = m.Operation.Responses["default"]
+	}
+	if !ok {
+		return &RequestError{In: "response", Err: fmt.Errorf("%w: %d", ErrUnexpectedStatus, status)}
+	}
+
+	if len(body) == 0 || len(res.Content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	mt, ok := lookupContent(res.Content, mediaType)
+	if !ok {
+		return &RequestError{In: "response", Err: fmt.Errorf("%w: %q", ErrUnknownContentType, mediaType)}
+	}
+
+	if !isJSON(mediaType) {
+		return nil
+	}
+
+	if err := validateJSON(mt.Schema, body); err != nil {
+		return &RequestError{In: "response", Err: err}
+	}
+
+	return nil
+}
+
+// lookupContent finds the media type, falling back to ranges like image/*
+// and */*.
+func lookupContent(content map[string]*MediaType, mediaType string) (*MediaType, bool) {
+	if mt, ok := content[mediaType]; ok {
+		return mt, true
+	}
+
+	if i := strings.IndexByte(mediaType, '/'); i > 0 {
+		if mt, ok := content[mediaType[:i]+"/*"]; ok {
+			return mt, true
+		}
+	}
+
+	mt, ok := content["*/*"]
+	return mt, ok
+}
+
+func validateJSON(s *Schema, body []byte) error {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return ErrInvalidJSON
+	}
+
+	return s.Validate(v)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,105 @@
+// Package ratelimit implements token bucket rate limiting with pluggable bucket stores.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidLimit = errors.New(`invalid limit, expected "<count>/<period>", e.g. "60/1m"`)
+)
+
+// Limit allows Count requests per Period. The bucket holds up to Count tokens
+// and refills continuously, so bursts of up to Count requests are allowed.
+// The zero Limit disables limiting.
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+func (l Limit) Enabled() bool {
+	return l.Count > 0 && l.Period > 0
+}
+
+// rate is the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Count) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return ""
+	}
+	return strconv.Itoa(l.Count) + "/" + l.Period.String()
+}
+
+// UnmarshalText parses limits like "60/1m" or "10/1s", an empty value disables the limit.
+func (l *Limit) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*l = Limit{}
+		return nil
+	}
+
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return ErrInvalidLimit
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidLimit, s)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidLimit, s)
+	}
+
+	*l = Limit{Count: n, Period: d}
+	return nil
+}
+
+// Result describes the bucket state after taking a token.
+type Result struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left.
+	Remaining int
+	// RetryAfter is how long to wait for the next token, zero when allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// take applies one request to a bucket holding tokens, last refilled at updatedAt.
+// It returns the new token count along with the result.
+func take(limit Limit, tokens float64, updatedAt, now time.Time) (float64, Result) {
+	capacity := float64(limit.Count)
+	rate := limit.rate()
+
+	if elapsed := now.Sub(updatedAt).Seconds(); elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed*rate)
+	}
+
+	var res Result
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = seconds((capacity - tokens) / rate)
+
+	return tokens, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
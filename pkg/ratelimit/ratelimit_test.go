@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+func TestLimit_UnmarshalText(t *testing.T) {
+	var l Limit
+
+	require.NoError(t, l.UnmarshalText([]byte("60/1m")))
+	require.Equal(t, Limit{Count: 60, Period: time.Minute}, l)
+
+	require.NoError(t, l.UnmarshalText([]byte("")))
+	require.False(t, l.Enabled())
+
+	require.ErrorIs(t, l.UnmarshalText([]byte("60")), ErrInvalidLimit)
+	require.ErrorIs(t, l.UnmarshalText([]byte("x/1m")), ErrInvalidLimit)
+	require.ErrorIs(t, l.UnmarshalText([]byte("1/0s")), ErrInvalidLimit)
+}
+
+func TestStores(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+
+	memory := NewMemoryStore()
+	memory.now = clock
+
+	redisStore := NewRedisStore(client, "test:")
+	redisStore.now = clock
+
+	stores := map[string]store{
+		"memory": memory,
+		"redis":  redisStore,
+	}
+
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			limit := Limit{Count: 2, Period: 2 * time.Second}
+			key := "bucket-" + name
+
+			res, err := s.Take(ctx, key, limit)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+			require.Equal(t, 1, res.Remaining)
+
+			res, err = s.Take(ctx, key, limit)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+			require.Equal(t, 0, res.Remaining)
+			require.Equal(t, 2*time.Second, res.Reset)
+
+			res, err = s.Take(ctx, key, limit)
+			require.NoError(t, err)
+			require.False(t, res.Allowed)
+			require.Equal(t, time.Second, res.RetryAfter)
+
+			// one token refills per second
+			now = now.Add(time.Second)
+
+			res, err = s.Take(ctx, key, limit)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+
+			res, err = s.Take(ctx, "other-"+name, limit)
+			require.NoError(t, err)
+			require.True(t, res.Allowed)
+		})
+	}
+}
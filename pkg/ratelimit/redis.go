@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"math"
+	"time"
+)
+
+// takeScript is the same algorithm as take, run atomically next to the bucket.
+// KEYS[1] bucket key, ARGV: capacity, refill rate per ms, now in ms.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = capacity
+  ts = now
+end
+
+local elapsed = now - ts
+if elapsed > 0 then
+  tokens = math.min(capacity, tokens + elapsed * rate)
+end
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((capacity - tokens) / rate) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis (or a compatible server), so limits are
+// shared by all replicas.
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+	now    func() time.Time
+}
+
+func NewRedisStore(client redis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	const op = "news-crud.pkg.ratelimit.RedisStore.Take"
+
+	ratePerMs := limit.rate() / 1000
+
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key},
+		limit.Count, ratePerMs, s.now().UnixMilli(),
+	).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("%s: unexpected script result %v", op, values)
+	}
+
+	allowed, _ := values[0].(int64)
+
+	var tokens float64
+	if s, ok := values[1].(string); ok {
+		if _, err = fmt.Sscan(s, &tokens); err != nil {
+			return Result{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	res := Result{
+		Allowed:   allowed == 1,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Count) - tokens) / limit.rate()),
+	}
+	if !res.Allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+
+	return res, nil
+}
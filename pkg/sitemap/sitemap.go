@@ -0,0 +1,143 @@
+// Package sitemap writes sitemaps and sitemap indexes as defined at
+// sitemaps.org, including the Google News extension. Entries are encoded as they
+// are added, so a sitemap can be streamed without holding it in memory.
+package sitemap
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const (
+	// MaxURLs is the number of URLs a single sitemap may hold.
+	MaxURLs = 50000
+	// MaxNewsURLs is the number of URLs a news sitemap may hold.
+	MaxNewsURLs = 1000
+)
+
+const (
+	namespace     = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	newsNamespace = "http://www.google.com/schemas/sitemap-news/0.9"
+)
+
+type URL struct {
+	Loc     string
+	LastMod time.Time
+	News    *News
+}
+
+// News marks the URL as a news article.
+type News struct {
+	PublicationName string
+	Language        string
+	Title           string
+	PublicationDate time.Time
+}
+
+// Sitemap is an entry of a sitemap index.
+type Sitemap struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type Writer struct {
+	w   io.Writer
+	enc *xml.Encoder
+	end string
+}
+
+// NewURLSet starts a sitemap, news enables the Google News namespace.
+func NewURLSet(w io.Writer, news bool) (*Writer, error) {
+	start := `<urlset xmlns="` + namespace + `"`
+	if news {
+		start += ` xmlns:news="` + newsNamespace + `"`
+	}
+
+	return newWriter(w, start+">", "</urlset>")
+}
+
+// NewIndex starts a sitemap index.
+func NewIndex(w io.Writer) (*Writer, error) {
+	return newWriter(w, `<sitemapindex xmlns="`+namespace+`">`, "</sitemapindex>")
+}
+
+func newWriter(w io.Writer, start, end string) (*Writer, error) {
+	if _, err := io.WriteString(w, xml.Header+start+"\n"); err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w, enc: xml.NewEncoder(w), end: end}, nil
+}
+
+type xmlURL struct {
+	XMLName xml.Name `xml:"url"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+	News    *xmlNews `xml:"news:news,omitempty"`
+}
+
+type xmlNews struct {
+	Publication struct {
+		Name     string `xml:"news:name"`
+		Language string `xml:"news:language"`
+	} `xml:"news:publication"`
+	PublicationDate string `xml:"news:publication_date"`
+	Title           string `xml:"news:title"`
+}
+
+type xmlSitemap struct {
+	XMLName xml.Name `xml:"sitemap"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+}
+
+func (sw *Writer) AddURL(u URL) error {
+	entry := xmlURL{Loc: u.Loc, LastMod: formatTime(u.LastMod)}
+
+	if u.News != nil {
+		entry.News = &xmlNews{
+			PublicationDate: formatTime(u.News.PublicationDate),
+			Title:           u.News.Title,
+		}
+		entry.News.Publication.Name = u.News.PublicationName
+		entry.News.Publication.Language = u.News.Language
+	}
+
+	return sw.encode(entry)
+}
+
+func (sw *Writer) AddSitemap(s Sitemap) error {
+	return sw.encode(xmlSitemap{Loc: s.Loc, LastMod: formatTime(s.LastMod)})
+}
+
+// Close ends the document, it does not close the underlying writer.
+func (sw *Writer) Close() error {
+	if err := sw.enc.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(sw.w, sw.end+"\n")
+	return err
+}
+
+func (sw *Writer) encode(v any) error {
+	if err := sw.enc.Encode(v); err != nil {
+		return err
+	}
+
+	// keep the output flowing instead of buffering the whole document
+	if err := sw.enc.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(sw.w, "\n")
+	return err
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
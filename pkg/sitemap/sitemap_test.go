@@ -0,0 +1,52 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestURLSet(t *testing.T) {
+	var buf bytes.Buffer
+
+	sw, err := NewURLSet(&buf, true)
+	require.NoError(t, err)
+
+	require.NoError(t, sw.AddURL(URL{
+		Loc:     "https://example.com/posts/1?a=1&b=2",
+		LastMod: time.Date(2024, 5, 1, 13, 0, 0, 0, time.FixedZone("EEST", 3*60*60)),
+	}))
+	require.NoError(t, sw.AddURL(URL{
+		Loc: "https://example.com/posts/2",
+		News: &News{
+			PublicationName: "News",
+			Language:        "en",
+			Title:           "Cats & dogs",
+			PublicationDate: time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC),
+		},
+	}))
+	require.NoError(t, sw.Close())
+
+	require.Equal(t, xml.Header+`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+<url><loc>https://example.com/posts/1?a=1&amp;b=2</loc><lastmod>2024-05-01T10:00:00Z</lastmod></url>
+<url><loc>https://example.com/posts/2</loc><news:news><news:publication><news:name>News</news:name><news:language>en</news:language></news:publication><news:publication_date>2024-05-02T08:00:00Z</news:publication_date><news:title>Cats &amp; dogs</news:title></news:news></url>
+</urlset>
+`, buf.String())
+}
+
+func TestIndex(t *testing.T) {
+	var buf bytes.Buffer
+
+	sw, err := NewIndex(&buf)
+	require.NoError(t, err)
+
+	require.NoError(t, sw.AddSitemap(Sitemap{Loc: "https://example.com/sitemaps/posts-1.xml"}))
+	require.NoError(t, sw.Close())
+
+	require.Equal(t, xml.Header+`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<sitemap><loc>https://example.com/sitemaps/posts-1.xml</loc></sitemap>
+</sitemapindex>
+`, buf.String())
+}
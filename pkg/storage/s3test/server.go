@@ -0,0 +1,119 @@
+// Package s3test runs an in-memory stand-in for an S3-compatible object store.
+// It verifies request signatures and supports putting, getting and deleting
+// objects, addressed path-style.
+package s3test
+
+import (
+	"github.com/ananaslegend/news-crud/pkg/storage/sigv4"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const Region = "us-east-1"
+
+type object struct {
+	body        []byte
+	contentType string
+}
+
+type Server struct {
+	server *httptest.Server
+	bucket string
+	creds  sigv4.Credentials
+
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+func NewServer(bucket, accessKey, secretKey string) *Server {
+	s := &Server{
+		bucket:  bucket,
+		creds:   sigv4.Credentials{AccessKey: accessKey, SecretKey: secretKey},
+		objects: make(map[string]object),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Object returns the stored body and content type of the key.
+func (s *Server) Object(key string) ([]byte, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[key]
+	return obj.body, obj.contentType, ok
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if !s.verify(r) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.bucket+"/")
+	if !ok || key == "" {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = object{body: body, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		obj, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.body)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) verify(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+
+	var extra []string
+	if _, signed, ok := strings.Cut(auth, "SignedHeaders="); ok {
+		signed, _, _ = strings.Cut(signed, ",")
+		for _, name := range strings.Split(signed, ";") {
+			if name != "host" && !strings.HasPrefix(name, "x-amz-") {
+				extra = append(extra, name)
+			}
+		}
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if len(amzDate) != len(sigv4.TimeFormat) {
+		return false
+	}
+
+	want := sigv4.Authorization(r, s.creds, Region, "s3", r.Header.Get("X-Amz-Content-Sha256"), amzDate, extra...)
+	return auth == want
+}
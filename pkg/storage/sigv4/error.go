@@ -0,0 +1,5 @@
+package sigv4
+
+import "errors"
+
+var errInvalidEscape = errors.New("invalid percent encoding")
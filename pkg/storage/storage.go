@@ -0,0 +1,41 @@
+// Package storage keeps binary objects, such as uploaded media, by key.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Storage is an object store. Keys are slash separated paths without "." or
+// ".." segments.
+type Storage interface {
+	// Put stores size bytes read from r under the key, replacing any object stored before.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func validKey(key string) bool {
+	if key == "" || key[0] == '/' {
+		return false
+	}
+
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		if i < len(key) && key[i] != '/' {
+			continue
+		}
+		if segment := key[start:i]; segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+		start = i + 1
+	}
+
+	return true
+}
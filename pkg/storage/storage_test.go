@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/ananaslegend/news-crud/pkg/storage"
+	"github.com/ananaslegend/news-crud/pkg/storage/s3test"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func testStorage(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	body := []byte("\x89PNG not really")
+
+	require.NoError(t, s.Put(ctx, "ab/cd.png", bytes.NewReader(body), int64(len(body)), "image/png"))
+
+	r, err := s.Get(ctx, "ab/cd.png")
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, body, got)
+
+	require.NoError(t, s.Delete(ctx, "ab/cd.png"))
+	require.NoError(t, s.Delete(ctx, "ab/cd.png"))
+
+	_, err = s.Get(ctx, "ab/cd.png")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	for _, key := range []string{"", "/abs", "../escape", "a//b", "a/./b"} {
+		_, err = s.Get(ctx, key)
+		require.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	s, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	testStorage(t, s)
+}
+
+func TestS3(t *testing.T) {
+	server := s3test.NewServer("media", "access", "secret")
+	defer server.Close()
+
+	s, err := storage.NewS3(storage.S3Config{
+		Endpoint:  server.URL(),
+		Region:    s3test.Region,
+		Bucket:    "media",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}, http.DefaultClient)
+	require.NoError(t, err)
+
+	testStorage(t, s)
+
+	require.NoError(t, s.Put(context.Background(), "x.jpg", bytes.NewReader([]byte("jpg")), 3, "image/jpeg"))
+	body, contentType, ok := server.Object("x.jpg")
+	require.True(t, ok)
+	require.Equal(t, []byte("jpg"), body)
+	require.Equal(t, "image/jpeg", contentType)
+}
+
+func TestS3_WrongCredentials(t *testing.T) {
+	server := s3test.NewServer("media", "access", "secret")
+	defer server.Close()
+
+	s, err := storage.NewS3(storage.S3Config{
+		Endpoint:  server.URL(),
+		Region:    s3test.Region,
+		Bucket:    "media",
+		AccessKey: "access",
+		SecretKey: "wrong",
+	}, http.DefaultClient)
+	require.NoError(t, err)
+
+	err = s.Put(context.Background(), "x.jpg", bytes.NewReader([]byte("jpg")), 3, "image/jpeg")
+	require.ErrorContains(t, err, "403")
+}
@@ -0,0 +1,265 @@
+// Package wxr reads WordPress eXtended RSS, the export format of WordPress.
+// Items are decoded one at a time, so exports of any size can be streamed.
+// Every WXR version is accepted, elements are matched by local name.
+package wxr
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrNotWXR = errors.New("not a WordPress export")
+
+// ItemError is an item that was read but can not be used. Reading may go on
+// with the next item.
+type ItemError struct {
+	ID  string
+	Err error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %s: %v", e.ID, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// dateLayout is the layout of wp:post_date and the like, zeros mean never.
+const dateLayout = "2006-01-02 15:04:05"
+
+// Author is an author declared in the channel, items refer to it by login.
+type Author struct {
+	Login       string
+	Email       string
+	DisplayName string
+}
+
+// Item is a post, page, attachment or any other post type of the export.
+type Item struct {
+	// ID is wp:post_id, unique within the export.
+	ID      string
+	Type    string
+	Status  string
+	Title   string
+	Link    string
+	Creator string
+	Content string
+	Excerpt string
+	// Date and Modified are in UTC. The GMT dates are used when set, the
+	// local ones, read as UTC, otherwise. They are zero for drafts that
+	// were never dated.
+	Date       time.Time
+	Modified   time.Time
+	Categories []Category
+}
+
+// Category is a term of the item, Domain is its taxonomy: "category",
+// "post_tag" or a custom one.
+type Category struct {
+	Domain   string
+	Nicename string
+	Name     string
+}
+
+// Terms returns the names of the terms of the item in the taxonomy.
+func (i Item) Terms(domain string) []string {
+	var terms []string
+	for _, c := range i.Categories {
+		if c.Domain == domain {
+			terms = append(terms, c.Name)
+		}
+	}
+	return terms
+}
+
+type Reader struct {
+	d       *xml.Decoder
+	started bool
+	authors map[string]Author
+}
+
+func NewReader(r io.Reader) *Reader {
+	d := xml.NewDecoder(r)
+	// exports are declared UTF-8 but often carry stray bytes, keep them
+	d.Strict = false
+	d.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	return &Reader{d: d, authors: make(map[string]Author)}
+}
+
+// Authors returns the authors read so far keyed by login. WordPress writes
+// them before the items.
+func (r *Reader) Authors() map[string]Author {
+	return r.authors
+}
+
+// Next returns the next item, io.EOF after the last one. An *ItemError
+// comes with the item it is about.
+func (r *Reader) Next() (Item, error) {
+	for {
+		tok, err := r.d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) && !r.started {
+				return Item{}, ErrNotWXR
+			}
+			return Item{}, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if !r.started {
+			if start.Name.Local != "rss" {
+				return Item{}, fmt.Errorf("%w: root element is %s", ErrNotWXR, start.Name.Local)
+			}
+			r.started = true
+			continue
+		}
+
+		switch {
+		case start.Name.Local == "author" && isWP(start.Name.Space):
+			var a Author
+			if err = r.decodeAuthor(start, &a); err != nil {
+				return Item{}, err
+			}
+			r.authors[a.Login] = a
+		case start.Name.Local == "item":
+			return r.decodeItem(start)
+		}
+	}
+}
+
+func (r *Reader) decodeAuthor(start xml.StartElement, a *Author) error {
+	return r.children(start, func(child xml.StartElement) error {
+		var err error
+		switch child.Name.Local {
+		case "author_login":
+			a.Login, err = r.text(child)
+		case "author_email":
+			a.Email, err = r.text(child)
+		case "author_display_name":
+			a.DisplayName, err = r.text(child)
+		default:
+			err = r.d.Skip()
+		}
+		return err
+	})
+}
+
+func (r *Reader) decodeItem(start xml.StartElement) (Item, error) {
+	var (
+		item                  Item
+		date, dateGMT         string
+		modified, modifiedGMT string
+	)
+
+	err := r.children(start, func(child xml.StartElement) error {
+		var err error
+		switch name := child.Name.Local; {
+		case name == "encoded" && strings.Contains(child.Name.Space, "excerpt"):
+			item.Excerpt, err = r.text(child)
+		case name == "encoded":
+			item.Content, err = r.text(child)
+		case name == "title":
+			item.Title, err = r.text(child)
+		case name == "link":
+			item.Link, err = r.text(child)
+		case name == "creator":
+			item.Creator, err = r.text(child)
+		case name == "post_id":
+			item.ID, err = r.text(child)
+		case name == "post_type":
+			item.Type, err = r.text(child)
+		case name == "status":
+			item.Status, err = r.text(child)
+		case name == "post_date":
+			date, err = r.text(child)
+		case name == "post_date_gmt":
+			dateGMT, err = r.text(child)
+		case name == "post_modified":
+			modified, err = r.text(child)
+		case name == "post_modified_gmt":
+			modifiedGMT, err = r.text(child)
+		case name == "category":
+			c := Category{}
+			for _, attr := range child.Attr {
+				switch attr.Name.Local {
+				case "domain":
+					c.Domain = attr.Value
+				case "nicename":
+					c.Nicename = attr.Value
+				}
+			}
+			if c.Name, err = r.text(child); err == nil {
+				item.Categories = append(item.Categories, c)
+			}
+		default:
+			err = r.d.Skip()
+		}
+		return err
+	})
+	if err != nil {
+		return Item{}, err
+	}
+
+	if item.Date, err = parseDate(dateGMT, date); err != nil {
+		return item, &ItemError{ID: item.ID, Err: err}
+	}
+	if item.Modified, err = parseDate(modifiedGMT, modified); err != nil {
+		return item, &ItemError{ID: item.ID, Err: err}
+	}
+
+	return item, nil
+}
+
+// children calls fn for every child element of start, fn has to consume
+// the child.
+func (r *Reader) children(start xml.StartElement, fn func(child xml.StartElement) error) error {
+	for {
+		tok, err := r.d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err = fn(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if t.Name.Local == start.Name.Local {
+				return nil
+			}
+		}
+	}
+}
+
+func (r *Reader) text(start xml.StartElement) (string, error) {
+	var s string
+	err := r.d.DecodeElement(&s, &start)
+	return strings.TrimSpace(s), err
+}
+
+// parseDate reads the first of the dates that is set.
+func parseDate(dates ...string) (time.Time, error) {
+	for _, d := range dates {
+		if d == "" || strings.HasPrefix(d, "0000-00-00") {
+			continue
+		}
+		return time.Parse(dateLayout, d)
+	}
+	return time.Time{}, nil
+}
+
+func isWP(space string) bool {
+	return strings.Contains(space, "wordpress.org/export")
+}
@@ -0,0 +1,128 @@
+package wxr
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>Archive</title>
+	<wp:wxr_version>1.2</wp:wxr_version>
+	<wp:author>
+		<wp:author_id>1</wp:author_id>
+		<wp:author_login><![CDATA[jane]]></wp:author_login>
+		<wp:author_email><![CDATA[jane@example.com]]></wp:author_email>
+		<wp:author_display_name><![CDATA[Jane Doe]]></wp:author_display_name>
+	</wp:author>
+	<wp:category>
+		<wp:term_id>3</wp:term_id>
+		<wp:category_nicename><![CDATA[city]]></wp:category_nicename>
+	</wp:category>
+	<item>
+		<title>Tom &amp; Jerry</title>
+		<link>https://example.com/2024/05/tom-jerry/</link>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[<p>Hello <b>world</b></p>]]></content:encoded>
+		<excerpt:encoded><![CDATA[Short]]></excerpt:encoded>
+		<wp:post_id>12</wp:post_id>
+		<wp:post_date><![CDATA[2024-05-01 12:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2024-05-01 10:00:00]]></wp:post_date_gmt>
+		<wp:post_modified><![CDATA[2024-05-02 12:30:00]]></wp:post_modified>
+		<wp:post_modified_gmt><![CDATA[2024-05-02 10:30:00]]></wp:post_modified_gmt>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="city"><![CDATA[City]]></category>
+		<category domain="post_tag" nicename="cartoons"><![CDATA[Cartoons]]></category>
+		<wp:postmeta>
+			<wp:meta_key><![CDATA[_edit_last]]></wp:meta_key>
+			<wp:meta_value><![CDATA[1]]></wp:meta_value>
+		</wp:postmeta>
+		<wp:comment>
+			<wp:comment_id>5</wp:comment_id>
+			<wp:comment_content><![CDATA[Nice]]></wp:comment_content>
+		</wp:comment>
+	</item>
+	<item>
+		<title>Draft</title>
+		<dc:creator><![CDATA[jane]]></dc:creator>
+		<content:encoded><![CDATA[]]></content:encoded>
+		<wp:post_id>13</wp:post_id>
+		<wp:post_date><![CDATA[2024-05-03 08:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[0000-00-00 00:00:00]]></wp:post_date_gmt>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[page]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestReader(t *testing.T) {
+	r := NewReader(strings.NewReader(testExport))
+
+	item, err := r.Next()
+	require.NoError(t, err)
+	require.Equal(t, Item{
+		ID:       "12",
+		Type:     "post",
+		Status:   "publish",
+		Title:    "Tom & Jerry",
+		Link:     "https://example.com/2024/05/tom-jerry/",
+		Creator:  "jane",
+		Content:  "<p>Hello <b>world</b></p>",
+		Excerpt:  "Short",
+		Date:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Modified: time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC),
+		Categories: []Category{
+			{Domain: "category", Nicename: "city", Name: "City"},
+			{Domain: "post_tag", Nicename: "cartoons", Name: "Cartoons"},
+		},
+	}, item)
+	require.Equal(t, []string{"Cartoons"}, item.Terms("post_tag"))
+
+	require.Equal(t, map[string]Author{
+		"jane": {Login: "jane", Email: "jane@example.com", DisplayName: "Jane Doe"},
+	}, r.Authors())
+
+	item, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "13", item.ID)
+	require.Equal(t, "page", item.Type)
+	require.Equal(t, time.Date(2024, 5, 3, 8, 0, 0, 0, time.UTC), item.Date, "local date when there is no GMT one")
+	require.True(t, item.Modified.IsZero())
+
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestReader_NotWXR(t *testing.T) {
+	for _, input := range []string{"", `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`} {
+		_, err := NewReader(strings.NewReader(input)).Next()
+		require.ErrorIs(t, err, ErrNotWXR, "input %q", input)
+	}
+}
+
+func TestReader_BadDate(t *testing.T) {
+	r := NewReader(strings.NewReader(`<rss><channel>
+		<item><post_id>1</post_id><post_date>yesterday</post_date></item>
+		<item><post_id>2</post_id><post_date>2024-05-01 10:00:00</post_date></item>
+	</channel></rss>`))
+
+	item, err := r.Next()
+	var itemErr *ItemError
+	require.True(t, errors.As(err, &itemErr))
+	require.Equal(t, "1", itemErr.ID)
+	require.Equal(t, "1", item.ID)
+
+	item, err = r.Next()
+	require.NoError(t, err, "reading goes on after a bad item")
+	require.Equal(t, "2", item.ID)
+}